@@ -6,11 +6,14 @@ require (
 	github.com/fxamacker/cbor/v2 v2.3.0
 	github.com/go-chi/chi v1.5.4
 	github.com/go-chi/cors v1.2.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/graph-gophers/graphql-go v1.1.0
+	github.com/lib/pq v1.10.2
 	github.com/savaki/zapctx v0.0.0-20201018205532-7b483125a976
 	github.com/segmentio/ksuid v1.0.4
 	github.com/tj/assert v0.0.3
 	github.com/urfave/cli/v2 v2.3.0
+	go.etcd.io/bbolt v1.3.6
 	go.uber.org/zap v1.19.0
 	golang.org/x/crypto v0.0.0-20210817164053-32db794688a5
 )
@@ -0,0 +1,192 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// AssetNameFormat identifies how cardano-cli renders and accepts asset names
+type AssetNameFormat int
+
+const (
+	// AssetNameUTF8 renders asset names as text; cardano-cli 1.31 and earlier
+	AssetNameUTF8 AssetNameFormat = iota
+
+	// AssetNameHex renders asset names as hex; cardano-cli 1.32 and later
+	AssetNameHex
+)
+
+// hrpAssetFingerprint is the bech32 prefix for CIP-14 asset fingerprints
+const hrpAssetFingerprint = "asset"
+
+var reAssetQuantity = regexp.MustCompile(`^\s*(-?\d+)\s+([a-f0-9]{56})(?:\.([a-fA-F0-9]*))?\s*$`)
+
+// ParseAssetName converts a user supplied asset name into the hex encoded form
+// used internally.  Names prefixed with 0x are treated as hex, allowing binary
+// names, while all other names are treated as utf-8 text.
+func ParseAssetName(s string) (string, error) {
+	var name []byte
+	if strings.HasPrefix(s, "0x") {
+		data, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return "", fmt.Errorf("invalid asset name, %v: %w", s, err)
+		}
+		name = data
+	} else {
+		name = []byte(s)
+	}
+
+	if n := len(name); n > maxAssetNameBytes {
+		return "", fmt.Errorf("invalid asset name, %v: exceeds %v bytes, %v", s, maxAssetNameBytes, n)
+	}
+	return hex.EncodeToString(name), nil
+}
+
+// AssetNameUTF8 returns the asset name as printable text, if possible
+func (a Asset) AssetNameUTF8() (string, bool) {
+	data, err := hex.DecodeString(a.AssetName)
+	if err != nil || !utf8.Valid(data) {
+		return "", false
+	}
+
+	s := string(data)
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return "", false
+		}
+	}
+	return s, true
+}
+
+// DisplayName returns the utf-8 asset name when printable and the hex name otherwise
+func (a Asset) DisplayName() string {
+	if s, ok := a.AssetNameUTF8(); ok {
+		return s
+	}
+	return a.AssetName
+}
+
+// Fingerprint returns the CIP-14 asset fingerprint e.g. asset1rjklcrnsdzqp65wjgrg55sy9723kw09mlgvlc3
+func (a Asset) Fingerprint() string {
+	policyID, err := hex.DecodeString(a.PolicyId)
+	if err != nil {
+		return ""
+	}
+	assetName, err := hex.DecodeString(a.AssetName)
+	if err != nil {
+		return ""
+	}
+
+	h, err := blake2b.New(20, nil)
+	if err != nil {
+		return ""
+	}
+	h.Write(policyID)
+	h.Write(assetName)
+
+	fingerprint, err := Bech32Encode(hrpAssetFingerprint, h.Sum(nil))
+	if err != nil {
+		return ""
+	}
+	return fingerprint
+}
+
+// MatchAsset returns a func that matches assets identified by either
+// policyId.assetName, where assetName is hex or utf-8, or by the CIP-14
+// fingerprint, asset1...
+func MatchAsset(id string) func(asset *Asset) bool {
+	if strings.HasPrefix(id, hrpAssetFingerprint+"1") {
+		return func(asset *Asset) bool { return asset != nil && asset.Fingerprint() == id }
+	}
+
+	parts := strings.SplitN(id, ".", 2)
+	if len(parts) != 2 {
+		return func(asset *Asset) bool { return false }
+	}
+
+	var (
+		policyID      = parts[0]
+		assetNameHex  = strings.ToLower(parts[1])
+		assetNameUTF8 = hex.EncodeToString([]byte(parts[1]))
+	)
+	return func(asset *Asset) bool {
+		return asset != nil && asset.PolicyId == policyID && (asset.AssetName == assetNameHex || asset.AssetName == assetNameUTF8)
+	}
+}
+
+// parseCLIAssetName converts an asset name as rendered by cardano-cli to hex
+func parseCLIAssetName(s string, format AssetNameFormat) string {
+	if format == AssetNameHex {
+		return strings.ToLower(s)
+	}
+	return hex.EncodeToString([]byte(s))
+}
+
+// formatCLIAssetName converts a hex encoded asset name into the form accepted by cardano-cli
+func formatCLIAssetName(assetNameHex string, format AssetNameFormat) (string, error) {
+	if format == AssetNameHex {
+		return assetNameHex, nil
+	}
+
+	data, err := hex.DecodeString(assetNameHex)
+	if err != nil {
+		return "", fmt.Errorf("invalid asset name, %v: %w", assetNameHex, err)
+	}
+	for _, r := range string(data) {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return "", fmt.Errorf("asset name, 0x%v, requires a cardano-cli that supports hex asset names", assetNameHex)
+		}
+	}
+	return string(data), nil
+}
+
+// formatCLIValue converts a value of the form "qty policyId.assetNameHex+..."
+// into the form accepted by cardano-cli
+func formatCLIValue(value string, format AssetNameFormat) (string, error) {
+	if format == AssetNameHex {
+		return value, nil
+	}
+
+	parts := strings.Split(value, "+")
+	for i, part := range parts {
+		match := reAssetQuantity.FindStringSubmatch(part)
+		if len(match) != 4 || match[3] == "" {
+			continue
+		}
+
+		assetName, err := formatCLIAssetName(match[3], format)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = fmt.Sprintf("%v %v.%v", match[1], match[2], assetName)
+	}
+	return strings.Join(parts, "+"), nil
+}
@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestAsset_Fingerprint(t *testing.T) {
+	testCases := map[string]struct {
+		PolicyID    string
+		AssetName   string
+		Fingerprint string
+	}{
+		"empty name": {
+			PolicyID:    "7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373",
+			Fingerprint: "asset1rjklcrnsdzqp65wjgrg55sy9723kw09mlgvlc3",
+		},
+		"other policy": {
+			PolicyID:    "7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc37e",
+			Fingerprint: "asset1nl0puwxmhas8fawxp8nx4e2q3wekg969n2auw3",
+		},
+		"with name": {
+			PolicyID:    "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209",
+			AssetName:   "504154415445",
+			Fingerprint: "asset1hv4p5tv2a837mzqrst04d0dcptdjmluqvdx9k3",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			asset := Asset{PolicyId: tc.PolicyID, AssetName: tc.AssetName}
+			assert.Equal(t, tc.Fingerprint, asset.Fingerprint())
+		})
+	}
+}
+
+func TestParseAssetName(t *testing.T) {
+	got, err := ParseAssetName("test")
+	assert.Nil(t, err)
+	assert.Equal(t, "74657374", got)
+
+	got, err = ParseAssetName("0x00ff")
+	assert.Nil(t, err)
+	assert.Equal(t, "00ff", got)
+
+	_, err = ParseAssetName("0xjunk")
+	assert.NotNil(t, err)
+
+	_, err = ParseAssetName("abcdefghijklmnopqrstuvwxyz0123456789")
+	assert.NotNil(t, err)
+}
+
+func TestAsset_AssetNameUTF8(t *testing.T) {
+	s, ok := Asset{AssetName: "74657374"}.AssetNameUTF8()
+	assert.True(t, ok)
+	assert.Equal(t, "test", s)
+
+	_, ok = Asset{AssetName: "00ff"}.AssetNameUTF8()
+	assert.False(t, ok)
+	assert.Equal(t, "00ff", Asset{AssetName: "00ff"}.DisplayName())
+}
+
+func Test_formatCLIValue(t *testing.T) {
+	const policyID = "5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c"
+
+	value := "100 " + policyID + ".74657374+-5 " + policyID + ".6f74686572"
+	got, err := formatCLIValue(value, AssetNameUTF8)
+	assert.Nil(t, err)
+	assert.Equal(t, "100 "+policyID+".test+-5 "+policyID+".other", got)
+
+	got, err = formatCLIValue(value, AssetNameHex)
+	assert.Nil(t, err)
+	assert.Equal(t, value, got)
+
+	_, err = formatCLIValue("1 "+policyID+".00ff", AssetNameUTF8)
+	assert.NotNil(t, err)
+}
+
+func Test_parseUtxosHex(t *testing.T) {
+	text := `                           TxHash                                 TxIx        Amount
+--------------------------------------------------------------------------------------
+111b3dc09d55e1708a22c866f697f358ccfe94dda61df8c0b9bca5b9081989ba     0        1000000000 lovelace + 1000 5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c.74657374 + 5 5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c + TxOutDatumHashNone`
+	utxos := parseUtxos(bytes.NewBufferString(text), AssetNameHex)
+	assert.Len(t, utxos, 1)
+	assert.Len(t, utxos[0].Tokens, 2)
+	assert.Equal(t, "5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c.74657374", utxos[0].Tokens[0].Asset.ID())
+	assert.Equal(t, "", utxos[0].Tokens[1].Asset.AssetName)
+	assert.Equal(t, "5", utxos[0].Tokens[1].Quantity)
+
+
+	text = `111b3dc09d55e1708a22c866f697f358ccfe94dda61df8c0b9bca5b9081989ba     0        1000000000 lovelace + 1000 5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c.test + TxOutDatumHashNone`
+	utxos = ParseUtxos(bytes.NewBufferString(text))
+	assert.Len(t, utxos, 1)
+	assert.Equal(t, "74657374", utxos[0].Tokens[0].Asset.AssetName)
+}
+
+func TestMatchAsset(t *testing.T) {
+	asset := &Asset{PolicyId: "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209", AssetName: "504154415445"}
+
+	assert.True(t, MatchAsset("1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209.504154415445")(asset))
+	assert.True(t, MatchAsset("1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209.PATATE")(asset))
+	assert.True(t, MatchAsset("asset1hv4p5tv2a837mzqrst04d0dcptdjmluqvdx9k3")(asset))
+	assert.False(t, MatchAsset("asset1rjklcrnsdzqp65wjgrg55sy9723kw09mlgvlc3")(asset))
+	assert.False(t, MatchAsset("junk")(asset))
+	assert.False(t, MatchAsset("1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209.PATATE")(nil))
+}
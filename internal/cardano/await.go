@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savaki/zapctx"
+	"go.uber.org/zap"
+)
+
+const (
+	// awaitPollInterval is how often the utxo set is polled for tx outputs
+	awaitPollInterval = time.Second
+
+	// DefaultAwaitTimeout is the default time to wait for a tx to be confirmed
+	DefaultAwaitTimeout = 2 * time.Minute
+)
+
+// AwaitTx polls the utxo set until outputs of the tx appear, returning those
+// outputs.  When addresses are provided, only the outputs to those addresses
+// are considered; otherwise the whole utxo set is searched.  Use ctx to bound
+// the time spent waiting.
+func (c CLI) AwaitTx(ctx context.Context, txID string, addresses ...string) (utxos Utxos, err error) {
+	defer func(begin time.Time) {
+		zapctx.FromContext(ctx).Info("awaited tx",
+			zap.String("tx", txID),
+			zap.Int("outputs", len(utxos)),
+			zap.Duration("elapsed", time.Since(begin).Round(time.Millisecond)),
+			zap.Error(err),
+		)
+	}(time.Now())
+
+	if !reID.MatchString(txID) {
+		return nil, fmt.Errorf("unable to await tx: invalid tx id, %v", txID)
+	}
+	if len(addresses) == 0 {
+		addresses = []string{""}
+	}
+
+	ticker := time.NewTicker(awaitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, address := range addresses {
+			found, err := c.Utxos(address, ExcludeTx(txID))
+			if err != nil {
+				return nil, fmt.Errorf("unable to await tx, %v: %w", txID, err)
+			}
+			utxos = append(utxos, found...)
+		}
+		if len(utxos) > 0 {
+			return utxos, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("unable to await tx, %v: %w", txID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExcludeTx excludes utxos not created by the tx
+func ExcludeTx(txID string) func(utxo Utxo) bool {
+	return func(utxo Utxo) bool {
+		return utxo.Address != txID
+	}
+}
@@ -0,0 +1,29 @@
+package cardano
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestBech32Decode(t *testing.T) {
+	hrp, data, err := Bech32Decode("A12UEL5L")
+	assert.Nil(t, err)
+	assert.Equal(t, "a", hrp)
+	assert.Len(t, data, 0)
+
+	address := append([]byte{0x60}, bytes.Repeat([]byte{0x01}, 28)...)
+	s, err := Bech32Encode("addr_test", address)
+	assert.Nil(t, err)
+
+	hrp, data, err = Bech32Decode(s)
+	assert.Nil(t, err)
+	assert.Equal(t, "addr_test", hrp)
+	assert.Equal(t, address, data)
+
+	_, _, err = Bech32Decode(s[:len(s)-1] + "q")
+	assert.NotNil(t, err)
+	_, _, err = Bech32Decode("A12uEL5L")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	// MetadataLabelNFT is the transaction metadata label reserved by CIP-25
+	MetadataLabelNFT = 721
+
+	// maxMetadataStringBytes is the maximum length of a metadata string
+	maxMetadataStringBytes = 64
+
+	// maxAssetNameBytes is the maximum length of an asset name
+	maxAssetNameBytes = 32
+)
+
+// NFTFile describes an additional file associated with an NFT
+type NFTFile struct {
+	Name      string
+	MediaType string
+	Src       string
+}
+
+// NFTMetadata contains the CIP-25 metadata for a single asset
+type NFTMetadata struct {
+	Name        string
+	Image       string
+	MediaType   string
+	Description string
+	Files       []NFTFile
+}
+
+// Validate ensures the metadata can be written on chain.  Strings that
+// identify the asset must fit within the 64 byte metadata limit while
+// longer values e.g. image uris are split into chunks when encoded.
+func (m NFTMetadata) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("invalid nft metadata: name required")
+	}
+	if m.Image == "" {
+		return fmt.Errorf("invalid nft metadata: image required")
+	}
+	if err := validateMetadataString("name", m.Name); err != nil {
+		return err
+	}
+	if err := validateMetadataString("mediaType", m.MediaType); err != nil {
+		return err
+	}
+	for i, file := range m.Files {
+		if file.MediaType == "" || file.Src == "" {
+			return fmt.Errorf("invalid nft metadata: files[%v] requires mediaType and src", i)
+		}
+		if err := validateMetadataString(fmt.Sprintf("files[%v].name", i), file.Name); err != nil {
+			return err
+		}
+		if err := validateMetadataString(fmt.Sprintf("files[%v].mediaType", i), file.MediaType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateMetadataString(field, s string) error {
+	if n := len(s); n > maxMetadataStringBytes {
+		return fmt.Errorf("invalid nft metadata: %v exceeds %v bytes, %v", field, maxMetadataStringBytes, n)
+	}
+	return nil
+}
+
+// ValidateAssetName ensures the asset name fits within the ledger limit
+func ValidateAssetName(assetName string) error {
+	if n := len(assetName); n > maxAssetNameBytes {
+		return fmt.Errorf("invalid asset name, %v: exceeds %v bytes, %v", assetName, maxAssetNameBytes, n)
+	}
+	return nil
+}
+
+// NFTMetadataJSON returns the CIP-25 transaction metadata, in the cardano-cli
+// no schema json format, for the assets minted under policyID
+//
+//	{
+//	  "721": {
+//	    "<policyID>": {
+//	      "<assetName>": {
+//	        "name": "...",
+//	        "image": "...",
+//	        ...
+//	      }
+//	    },
+//	    "version": "1.0"
+//	  }
+//	}
+func NFTMetadataJSON(policyID string, assets map[string]NFTMetadata) ([]byte, error) {
+	type File struct {
+		Name      string      `json:"name,omitempty"`
+		MediaType string      `json:"mediaType"`
+		Src       interface{} `json:"src"`
+	}
+	type Record struct {
+		Name        string      `json:"name"`
+		Image       interface{} `json:"image"`
+		MediaType   string      `json:"mediaType,omitempty"`
+		Description interface{} `json:"description,omitempty"`
+		Files       []File      `json:"files,omitempty"`
+	}
+
+	records := map[string]Record{}
+	for assetName, m := range assets {
+		if err := ValidateAssetName(assetName); err != nil {
+			return nil, err
+		}
+		if err := m.Validate(); err != nil {
+			return nil, fmt.Errorf("%v: %w", assetName, err)
+		}
+
+		record := Record{
+			Name:      m.Name,
+			Image:     splitMetadataString(m.Image),
+			MediaType: m.MediaType,
+		}
+		if m.Description != "" {
+			record.Description = splitMetadataString(m.Description)
+		}
+		for _, file := range m.Files {
+			record.Files = append(record.Files, File{
+				Name:      file.Name,
+				MediaType: file.MediaType,
+				Src:       splitMetadataString(file.Src),
+			})
+		}
+		records[assetName] = record
+	}
+
+	metadata := map[string]interface{}{
+		fmt.Sprint(MetadataLabelNFT): map[string]interface{}{
+			policyID:  records,
+			"version": "1.0",
+		},
+	}
+	return json.Marshal(metadata)
+}
+
+// splitMetadataString returns s unchanged if it fits within a metadata string
+// otherwise s split into an array of chunks that each fit
+func splitMetadataString(s string) interface{} {
+	if len(s) <= maxMetadataStringBytes {
+		return s
+	}
+
+	var chunks []string
+	for len(s) > maxMetadataStringBytes {
+		n := maxMetadataStringBytes
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		chunks = append(chunks, s[:n])
+		s = s[n:]
+	}
+	if s != "" {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
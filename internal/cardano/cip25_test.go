@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestNFTMetadataJSON(t *testing.T) {
+	image := "ipfs://" + strings.Repeat("a", 100)
+	assets := map[string]NFTMetadata{
+		"Sundae01": {
+			Name:        "Sundae #1",
+			Image:       image,
+			MediaType:   "image/png",
+			Description: "the first sundae",
+			Files: []NFTFile{
+				{Name: "hi-res", MediaType: "image/png", Src: "ipfs://abc"},
+			},
+		},
+	}
+
+	data, err := NFTMetadataJSON("abc123", assets)
+	assert.Nil(t, err)
+
+	want := `{"721":{"abc123":{"Sundae01":{"name":"Sundae #1","image":["` + image[:64] + `","` + image[64:] + `"],"mediaType":"image/png","description":"the first sundae","files":[{"name":"hi-res","mediaType":"image/png","src":"ipfs://abc"}]}},"version":"1.0"}}`
+	assert.Equal(t, want, string(data))
+}
+
+func TestNFTMetadata_Validate(t *testing.T) {
+	testCases := map[string]struct {
+		Metadata NFTMetadata
+		Valid    bool
+	}{
+		"ok": {
+			Metadata: NFTMetadata{Name: "name", Image: "ipfs://abc"},
+			Valid:    true,
+		},
+		"no image": {
+			Metadata: NFTMetadata{Name: "name"},
+		},
+		"long name": {
+			Metadata: NFTMetadata{Name: strings.Repeat("n", 65), Image: "ipfs://abc"},
+		},
+		"long media type": {
+			Metadata: NFTMetadata{Name: "name", Image: "ipfs://abc", MediaType: strings.Repeat("m", 65)},
+		},
+		"incomplete file": {
+			Metadata: NFTMetadata{Name: "name", Image: "ipfs://abc", Files: []NFTFile{{Src: "ipfs://def"}}},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			err := tc.Metadata.Validate()
+			assert.Equal(t, tc.Valid, err == nil)
+		})
+	}
+}
+
+func TestSplitMetadataString(t *testing.T) {
+	s := strings.Repeat("a", 63) + "é" + "b"
+	got := splitMetadataString(s)
+	assert.Equal(t, []string{strings.Repeat("a", 63), "éb"}, got)
+}
@@ -39,6 +39,7 @@ import (
 	"time"
 
 	"github.com/savaki/zapctx"
+	"github.com/segmentio/ksuid"
 	"go.uber.org/zap"
 )
 
@@ -59,9 +60,14 @@ type CLI struct {
 	Dir              string
 	PoolDir          string
 	SocketPath       string
-	TestnetMagic     string
+	Network          Network // Network selects the network every command targets
 	TreasuryAddr     string
 	TreasurySkeyFile string
+	Treasury         *TreasuryPool   // Treasury optionally tracks in-flight treasury inputs
+	FaucetWallet     string          // FaucetWallet optionally names the wallet native tokens are dispensed from
+	AssetNameFormat  AssetNameFormat // AssetNameFormat identifies how cardano-cli renders asset names
+	History          TxRecorder      // History optionally records submitted txs
+	Eras             *EraCache       // Eras optionally caches the era detected from the node
 	Debug            bool
 }
 
@@ -69,14 +75,16 @@ type CLI struct {
 //	return &CLI{
 //		Base:            base[0:len(base):len(base)],
 //		Dir:             dir,
-//		TestnetMagic:    testnetMagic,
 //		TreasuryAddr:    treasuryAddr,
 //		TreasuryKeyFile: treasuryKey,
 //	}
 //}
 
+// Asset identifies a native token.  AssetName is always hex encoded
+// regardless of the format used by cardano-cli.
 type Asset struct {
 	AssetName   string `json:"asset_name,omitempty"`
+	Decimals    *int32 `json:"decimals,omitempty"`
 	Description string `json:"description,omitempty"`
 	Logo        string `json:"logo,omitempty"`
 	Name        string `json:"name,omitempty"`
@@ -85,6 +93,7 @@ type Asset struct {
 	Url         string `json:"url,omitempty"`
 }
 
+// ID returns the asset id in the form policyId.assetNameHex
 func (a Asset) ID() string {
 	return fmt.Sprintf("%v.%v", a.PolicyId, a.AssetName)
 }
@@ -106,6 +115,7 @@ type Utxo struct {
 	Address   string  `json:"address,omitempty"`
 	DatumHash string  `json:"datum_hash,omitempty"`
 	Index     int32   `json:"index,omitempty"`
+	Owner     string  `json:"owner,omitempty"` // Owner holds the address of the utxo; only populated by UtxoSet
 	Tokens    []Token `json:"tokens,omitempty"`
 	Value     string  `json:"value,omitempty"`
 }
@@ -217,9 +227,9 @@ func (c CLI) MinFee(ctx context.Context, filename string, txIn, txOut, witnesses
 		"--tx-in-count", strconv.FormatInt(int64(txIn), 10),
 		"--tx-out-count", strconv.FormatInt(int64(txOut), 10),
 		"--witness-count", strconv.FormatInt(int64(witnesses), 10),
-		"--testnet-magic", c.TestnetMagic,
 		"--protocol-params-file", protocol,
 	}
+	args = append(args, c.Network.Args()...)
 	buf, err := c.exec(args...)
 	if err != nil {
 		return "", fmt.Errorf("unable to calculate min fee: %w", err)
@@ -247,7 +257,10 @@ func (c *CLI) NormalizeAddress(address string) (string, error) {
 	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return address, nil
+			if c.Network.Name == "" {
+				return address, nil
+			}
+			return address, c.Network.ValidateAddress(address)
 		}
 		return "", fmt.Errorf("unable to read wallet: %w", err)
 	}
@@ -284,9 +297,8 @@ func (c CLI) ProtocolParameters(ctx context.Context) (filename string, err error
 			return "", fmt.Errorf("unable to read protocol parameters: %w", err)
 		}
 
-		args := []string{
-			"query", "protocol-parameters", "--testnet-magic", c.TestnetMagic, "--out-file", filename,
-		}
+		args := append([]string{"query", "protocol-parameters"}, c.Network.Args()...)
+		args = append(args, "--out-file", filename)
 		if _, err := c.exec(args...); err != nil {
 			return "", fmt.Errorf("unable to read protocol parameters: %w", err)
 		}
@@ -295,7 +307,8 @@ func (c CLI) ProtocolParameters(ctx context.Context) (filename string, err error
 }
 
 func (c CLI) QueryTip() (*Tip, error) {
-	buf, err := c.exec("query", "tip", "--testnet-magic", c.TestnetMagic, "--cardano-mode")
+	args := append([]string{"query", "tip"}, c.Network.Args()...)
+	buf, err := c.exec(append(args, "--cardano-mode")...)
 	if err != nil {
 		return nil, fmt.Errorf("query tip failed: %w", err)
 	}
@@ -305,6 +318,9 @@ func (c CLI) QueryTip() (*Tip, error) {
 		return nil, fmt.Errorf("query tip failed: %w", err)
 	}
 
+	// keep the cached era current; an unsupported era is reported by Era
+	_, _ = c.Eras.detected(tip.Era, time.Now())
+
 	return &tip, nil
 }
 
@@ -313,11 +329,12 @@ func (c CLI) DataDir() string {
 	return c.Dir
 }
 
+// AtLeast excludes utxos holding less than amt lovelace
 func AtLeast(amt int32) func(utxo Utxo) bool {
 	cmp := big.NewInt(int64(amt))
 	return func(utxo Utxo) bool {
-		val, _ := big.NewInt(0).SetString(utxo.Value, 10)
-		return val.Cmp(cmp) >= 0
+		val, ok := big.NewInt(0).SetString(utxo.Value, 10)
+		return !ok || val.Cmp(cmp) < 0
 	}
 }
 
@@ -358,7 +375,8 @@ func (c CLI) Utxos(address string, excludes ...func(Utxo) bool) (utxos Utxos, er
 		return nil, err
 	}
 
-	args := []string{"query", "utxo", "--testnet-magic", c.TestnetMagic, "--cardano-mode"}
+	args := append([]string{"query", "utxo"}, c.Network.Args()...)
+	args = append(args, "--cardano-mode")
 	if address == "" {
 		args = append(args, "--whole-utxo")
 	} else {
@@ -371,7 +389,7 @@ func (c CLI) Utxos(address string, excludes ...func(Utxo) bool) (utxos Utxos, er
 	}
 
 loop:
-	for _, item := range ParseUtxos(buf) {
+	for _, item := range parseUtxos(buf, c.AssetNameFormat) {
 		utxo := item
 		for _, fn := range excludes {
 			if fn(utxo) {
@@ -384,6 +402,30 @@ loop:
 	return utxos, nil
 }
 
+// UtxoSet returns the whole utxo set.  Unlike Utxos, each utxo includes the
+// Owner address, which the text output of cardano-cli omits.
+func (c CLI) UtxoSet() (Utxos, error) {
+	filename := filepath.Join(c.Dir, "tmp", ksuid.New().String())
+	defer os.Remove(filename)
+
+	args := append([]string{"query", "utxo"}, c.Network.Args()...)
+	args = append(args, "--cardano-mode", "--whole-utxo", "--out-file", filename)
+	if _, err := c.exec(args...); err != nil {
+		return nil, fmt.Errorf("failed to query utxo set: %w", err)
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query utxo set: %w", err)
+	}
+
+	utxos, err := parseUtxoSet(data, c.AssetNameFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query utxo set: %w", err)
+	}
+	return utxos, nil
+}
+
 func (c CLI) Version() (version Version, err error) {
 	buf, err := c.exec("version")
 	if err != nil {
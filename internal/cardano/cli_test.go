@@ -64,3 +64,18 @@ func TestNormalize(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "foo", got)
 }
+
+func TestAtLeast(t *testing.T) {
+	exclude := AtLeast(2e6)
+	assert.True(t, exclude(Utxo{Value: "1999999"}))
+	assert.False(t, exclude(Utxo{Value: "2000000"}))
+	assert.False(t, exclude(Utxo{Value: "3000000"}))
+	assert.True(t, exclude(Utxo{Value: "bad"}))
+}
+
+func TestExcludeTx(t *testing.T) {
+	txID := "7a9e6f8a5d1e5a3c8b0a9a1d5f1e6d2b3c4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c"
+	exclude := ExcludeTx(txID)
+	assert.False(t, exclude(Utxo{Address: txID}))
+	assert.True(t, exclude(Utxo{Address: "other"}))
+}
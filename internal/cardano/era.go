@@ -0,0 +1,174 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Era describes how commands are built and their output parsed for a ledger era
+type Era struct {
+	Name       string // Name of the era as reported by query tip e.g. Alonzo
+	Flag       string // Flag selects the era in transaction commands e.g. --alonzo-era
+	MultiAsset bool   // MultiAsset indicates outputs may hold native tokens and txs may mint
+	MinUtxo    bool   // MinUtxo indicates cardano-cli can calculate the min-ada of an output
+	DatumTag   string // DatumTag optionally prefixes datum hashes in query utxo output
+}
+
+// eras holds the eras the toolkit supports, oldest first
+var eras = []Era{
+	{Name: "Shelley", Flag: "--shelley-era"},
+	{Name: "Allegra", Flag: "--allegra-era"},
+	{Name: "Mary", Flag: "--mary-era", MultiAsset: true},
+	{Name: "Alonzo", Flag: "--alonzo-era", MultiAsset: true, MinUtxo: true, DatumTag: "ScriptDataInAlonzoEra"},
+	{Name: "Babbage", Flag: "--babbage-era", MultiAsset: true, MinUtxo: true, DatumTag: "ScriptDataInBabbageEra"},
+}
+
+// reDatum matches the datum hash of a utxo rendered by any supported era
+var reDatum = func() *regexp.Regexp {
+	var tags []string
+	for _, era := range eras {
+		if era.DatumTag != "" {
+			tags = append(tags, era.DatumTag)
+		}
+	}
+	return regexp.MustCompile(`(?:` + strings.Join(tags, "|") + `)\s+"([^"]+)"`)
+}()
+
+// LookupEra returns the era with the given name, ignoring case and any era
+// suffix e.g. Alonzo, alonzo, and alonzo-era are equivalent
+func LookupEra(name string) (Era, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.TrimSuffix(strings.TrimSuffix(key, "era"), "-")
+	for _, era := range eras {
+		if strings.ToLower(era.Name) == key {
+			return era, nil
+		}
+	}
+	return Era{}, fmt.Errorf("unsupported era, %v: expected one of %v", name, strings.Join(EraNames(), ", "))
+}
+
+// EraNames returns the names of the supported eras, oldest first
+func EraNames() []string {
+	var names []string
+	for _, era := range eras {
+		names = append(names, era.Name)
+	}
+	return names
+}
+
+// Era returns the era commands should be built for; the era configured by
+// the network when set, otherwise the current era reported by the node.  When
+// the CLI has an EraCache, the node is only queried once the cached era
+// expires, and the last detected era is used should the node be unreachable.
+func (c CLI) Era() (Era, error) {
+	if c.Network.Era != "" {
+		return LookupEra(c.Network.Era)
+	}
+
+	now := time.Now()
+	if era, ok := c.Eras.get(now); ok {
+		return era, nil
+	}
+
+	tip, err := c.QueryTip()
+	if err != nil {
+		if era, ok := c.Eras.last(); ok {
+			return era, nil
+		}
+		return Era{}, fmt.Errorf("unable to detect era: %w", err)
+	}
+	return LookupEra(tip.Era)
+}
+
+// DefaultEraTTL is the default duration a detected era is cached for
+const DefaultEraTTL = 5 * time.Minute
+
+// EraCache holds the era last detected from the node so that building
+// commands does not require a query tip each time.  The cached era is
+// refreshed after the ttl expires or whenever a query tip reports a new era.
+// A nil EraCache caches nothing.
+type EraCache struct {
+	ttl time.Duration
+
+	mutex sync.Mutex
+	era   Era
+	at    time.Time
+}
+
+// NewEraCache returns an EraCache holding detected eras for ttl
+func NewEraCache(ttl time.Duration) *EraCache {
+	if ttl <= 0 {
+		ttl = DefaultEraTTL
+	}
+	return &EraCache{ttl: ttl}
+}
+
+// get returns the cached era provided it has not expired
+func (e *EraCache) get(now time.Time) (Era, bool) {
+	if e == nil {
+		return Era{}, false
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.at.IsZero() || now.Sub(e.at) >= e.ttl {
+		return Era{}, false
+	}
+	return e.era, true
+}
+
+// last returns the cached era regardless of whether it has expired
+func (e *EraCache) last() (Era, bool) {
+	if e == nil {
+		return Era{}, false
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.era, !e.at.IsZero()
+}
+
+// detected looks up the era reported by the node and caches it
+func (e *EraCache) detected(name string, now time.Time) (Era, error) {
+	era, err := LookupEra(name)
+	if err != nil {
+		return Era{}, err
+	}
+	if e == nil {
+		return era, nil
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.era = era
+	e.at = now
+	return era, nil
+}
@@ -0,0 +1,85 @@
+package cardano
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+func TestLookupEra(t *testing.T) {
+	for _, name := range []string{"Alonzo", "alonzo", "alonzo-era", " ALONZO "} {
+		era, err := LookupEra(name)
+		assert.Nil(t, err, name)
+		assert.Equal(t, "--alonzo-era", era.Flag, name)
+	}
+
+	era, err := LookupEra("Allegra")
+	assert.Nil(t, err)
+	assert.False(t, era.MultiAsset)
+
+	_, err = LookupEra("Byron")
+	assert.NotNil(t, err)
+	_, err = LookupEra("Conway")
+	assert.NotNil(t, err)
+}
+
+func TestParseUtxos_Babbage(t *testing.T) {
+	buf := bytes.NewBufferString(`                           TxHash                                 TxIx        Amount
+--------------------------------------------------------------------------------------
+0f318cef1d18cb1c1d42962359c0d3f6cfc533e94393cc0203cb6ebdd67391d6     0        10000000 lovelace + TxOutDatumHash ScriptDataInBabbageEra "ed0429bf27140424f9997e5df481751c9f7679291dfa5bcf25508cfe48dbb4a4"
+1070fc7f54ebe76a5883c676d86765db3d7a5d55654e1e0fc69b9acd7f81c40c     1        20000000 lovelace + TxOutDatumNone
+`)
+	utxos := ParseUtxos(buf)
+	assert.Len(t, utxos, 2)
+	assert.Equal(t, "ed0429bf27140424f9997e5df481751c9f7679291dfa5bcf25508cfe48dbb4a4", utxos[0].DatumHash)
+	assert.Equal(t, "", utxos[1].DatumHash)
+	assert.Equal(t, "20000000", utxos[1].Value)
+}
+
+func TestCLI_Era(t *testing.T) {
+	var (
+		eras = NewEraCache(time.Hour)
+		cli  = CLI{
+			Cmd:  []string{"sh", "-c", `echo '{"era":"Babbage"}'`, "--"},
+			Dir:  os.TempDir(),
+			Eras: eras,
+		}
+	)
+
+	era, err := cli.Era()
+	assert.Nil(t, err)
+	assert.Equal(t, "Babbage", era.Name)
+
+	// the cached era is used without querying the node
+	cli.Cmd = []string{"false"}
+	era, err = cli.Era()
+	assert.Nil(t, err)
+	assert.Equal(t, "Babbage", era.Name)
+
+	// once expired, the node is queried and any new era cached
+	now := time.Now()
+	_, ok := eras.get(now.Add(time.Hour))
+	assert.False(t, ok)
+
+	cli.Cmd = []string{"sh", "-c", `echo '{"era":"Alonzo"}'`, "--"}
+	_, err = cli.QueryTip()
+	assert.Nil(t, err)
+	era, ok = eras.get(now)
+	assert.True(t, ok)
+	assert.Equal(t, "Alonzo", era.Name)
+
+	// the last detected era is used when the node is unreachable
+	eras.at = now.Add(-2 * time.Hour)
+	cli.Cmd = []string{"false"}
+	era, err = cli.Era()
+	assert.Nil(t, err)
+	assert.Equal(t, "Alonzo", era.Name)
+
+	// without a cache, the node must be reachable
+	cli.Eras = nil
+	_, err = cli.Era()
+	assert.NotNil(t, err)
+}
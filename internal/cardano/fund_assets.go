@@ -0,0 +1,357 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/savaki/zapctx"
+	"github.com/segmentio/ksuid"
+	"go.uber.org/zap"
+)
+
+var reLovelace = regexp.MustCompile(`(\d+)`)
+
+// AssetQuantity identifies a quantity of a native token; AssetID may be any
+// form accepted by MatchAsset
+type AssetQuantity struct {
+	AssetID  string
+	Quantity string
+}
+
+// MinUTxO returns the minimum lovelace an output to address holding tokens
+// ("qty policyId.assetNameHex") must contain
+func (c CLI) MinUTxO(ctx context.Context, address string, tokens ...string) (lovelace string, err error) {
+	defer func(begin time.Time) {
+		zapctx.FromContext(ctx).Info("calculated min utxo",
+			zap.Duration("elapsed", time.Since(begin).Round(time.Millisecond)),
+			zap.String("lovelace", lovelace),
+			zap.Error(err),
+		)
+	}(time.Now())
+
+	era, err := c.Era()
+	if err != nil {
+		return "", fmt.Errorf("unable to calculate min utxo: %w", err)
+	}
+	if !era.MinUtxo {
+		return "", fmt.Errorf("unable to calculate min utxo: not supported by the %v era", era.Name)
+	}
+
+	protocol, err := c.ProtocolParameters(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to calculate min utxo: %w", err)
+	}
+
+	output := address + "+0"
+	if len(tokens) > 0 {
+		value, err := formatCLIValue(strings.Join(tokens, "+"), c.AssetNameFormat)
+		if err != nil {
+			return "", fmt.Errorf("unable to calculate min utxo: %w", err)
+		}
+		output += "+" + value
+	}
+
+	args := []string{
+		"transaction", "calculate-min-required-utxo",
+		era.Flag,
+		"--protocol-params-file", protocol,
+		"--tx-out", output,
+	}
+	buf, err := c.exec(args...)
+	if err != nil {
+		return "", fmt.Errorf("unable to calculate min utxo: %w", err)
+	}
+
+	match := reLovelace.FindStringSubmatch(buf.String())
+	if len(match) != 2 {
+		return "", fmt.Errorf("unable to calculate min utxo: unexpected output, %v", strings.TrimSpace(buf.String()))
+	}
+	return match[1], nil
+}
+
+// FundWalletAssets transfers native tokens along with at least quantity
+// lovelace, raised to the min-ada required by the output, to the address.
+// Tokens are dispensed from the FaucetWallet when configured and from the
+// treasury otherwise.
+func (c CLI) FundWalletAssets(ctx context.Context, address, quantity string, assets []AssetQuantity, opts ...BuildOption) (tx Tx, err error) {
+	defer func(begin time.Time) {
+		zapctx.FromContext(ctx).Info("funded wallet assets",
+			zap.String("address", address),
+			zap.String("quantity", quantity),
+			zap.Int("assets", len(assets)),
+			zap.String("source", c.FaucetWallet),
+			zap.Duration("elapsed", time.Since(begin).Round(time.Millisecond)),
+			zap.Error(err),
+		)
+	}(time.Now())
+
+	if len(assets) == 0 {
+		return c.FundWallet(ctx, address, quantity, opts...)
+	}
+
+	fund, err := c.resolveFundAssets(ctx, address, quantity, assets)
+	if err != nil {
+		return Tx{}, fmt.Errorf("unable to fund wallet assets: %w", err)
+	}
+
+	pool := c.Treasury
+	if pool == nil {
+		pool = NewTreasuryPool()
+	}
+
+	// token inputs and, for a faucet wallet, the lovelace inputs are reserved
+	// directly; treasury lovelace comes from the treasury pool
+	required := big.NewInt(0).Add(fund.lovelace, big.NewInt(treasuryMargin))
+	sourceKey := fund.signer
+	sourceLovelace := required
+	if fund.signer == "" {
+		sourceLovelace = big.NewInt(0)
+	}
+
+	pool.mutex.Lock()
+	inputs, err := pool.reserveValue(sourceKey, fund.source, sourceLovelace, fund.tokens, time.Now())
+	pool.mutex.Unlock()
+	if err != nil {
+		return Tx{}, fmt.Errorf("unable to fund wallet assets: %w", err)
+	}
+
+	reserved := inputs
+	release := func(submitted bool) {
+		if !submitted {
+			pool.release(reserved...)
+		}
+	}
+	if fund.signer == "" {
+		utxo, releaseTreasury, err := c.reserveTreasuryUtxo(ctx, fund.lovelace.String())
+		if err != nil {
+			release(false)
+			return Tx{}, fmt.Errorf("unable to fund wallet assets: %w", err)
+		}
+		inputs = append(inputs, utxo)
+		release = func(submitted bool) {
+			releaseTreasury(submitted)
+			if !submitted {
+				pool.release(reserved...)
+			}
+		}
+	}
+
+	tx, err = c.transferAssets(ctx, inputs, fund.sourceAddr, fund.signer, fund.address, fund.lovelace, fund.requested, opts...)
+	release(err == nil)
+	if err != nil {
+		return Tx{}, fmt.Errorf("unable to fund wallet assets: %w", err)
+	}
+	return tx, nil
+}
+
+// fundAssets describes an asset funding request resolved against the tokens
+// held by the faucet
+type fundAssets struct {
+	sourceAddr string              // sourceAddr holds the address tokens are dispensed from
+	signer     string              // signer holds the wallet signing for sourceAddr; empty for the treasury
+	source     Utxos               // source holds the utxos of sourceAddr
+	address    string              // address holds the normalized recipient address
+	lovelace   *big.Int            // lovelace paid, raised to the min-ada of the output
+	requested  Value               // requested holds the tokens paid
+	tokens     map[string]*big.Int // tokens holds the quantity paid per policyId.assetNameHex
+}
+
+// resolveFundAssets resolves the assets requested against the tokens held by
+// the faucet and raises quantity to the min-ada required by the output
+func (c CLI) resolveFundAssets(ctx context.Context, address, quantity string, assets []AssetQuantity) (fund fundAssets, err error) {
+	if quantity == "" {
+		quantity = "0"
+	}
+
+	sourceAddr, signer := c.TreasuryAddr, ""
+	if c.FaucetWallet != "" {
+		sourceAddr, err = c.NormalizeAddress(c.FaucetWallet)
+		if err != nil {
+			return fundAssets{}, err
+		}
+		signer = c.FaucetWallet
+	}
+
+	source, err := c.Utxos(sourceAddr, ExcludeScripts(true))
+	if err != nil {
+		return fundAssets{}, err
+	}
+
+	// resolve each requested asset against the tokens held by the source
+	var (
+		requested = NewValue()
+		tokens    = map[string]*big.Int{}
+	)
+	for _, asset := range assets {
+		q, ok := big.NewInt(0).SetString(asset.Quantity, 10)
+		if !ok || q.Sign() <= 0 {
+			return fundAssets{}, fmt.Errorf("invalid quantity for %v, %v", asset.AssetID, asset.Quantity)
+		}
+		assetID, ok := findAssetID(source, asset.AssetID)
+		if !ok {
+			return fundAssets{}, fmt.Errorf("faucet does not hold asset, %v", asset.AssetID)
+		}
+		requested.AddToken(assetID, q)
+		tokens[assetID] = requested.Token(assetID)
+	}
+
+	address, err = c.NormalizeAddress(address)
+	if err != nil {
+		return fundAssets{}, err
+	}
+
+	minUTxO, err := c.MinUTxO(ctx, address, requested.TokenArgs()...)
+	if err != nil {
+		return fundAssets{}, err
+	}
+	lovelace, ok := big.NewInt(0).SetString(quantity, 10)
+	if !ok {
+		return fundAssets{}, fmt.Errorf("invalid quantity, %v", quantity)
+	}
+	if min, _ := big.NewInt(0).SetString(minUTxO, 10); min != nil && lovelace.Cmp(min) < 0 {
+		lovelace = min
+	}
+
+	address, err = c.ValidatePayout(address, lovelace.String())
+	if err != nil {
+		return fundAssets{}, err
+	}
+
+	return fundAssets{
+		sourceAddr: sourceAddr,
+		signer:     signer,
+		source:     source,
+		address:    address,
+		lovelace:   lovelace,
+		requested:  requested,
+		tokens:     tokens,
+	}, nil
+}
+
+// FundAssetsLovelace returns the lovelace FundWalletAssets would pay address
+// along with the assets i.e. quantity raised to the min-ada of the output
+func (c CLI) FundAssetsLovelace(ctx context.Context, address, quantity string, assets []AssetQuantity) (string, error) {
+	if len(assets) == 0 {
+		return quantity, nil
+	}
+
+	fund, err := c.resolveFundAssets(ctx, address, quantity, assets)
+	if err != nil {
+		return "", fmt.Errorf("unable to fund wallet assets: %w", err)
+	}
+	return fund.lovelace.String(), nil
+}
+
+// transferAssets spends inputs paying lovelace and the requested tokens to
+// address with the remainder returned to sourceAddr as change at output 0
+func (c CLI) transferAssets(ctx context.Context, inputs Utxos, sourceAddr, signer, address string, lovelace *big.Int, requested Value, opts ...BuildOption) (Tx, error) {
+	total, err := inputs.Value()
+	if err != nil {
+		return Tx{}, err
+	}
+
+	change := NewValue()
+	change.Lovelace.Sub(total.Lovelace, lovelace)
+	for assetID, q := range total.Tokens {
+		change.AddToken(assetID, big.NewInt(0).Sub(q, requested.Token(assetID)))
+	}
+
+	build := func(fee *big.Int) ([]byte, error) {
+		options := []BuildOption{Fee(fee.String())}
+		for _, utxo := range inputs {
+			options = append(options, TxIn(utxo.Address, utxo.Index))
+		}
+		options = append(options,
+			TxOut(sourceAddr, big.NewInt(0).Sub(change.Lovelace, fee).String(), change.TokenArgs()...),
+			TxOut(address, lovelace.String(), requested.TokenArgs()...),
+		)
+		return c.Build(append(options, opts...)...)
+	}
+
+	raw, err := build(big.NewInt(0))
+	if err != nil {
+		return Tx{}, err
+	}
+
+	filename := filepath.Join(c.Dir, "tmp", ksuid.New().String())
+	defer os.Remove(filename)
+	if err := ioutil.WriteFile(filename, raw, 0644); err != nil {
+		return Tx{}, fmt.Errorf("failed to write raw tx body: %w", err)
+	}
+
+	feeStr, err := c.MinFee(ctx, filename, int32(len(inputs)), 2, 1)
+	if err != nil {
+		return Tx{}, err
+	}
+	fee, ok := big.NewInt(0).SetString(feeStr, 10)
+	if !ok {
+		return Tx{}, fmt.Errorf("failed to parse fee, %v", feeStr)
+	}
+	if big.NewInt(0).Sub(change.Lovelace, fee).Sign() < 0 {
+		return Tx{}, fmt.Errorf("insufficient lovelace to cover fee")
+	}
+
+	raw, err = build(fee)
+	if err != nil {
+		return Tx{}, err
+	}
+
+	signed, err := c.Sign(ctx, raw, signer)
+	if err != nil {
+		return Tx{}, err
+	}
+
+	tx, err := ParseTx(signed)
+	if err != nil {
+		return Tx{}, fmt.Errorf("failed to parse transaction: %w", err)
+	}
+
+	if err := c.Submit(WithTxKind(ctx, TxKindFund), signed); err != nil {
+		return Tx{}, err
+	}
+
+	return tx, nil
+}
+
+// findAssetID returns the policyId.assetNameHex id of the asset held by the
+// utxos matching id
+func findAssetID(utxos Utxos, id string) (string, bool) {
+	match := MatchAsset(id)
+	for _, utxo := range utxos {
+		for _, token := range utxo.Tokens {
+			if match(token.Asset) {
+				return token.Asset.ID(), true
+			}
+		}
+	}
+	return "", false
+}
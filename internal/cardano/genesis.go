@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/savaki/zapctx"
+	"go.uber.org/zap"
+)
+
+// GenesisKey describes a genesis utxo key e.g. shelley/utxo-keys/utxo1.skey
+type GenesisKey struct {
+	SigningKeyFile      string
+	VerificationKeyFile string
+	Address             string // Address is the enterprise address funded at genesis
+	Lovelace            string // Lovelace still held by the address
+	Utxos               Utxos  // Utxos holds the unspent utxos of the address
+}
+
+// findGenesisKeyFiles returns the signing key files within utxo-keys dirs
+// under dir that have a matching verification key
+func findGenesisKeyFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Base(filepath.Dir(path)) != "utxo-keys" || filepath.Ext(path) != ".skey" {
+			return nil
+		}
+		if _, err := os.Stat(strings.TrimSuffix(path, ".skey") + ".vkey"); err != nil {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// GenesisKeys detects the shelley genesis utxo keys, utxo-keys/*.skey, under
+// dir along with the funds their addresses still hold
+func (c CLI) GenesisKeys(dir string) ([]GenesisKey, error) {
+	files, err := findGenesisKeyFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find genesis keys: %w", err)
+	}
+
+	var keys []GenesisKey
+	for _, skey := range files {
+		vkey := strings.TrimSuffix(skey, ".skey") + ".vkey"
+		args := append([]string{"address", "build", "--payment-verification-key-file", vkey}, c.Network.Args()...)
+		buf, err := c.exec(args...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to build genesis address: %w", err)
+		}
+
+		address := strings.TrimSpace(buf.String())
+		utxos, err := c.Utxos(address, ExcludeTokens(true))
+		if err != nil {
+			return nil, fmt.Errorf("unable to find genesis funds: %w", err)
+		}
+		value := NewValue()
+		for _, utxo := range utxos {
+			if err := value.Add(utxo); err != nil {
+				return nil, fmt.Errorf("unable to find genesis funds: %w", err)
+			}
+		}
+
+		keys = append(keys, GenesisKey{
+			SigningKeyFile:      skey,
+			VerificationKeyFile: vkey,
+			Address:             address,
+			Lovelace:            value.Lovelace.String(),
+			Utxos:               utxos,
+		})
+	}
+	return keys, nil
+}
+
+// SweepGenesis transfers the funds held by the genesis utxo keys found under
+// dir into the treasury in a single tx.  The returned tx is empty when no
+// genesis key holds funds.
+func (c CLI) SweepGenesis(ctx context.Context, dir string) (tx Tx, err error) {
+	defer func(begin time.Time) {
+		zapctx.FromContext(ctx).Info("swept genesis funds",
+			zap.String("dir", dir),
+			zap.String("tx", tx.ID),
+			zap.Duration("elapsed", time.Since(begin).Round(time.Millisecond)),
+			zap.Error(err),
+		)
+	}(time.Now())
+
+	keys, err := c.GenesisKeys(dir)
+	if err != nil {
+		return Tx{}, fmt.Errorf("failed to sweep genesis funds: %w", err)
+	}
+
+	var (
+		utxos           Utxos
+		signingKeyFiles []string
+	)
+	for _, key := range keys {
+		if len(key.Utxos) == 0 {
+			continue
+		}
+		utxos = append(utxos, key.Utxos...)
+		signingKeyFiles = append(signingKeyFiles, key.SigningKeyFile)
+	}
+	if len(utxos) == 0 {
+		return Tx{}, nil
+	}
+
+	tx, err = c.transferAll(WithTxKind(ctx, TxKindSweep), utxos, c.TreasuryAddr, signingKeyFiles...)
+	if err != nil {
+		return Tx{}, fmt.Errorf("failed to sweep genesis funds: %w", err)
+	}
+	return tx, nil
+}
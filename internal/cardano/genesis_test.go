@@ -0,0 +1,32 @@
+package cardano
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func Test_findGenesisKeyFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, filename := range []string{
+		"shelley/utxo-keys/utxo1.skey",
+		"shelley/utxo-keys/utxo1.vkey",
+		"shelley/utxo-keys/utxo2.skey", // no vkey
+		"shelley/genesis-keys/genesis1.skey",
+		"shelley/genesis-keys/genesis1.vkey",
+	} {
+		path := filepath.Join(dir, filename)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, ioutil.WriteFile(path, []byte("{}"), 0644))
+	}
+
+	files, err := findGenesisKeyFiles(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "shelley/utxo-keys/utxo1.skey")}, files)
+}
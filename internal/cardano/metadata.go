@@ -0,0 +1,234 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// MetadataSchema identifies the json format used to describe tx metadata
+type MetadataSchema string
+
+const (
+	// MetadataNoSchema uses plain json values; strings beginning with 0x are bytes
+	MetadataNoSchema MetadataSchema = "no-schema"
+
+	// MetadataDetailedSchema describes each value e.g. {"int": 42} or {"bytes": "cafe"}
+	MetadataDetailedSchema MetadataSchema = "detailed-schema"
+)
+
+// auxDataTag identifies alonzo era auxiliary data
+const auxDataTag = 259
+
+// Metadata holds transaction metadata keyed by label
+type Metadata map[uint64]interface{}
+
+// ValidateMetadataJSON ensures data contains a json object keyed by metadata labels
+func ValidateMetadataJSON(data []byte, schema MetadataSchema) error {
+	switch schema {
+	case MetadataNoSchema, MetadataDetailedSchema:
+	default:
+		return fmt.Errorf("invalid metadata: unknown schema, %v", schema)
+	}
+
+	var labels map[string]json.RawMessage
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return fmt.Errorf("invalid metadata: expected json object keyed by label: %w", err)
+	}
+	for label := range labels {
+		if _, err := strconv.ParseUint(label, 10, 64); err != nil {
+			return fmt.Errorf("invalid metadata: label must be an unsigned integer, %v", label)
+		}
+	}
+	return nil
+}
+
+// decodeAuxData extracts the metadata from the cbor encoded auxiliary data
+// of a transaction.  shelley (map), allegra/mary ([map, scripts]), and alonzo
+// (tag 259 {0: map}) formats are supported.
+func decodeAuxData(data []byte) (Metadata, error) {
+	var v interface{}
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode auxiliary data: %w", err)
+	}
+
+	switch aux := v.(type) {
+	case nil:
+		return nil, nil
+	case map[interface{}]interface{}:
+		return toMetadata(aux)
+	case []interface{}:
+		if len(aux) == 0 {
+			return nil, nil
+		}
+		m, ok := aux[0].(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("failed to decode auxiliary data: unexpected metadata type, %T", aux[0])
+		}
+		return toMetadata(m)
+	case cbor.Tag:
+		if aux.Number != auxDataTag {
+			return nil, fmt.Errorf("failed to decode auxiliary data: unexpected tag, %v", aux.Number)
+		}
+		fields, ok := aux.Content.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("failed to decode auxiliary data: unexpected content type, %T", aux.Content)
+		}
+		m, ok := fields[uint64(0)].(map[interface{}]interface{})
+		if !ok {
+			return nil, nil
+		}
+		return toMetadata(m)
+	default:
+		return nil, fmt.Errorf("failed to decode auxiliary data: unexpected type, %T", v)
+	}
+}
+
+func toMetadata(m map[interface{}]interface{}) (Metadata, error) {
+	metadata := Metadata{}
+	for k, v := range m {
+		label, ok := k.(uint64)
+		if !ok {
+			return nil, fmt.Errorf("failed to decode metadata: invalid label, %v", k)
+		}
+		metadata[label] = v
+	}
+	return metadata, nil
+}
+
+// JSON encodes the metadata using the same json formats accepted by cardano-cli
+func (m Metadata) JSON(schema MetadataSchema) ([]byte, error) {
+	labels := map[string]interface{}{}
+	for label, v := range m {
+		var (
+			value interface{}
+			err   error
+		)
+		switch schema {
+		case MetadataDetailedSchema:
+			value, err = detailedValue(v)
+		default:
+			value, err = noSchemaValue(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode metadata label, %v: %w", label, err)
+		}
+		labels[strconv.FormatUint(label, 10)] = value
+	}
+	return json.Marshal(labels)
+}
+
+func noSchemaValue(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case uint64, int64, string:
+		return value, nil
+	case big.Int:
+		return json.Number(value.String()), nil
+	case []byte:
+		return "0x" + hex.EncodeToString(value), nil
+	case []interface{}:
+		items := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			item, err := noSchemaValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case map[interface{}]interface{}:
+		items := map[string]interface{}{}
+		for k, item := range value {
+			key, err := noSchemaValue(k)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := key.(string); !ok {
+				key = fmt.Sprint(key)
+			}
+			if items[key.(string)], err = noSchemaValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unsupported metadata value type, %T", v)
+	}
+}
+
+func detailedValue(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case uint64, int64:
+		return map[string]interface{}{"int": value}, nil
+	case big.Int:
+		return map[string]interface{}{"int": json.Number(value.String())}, nil
+	case string:
+		return map[string]interface{}{"string": value}, nil
+	case []byte:
+		return map[string]interface{}{"bytes": hex.EncodeToString(value)}, nil
+	case []interface{}:
+		items := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			item, err := detailedValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return map[string]interface{}{"list": items}, nil
+	case map[interface{}]interface{}:
+		type Entry struct {
+			K interface{} `json:"k"`
+			V interface{} `json:"v"`
+		}
+		entries := make([]Entry, 0, len(value))
+		for k, item := range value {
+			key, err := detailedValue(k)
+			if err != nil {
+				return nil, err
+			}
+			item, err := detailedValue(item)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, Entry{K: key, V: item})
+		}
+		// map iteration order is random; sort entries for stable output
+		sort.Slice(entries, func(i, j int) bool {
+			a, _ := json.Marshal(entries[i].K)
+			b, _ := json.Marshal(entries[j].K)
+			return bytes.Compare(a, b) < 0
+		})
+		return map[string]interface{}{"map": entries}, nil
+	default:
+		return nil, fmt.Errorf("unsupported metadata value type, %T", v)
+	}
+}
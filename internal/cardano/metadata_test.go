@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/tj/assert"
+)
+
+func TestParseTx_Metadata(t *testing.T) {
+	metadata := map[uint64]interface{}{
+		674: map[string]interface{}{
+			"msg": []interface{}{"hello", uint64(42)},
+		},
+		1: []byte{0xca, 0xfe},
+	}
+
+	testCases := map[string]struct {
+		AuxData interface{}
+	}{
+		"shelley": {
+			AuxData: metadata,
+		},
+		"mary": {
+			AuxData: []interface{}{metadata, []interface{}{}},
+		},
+		"alonzo": {
+			AuxData: cbor.Tag{Number: auxDataTag, Content: map[uint64]interface{}{0: metadata}},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			body := map[uint64]interface{}{2: uint64(170000)}
+			data, err := cbor.Marshal([]interface{}{body, map[uint64]interface{}{}, true, tc.AuxData})
+			assert.Nil(t, err)
+
+			file, err := json.Marshal(map[string]string{"cborHex": hex.EncodeToString(data)})
+			assert.Nil(t, err)
+
+			tx, err := ParseTx(file)
+			assert.Nil(t, err)
+			assert.Len(t, tx.Metadata, 2)
+
+			got, err := tx.Metadata.JSON(MetadataNoSchema)
+			assert.Nil(t, err)
+			assert.Equal(t, `{"1":"0xcafe","674":{"msg":["hello",42]}}`, string(got))
+
+			got, err = tx.Metadata.JSON(MetadataDetailedSchema)
+			assert.Nil(t, err)
+			assert.Equal(t, `{"1":{"bytes":"cafe"},"674":{"map":[{"k":{"string":"msg"},"v":{"list":[{"string":"hello"},{"int":42}]}}]}}`, string(got))
+		})
+	}
+}
+
+func TestValidateMetadataJSON(t *testing.T) {
+	assert.Nil(t, ValidateMetadataJSON([]byte(`{"674": {"msg": ["hello"]}}`), MetadataNoSchema))
+	assert.Nil(t, ValidateMetadataJSON([]byte(`{"674": {"string": "hello"}}`), MetadataDetailedSchema))
+	assert.NotNil(t, ValidateMetadataJSON([]byte(`{"msg": "hello"}`), MetadataNoSchema))
+	assert.NotNil(t, ValidateMetadataJSON([]byte(`["hello"]`), MetadataNoSchema))
+	assert.NotNil(t, ValidateMetadataJSON([]byte(`{"674": "hello"}`), "bogus"))
+}
@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// well known network names
+const (
+	NetworkMainnet = "mainnet"
+	NetworkPreprod = "preprod"
+	NetworkPreview = "preview"
+	NetworkTestnet = "testnet" // NetworkTestnet identifies a private testnet with a custom magic
+)
+
+// Network describes the network the toolkit targets.  Every cardano-cli command
+// that talks to the node or builds addresses selects the network using Args.
+type Network struct {
+	Name           string // Name of the network e.g. mainnet, preprod, preview, or testnet
+	Mainnet        bool   // Mainnet selects --mainnet rather than --testnet-magic
+	Magic          string // Magic holds the network magic
+	Era            string // Era optionally pins the era commands are built for; detected from the tip when empty
+	ByronGenesis   string // ByronGenesis optionally holds the path to the byron genesis file
+	ShelleyGenesis string // ShelleyGenesis optionally holds the path to the shelley genesis file
+	AlonzoGenesis  string // AlonzoGenesis optionally holds the path to the alonzo genesis file
+}
+
+// networks holds the well known network profiles
+var networks = map[string]Network{
+	NetworkMainnet: {Name: NetworkMainnet, Mainnet: true, Magic: "764824073"},
+	NetworkPreprod: {Name: NetworkPreprod, Magic: "1"},
+	NetworkPreview: {Name: NetworkPreview, Magic: "2"},
+}
+
+// LookupNetwork returns the profile for the named network.  magic is only used
+// by private testnets, testnet, which require it.
+func LookupNetwork(name, magic string) (Network, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == NetworkTestnet {
+		if magic == "" {
+			return Network{}, fmt.Errorf("invalid network, %v: testnet magic required", NetworkTestnet)
+		}
+		return Network{Name: NetworkTestnet, Magic: magic}, nil
+	}
+
+	network, ok := networks[name]
+	if !ok {
+		return Network{}, fmt.Errorf("unknown network, %v: expected one of %v", name, strings.Join(NetworkNames(), ", "))
+	}
+	return network, nil
+}
+
+// NetworkNames returns the names of the supported networks
+func NetworkNames() []string {
+	names := []string{NetworkTestnet}
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Args returns the cardano-cli arguments that select the network
+func (n Network) Args() []string {
+	if n.Mainnet {
+		return []string{"--mainnet"}
+	}
+	return []string{"--testnet-magic", n.Magic}
+}
+
+// AddressPrefix returns the bech32 prefix of payment addresses on the network
+func (n Network) AddressPrefix() string {
+	if n.Mainnet {
+		return "addr"
+	}
+	return "addr_test"
+}
+
+// StakeAddressPrefix returns the bech32 prefix of stake addresses on the network
+func (n Network) StakeAddressPrefix() string {
+	if n.Mainnet {
+		return "stake"
+	}
+	return "stake_test"
+}
+
+// ValidateAddress ensures bech32 payment and stake addresses belong to the
+// network.  Other strings, e.g. wallet names or byron addresses, are accepted.
+func (n Network) ValidateAddress(address string) error {
+	i := strings.LastIndex(address, "1")
+	if i <= 0 {
+		return nil
+	}
+
+	switch prefix := address[:i]; prefix {
+	case "addr", "addr_test":
+		if prefix != n.AddressPrefix() {
+			return fmt.Errorf("address, %v, does not belong to network, %v", address, n.Name)
+		}
+	case "stake", "stake_test":
+		if prefix != n.StakeAddressPrefix() {
+			return fmt.Errorf("stake address, %v, does not belong to network, %v", address, n.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cardano
+
+import (
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestLookupNetwork(t *testing.T) {
+	network, err := LookupNetwork("mainnet", "42")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"--mainnet"}, network.Args())
+	assert.Equal(t, "addr", network.AddressPrefix())
+	assert.Equal(t, "stake", network.StakeAddressPrefix())
+
+	network, err = LookupNetwork("preview", "42")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"--testnet-magic", "2"}, network.Args())
+
+	network, err = LookupNetwork("testnet", "42")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"--testnet-magic", "42"}, network.Args())
+	assert.Equal(t, "addr_test", network.AddressPrefix())
+
+	_, err = LookupNetwork("testnet", "")
+	assert.NotNil(t, err)
+
+	_, err = LookupNetwork("bogus", "42")
+	assert.NotNil(t, err)
+}
+
+func TestNetwork_ValidateAddress(t *testing.T) {
+	mainnet, err := LookupNetwork(NetworkMainnet, "")
+	assert.Nil(t, err)
+	testnet, err := LookupNetwork(NetworkTestnet, "42")
+	assert.Nil(t, err)
+
+	testCases := map[string]struct {
+		Network Network
+		Address string
+		Valid   bool
+	}{
+		"mainnet addr":          {Network: mainnet, Address: "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8", Valid: true},
+		"mainnet test":          {Network: mainnet, Address: "addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz"},
+		"testnet addr":          {Network: testnet, Address: "addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz", Valid: true},
+		"testnet mainnet":       {Network: testnet, Address: "addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8"},
+		"testnet stake":         {Network: testnet, Address: "stake_test1uqfu74w3wh4gfzu8m6e7j987h4lq9r3t7ef5gaw497uu85qsqfy27", Valid: true},
+		"testnet mainnet stake": {Network: testnet, Address: "stake1uyfu74w3wh4gfzu8m6e7j987h4lq9r3t7ef5gaw497uu85q0z0ufp"},
+		"wallet name":           {Network: mainnet, Address: "alice", Valid: true},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			err := tc.Network.ValidateAddress(tc.Address)
+			if tc.Valid {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/savaki/zapctx"
+	"go.uber.org/zap"
+)
+
+const (
+	dirPolicies    = "policies" // dirPolicies contains minting policies
+	suffixPolicy   = ".json"    // suffixPolicy contains suffix for policy records
+	suffixScript   = ".script"  // suffixScript contains suffix for policy scripts
+	policyScriptV1 = "sig"      // policyScriptV1 identifies a single signature policy
+)
+
+// Policy describes a minting policy created by the toolkit.  Both the policy
+// record and the native script are persisted under ${DATA_DIR}/policies so
+// that tokens minted under the policy may later be minted again or burned.
+type Policy struct {
+	ID         string    `json:"id,omitempty"`
+	KeyHash    string    `json:"keyHash,omitempty"`
+	Wallet     string    `json:"wallet,omitempty"`
+	Before     int32     `json:"before,omitempty"` // Before holds optional slot after which the policy is locked
+	ScriptFile string    `json:"-"`
+	CreatedAt  time.Time `json:"createdAt,omitempty"`
+}
+
+// Locked returns true if the policy no longer allows minting at the provided slot
+func (p Policy) Locked(slot int32) bool {
+	return p.Before > 0 && slot >= p.Before
+}
+
+type nativeScript struct {
+	Type    string         `json:"type,omitempty"`
+	KeyHash string         `json:"keyHash,omitempty"`
+	Slot    int32          `json:"slot,omitempty"`
+	Scripts []nativeScript `json:"scripts,omitempty"`
+}
+
+// makePolicyScript returns the native script for the signing key, optionally
+// time locked such that tokens may only be minted or burned prior to slot before
+func makePolicyScript(keyHash string, before int32) nativeScript {
+	sig := nativeScript{
+		Type:    policyScriptV1,
+		KeyHash: keyHash,
+	}
+	if before <= 0 {
+		return sig
+	}
+
+	return nativeScript{
+		Type: "all",
+		Scripts: []nativeScript{
+			{Type: "before", Slot: before},
+			sig,
+		},
+	}
+}
+
+// CreatePolicy creates (or returns the existing) minting policy for the wallet.
+// When before is positive, the policy is time locked to the given slot.
+func (c CLI) CreatePolicy(ctx context.Context, wallet string, before int32) (policy Policy, err error) {
+	defer func(begin time.Time) {
+		zapctx.FromContext(ctx).Info("created policy",
+			zap.Duration("elapsed", time.Since(begin).Round(time.Millisecond)),
+			zap.String("wallet", wallet),
+			zap.String("policyID", policy.ID),
+			zap.Error(err),
+		)
+	}(time.Now())
+
+	keyHash, err := c.KeyHash(ctx, wallet)
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: %w", err)
+	}
+
+	dir := filepath.Join(c.Dir, dirPolicies)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: unable to create directory, %v: %w", dirPolicies, err)
+	}
+
+	f, err := ioutil.TempFile(filepath.Join(c.Dir, "tmp"), "script")
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(makePolicyScript(keyHash, before)); err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: unable to write script: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: unable to write script: %w", err)
+	}
+
+	policyID, err := c.PolicyID(ctx, f.Name())
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: %w", err)
+	}
+
+	// the policy id is derived from the script so an existing policy is always
+	// identical to the one we just generated
+	if existing, err := c.FindPolicy(policyID); err == nil {
+		return existing, nil
+	}
+
+	data, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: unable to read script: %w", err)
+	}
+
+	policy = Policy{
+		ID:         policyID,
+		KeyHash:    keyHash,
+		Wallet:     wallet,
+		Before:     before,
+		ScriptFile: policyScriptFile(c.Dir, policyID),
+		CreatedAt:  time.Now().UTC(),
+	}
+	record, err := json.MarshalIndent(policy, "", "  ")
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: unable to encode policy: %w", err)
+	}
+
+	if err := ioutil.WriteFile(policy.ScriptFile, data, 0644); err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: unable to save script: %w", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, policyID+suffixPolicy), record, 0644); err != nil {
+		return Policy{}, fmt.Errorf("failed to create policy: unable to save policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// FindPolicy returns the stored policy with the given policy id
+func (c CLI) FindPolicy(policyID string) (Policy, error) {
+	policyID = strings.TrimSpace(policyID)
+	if !reID.MatchString(policyID) {
+		return Policy{}, fmt.Errorf("unable to find policy: invalid policy id, %v", policyID)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(c.Dir, dirPolicies, policyID+suffixPolicy))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Policy{}, fmt.Errorf("unable to find policy, %v: policy not found", policyID)
+		}
+		return Policy{}, fmt.Errorf("unable to find policy, %v: %w", policyID, err)
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return Policy{}, fmt.Errorf("unable to find policy, %v: unable to decode policy: %w", policyID, err)
+	}
+	policy.ScriptFile = policyScriptFile(c.Dir, policy.ID)
+
+	return policy, nil
+}
+
+// FindAllPolicies returns all stored policies optionally limited to those
+// belonging to the specified wallet
+func (c CLI) FindAllPolicies(wallet string) ([]Policy, error) {
+	entries, err := os.ReadDir(filepath.Join(c.Dir, dirPolicies))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find all policies: %w", err)
+	}
+
+	var policies []Policy
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, suffixPolicy) {
+			continue
+		}
+
+		policy, err := c.FindPolicy(strings.TrimSuffix(name, suffixPolicy))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find all policies: %w", err)
+		}
+		if wallet != "" && policy.Wallet != wallet {
+			continue
+		}
+
+		policies = append(policies, policy)
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].CreatedAt.Before(policies[j].CreatedAt)
+	})
+
+	return policies, nil
+}
+
+func policyScriptFile(dir, policyID string) string {
+	return filepath.Join(dir, dirPolicies, policyID+suffixScript)
+}
@@ -0,0 +1,59 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestMakePolicyScript(t *testing.T) {
+	data, err := json.Marshal(makePolicyScript("abc", 0))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"sig","keyHash":"abc"}`, string(data))
+
+	data, err = json.Marshal(makePolicyScript("abc", 1000))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"all","scripts":[{"type":"before","slot":1000},{"type":"sig","keyHash":"abc"}]}`, string(data))
+}
+
+func TestFindAllPolicies(t *testing.T) {
+	cli := &CLI{Dir: "testdata"}
+	policies, err := cli.FindAllPolicies("")
+	assert.Nil(t, err)
+	assert.Len(t, policies, 2)
+
+	policies, err = cli.FindAllPolicies("sample")
+	assert.Nil(t, err)
+	assert.Len(t, policies, 1)
+
+	policy := policies[0]
+	assert.Equal(t, "5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c", policy.ID)
+	assert.Equal(t, "testdata/policies/5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c.script", policy.ScriptFile)
+	assert.False(t, policy.Locked(100))
+
+	_, err = cli.FindPolicy("../wallets/sample")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"context"
+	"time"
+)
+
+// TxKind describes why the toolkit submitted a tx
+type TxKind string
+
+const (
+	TxKindBurn        TxKind = "burn"
+	TxKindConsolidate TxKind = "consolidate" // TxKindConsolidate identifies txs merging small treasury utxos
+	TxKindDelegate    TxKind = "delegate"
+	TxKindFund        TxKind = "fund"
+	TxKindMint        TxKind = "mint"
+	TxKindRegister    TxKind = "register"
+	TxKindSend        TxKind = "send"
+	TxKindSplit       TxKind = "split"
+	TxKindSubmit      TxKind = "submit" // TxKindSubmit identifies txs built and signed by clients
+	TxKindSweep       TxKind = "sweep"  // TxKindSweep identifies txs moving genesis funds into the treasury
+)
+
+type txKindKey struct{}
+
+// WithTxKind returns a context that records txs submitted with it as kind
+func WithTxKind(ctx context.Context, kind TxKind) context.Context {
+	return context.WithValue(ctx, txKindKey{}, kind)
+}
+
+// TxKindFromContext returns the kind associated with ctx, TxKindSubmit by default
+func TxKindFromContext(ctx context.Context) TxKind {
+	if kind, ok := ctx.Value(txKindKey{}).(TxKind); ok {
+		return kind
+	}
+	return TxKindSubmit
+}
+
+// SubmittedTx describes a tx successfully submitted by the toolkit
+type SubmittedTx struct {
+	Tx
+	Kind      TxKind
+	Signed    []byte // Signed holds the signed tx envelope as submitted
+	Submitted time.Time
+}
+
+// TxRecorder records the txs submitted by the toolkit
+type TxRecorder interface {
+	RecordTx(ctx context.Context, tx SubmittedTx) error
+}
@@ -31,6 +31,7 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -53,12 +54,15 @@ type txOut struct {
 }
 
 type BuildOptions struct {
-	Fee            string
-	Mint           string
-	MintScriptFile string
-	TxIn           []txIn
-	TxOut          []txOut
-	Certificates   []string
+	Fee              string
+	InvalidHereafter int32
+	Metadata         []byte
+	MetadataSchema   MetadataSchema
+	Mint             string
+	MintScriptFile   string
+	TxIn             []txIn
+	TxOut            []txOut
+	Certificates     []string
 }
 
 func MakeBuildOptions(opts ...BuildOption) BuildOptions {
@@ -69,6 +73,9 @@ func MakeBuildOptions(opts ...BuildOption) BuildOptions {
 	if options.Fee == "" {
 		options.Fee = "0"
 	}
+	if options.MetadataSchema == "" {
+		options.MetadataSchema = MetadataNoSchema
+	}
 
 	return options
 }
@@ -81,6 +88,22 @@ func Fee(fee string) BuildOption {
 	}
 }
 
+// InvalidHereafter sets the slot after which the transaction is no longer valid;
+// required when minting under a time locked policy
+func InvalidHereafter(slot int32) BuildOption {
+	return func(options *BuildOptions) {
+		options.InvalidHereafter = slot
+	}
+}
+
+// MetadataJSON attaches the json encoded transaction metadata to the transaction
+func MetadataJSON(data []byte, schema MetadataSchema) BuildOption {
+	return func(options *BuildOptions) {
+		options.Metadata = data
+		options.MetadataSchema = schema
+	}
+}
+
 func Mint(s string) BuildOption {
 	return func(options *BuildOptions) {
 		options.Mint = s
@@ -124,11 +147,19 @@ func (c CLI) Build(opts ...BuildOption) ([]byte, error) {
 		defer func() { os.Remove(filename) }()
 	}
 
+	era, err := c.Era()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tx: %w", err)
+	}
+
 	options := MakeBuildOptions(opts...)
+	if !era.MultiAsset && options.Mint != "" {
+		return nil, fmt.Errorf("failed to build tx: %v era does not support minting", era.Name)
+	}
 	args := []string{
 		"transaction", "build-raw",
 		"--fee", options.Fee,
-		"--alonzo-era",
+		era.Flag,
 		"--out-file", filename,
 	}
 
@@ -143,12 +174,23 @@ func (c CLI) Build(opts ...BuildOption) ([]byte, error) {
 
 		output := fmt.Sprintf("%v+%v", address, in.Quantity)
 		if len(in.Tokens) > 0 {
-			output += "+" + strings.Join(in.Tokens, "+")
+			if !era.MultiAsset {
+				return nil, fmt.Errorf("failed to build tx: %v era does not support native tokens", era.Name)
+			}
+			tokens, err := formatCLIValue(strings.Join(in.Tokens, "+"), c.AssetNameFormat)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build tx: %w", err)
+			}
+			output += "+" + tokens
 		}
 		args = append(args, "--tx-out", output)
 	}
 	if options.Mint != "" {
-		args = append(args, "--mint="+options.Mint)
+		mint, err := formatCLIValue(options.Mint, c.AssetNameFormat)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build tx: %w", err)
+		}
+		args = append(args, "--mint="+mint)
 	}
 	if options.MintScriptFile != "" {
 		args = append(args, "--mint-script-file="+options.MintScriptFile)
@@ -156,6 +198,19 @@ func (c CLI) Build(opts ...BuildOption) ([]byte, error) {
 	for _, in := range options.Certificates {
 		args = append(args, "--certificate-file="+in)
 	}
+	if options.InvalidHereafter > 0 {
+		args = append(args, "--invalid-hereafter", strconv.FormatInt(int64(options.InvalidHereafter), 10))
+	}
+	if len(options.Metadata) > 0 {
+		metadata := filename + ".metadata.json"
+		if !c.Debug {
+			defer func() { os.Remove(metadata) }()
+		}
+		if err := ioutil.WriteFile(metadata, options.Metadata, 0644); err != nil {
+			return nil, fmt.Errorf("failed to build tx: unable to write metadata: %w", err)
+		}
+		args = append(args, "--json-metadata-"+string(options.MetadataSchema), "--metadata-json-file", metadata)
+	}
 
 	fmt.Println()
 	fmt.Println(strings.Join(c.Cmd, " "), strings.Join(args, " "))
@@ -181,6 +236,20 @@ func (c CLI) Sign(ctx context.Context, raw []byte, wallets ...string) (data []by
 		)
 	}(time.Now())
 
+	var signingKeyFiles []string
+	for _, wallet := range wallets {
+		switch wallet {
+		case "":
+			signingKeyFiles = append(signingKeyFiles, c.TreasurySkeyFile)
+		default:
+			signingKeyFiles = append(signingKeyFiles, fmt.Sprintf("%v/%v/%v.skey", c.Dir, dirWallets, wallet))
+		}
+	}
+	return c.signWithKeys(raw, signingKeyFiles...)
+}
+
+// signWithKeys signs the raw tx body with the given signing key files
+func (c CLI) signWithKeys(raw []byte, signingKeyFiles ...string) ([]byte, error) {
 	filename := filepath.Join(c.Dir, "tmp", ksuid.New().String())
 	if !c.Debug {
 		defer func() { os.Remove(filename) }()
@@ -195,14 +264,7 @@ func (c CLI) Sign(ctx context.Context, raw []byte, wallets ...string) (data []by
 		"--tx-body-file", filename,
 		"--out-file", filename,
 	}
-	for _, wallet := range wallets {
-		var signingKeyFile string
-		switch wallet {
-		case "":
-			signingKeyFile = c.TreasurySkeyFile
-		default:
-			signingKeyFile = fmt.Sprintf("%v/%v/%v.skey", c.Dir, dirWallets, wallet)
-		}
+	for _, signingKeyFile := range signingKeyFiles {
 		args = append(args, "--signing-key-file", signingKeyFile)
 	}
 
@@ -210,7 +272,7 @@ func (c CLI) Sign(ctx context.Context, raw []byte, wallets ...string) (data []by
 		return nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	data, err = ioutil.ReadFile(filename)
+	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign transaction: unable to read file, %v: %w", filename, err)
 	}
@@ -238,48 +300,86 @@ func (c CLI) Submit(ctx context.Context, signed []byte) (err error) {
 	args := []string{
 		"transaction", "submit",
 		"--cardano-mode",
-		"--testnet-magic", c.TestnetMagic,
 		"--tx-file", filename,
 	}
+	args = append(args, c.Network.Args()...)
 	if _, err := c.exec(args...); err != nil {
 		return fmt.Errorf("failed to submit tx: %w", err)
 	}
 
+	if c.History != nil {
+		c.recordTx(ctx, signed)
+	}
+
 	return nil
 }
 
-func (c CLI) transferFunds(ctx context.Context, utxo Utxo, address, quantity string) (Tx, error) {
+// recordTx records the submitted tx; failures are logged as the tx has
+// already been submitted
+func (c CLI) recordTx(ctx context.Context, signed []byte) {
+	tx, err := ParseTx(signed)
+	if err == nil {
+		err = c.History.RecordTx(ctx, SubmittedTx{
+			Tx:        tx,
+			Kind:      TxKindFromContext(ctx),
+			Signed:    signed,
+			Submitted: time.Now(),
+		})
+	}
+	if err != nil {
+		zapctx.FromContext(ctx).Info("failed to record tx", zap.String("tx", tx.ID), zap.Error(err))
+	}
+}
+
+// Payout describes lovelace sent from the treasury to an address
+type Payout struct {
+	Address  string
+	Quantity string
+}
+
+// transferFunds spends the treasury utxo paying out to each address.  The
+// change is returned to the treasury at output 0 so payout i is found at
+// output i+1 of the returned tx.
+func (c CLI) transferFunds(ctx context.Context, utxo Utxo, payouts []Payout, opts ...BuildOption) (Tx, error) {
 	total := &big.Int{}
 	total, ok := total.SetString(utxo.Value, 10)
 	if !ok {
 		return Tx{}, fmt.Errorf("failed to transfer funds: unable to parse source utxo value, %v", utxo.Value)
 	}
 
-	q := &big.Int{}
-	q, ok = q.SetString(quantity, 10)
-	if !ok {
-		return Tx{}, fmt.Errorf("failed to transfer funds: unable to parse desired quantity, %v", quantity)
+	remainder := big.NewInt(0).Set(total)
+	for _, payout := range payouts {
+		q, ok := big.NewInt(0).SetString(payout.Quantity, 10)
+		if !ok {
+			return Tx{}, fmt.Errorf("failed to transfer funds: unable to parse desired quantity, %v", payout.Quantity)
+		}
+		remainder.Sub(remainder, q)
 	}
 
-	remainder := &big.Int{}
-	remainder = remainder.Sub(total, q)
+	build := func(fee string, remainder *big.Int) ([]byte, error) {
+		options := []BuildOption{
+			Fee(fee),
+			TxIn(utxo.Address, utxo.Index),
+			TxOut(c.TreasuryAddr, remainder.Text(10)),
+		}
+		for _, payout := range payouts {
+			options = append(options, TxOut(payout.Address, payout.Quantity))
+		}
+		return c.Build(append(options, opts...)...)
+	}
 
-	raw, err := c.Build(
-		TxIn(utxo.Address, utxo.Index),
-		TxOut(c.TreasuryAddr, remainder.Text(10)),
-		TxOut(address, quantity),
-	)
+	raw, err := build("0", remainder)
 	if err != nil {
 		return Tx{}, fmt.Errorf("failed to transfer funds: %w", err)
 	}
 
 	filename := filepath.Join(c.Dir, "tmp", ksuid.New().String())
 	defer os.Remove(filename)
-	if _ = ioutil.WriteFile(filename, raw, 0644); err != nil {
+	if err := ioutil.WriteFile(filename, raw, 0644); err != nil {
 		return Tx{}, fmt.Errorf("failed to write raw tx body: %w", err)
 	}
 
-	feeStr, err := c.MinFee(ctx, filename, 1, 2, 1)
+	feeStr, err := c.MinFee(ctx, filename, 1, int32(len(payouts)+1), 1)
 	if err != nil {
 		return Tx{}, fmt.Errorf("failed to transfer funds: %w", err)
 	}
@@ -291,12 +391,7 @@ func (c CLI) transferFunds(ctx context.Context, utxo Utxo, address, quantity str
 
 	remainder = big.NewInt(0).Sub(remainder, fee)
 
-	raw, err = c.Build(
-		Fee(feeStr),
-		TxIn(utxo.Address, utxo.Index),
-		TxOut(c.TreasuryAddr, remainder.Text(10)),
-		TxOut(address, quantity),
-	)
+	raw, err = build(feeStr, remainder)
 	if err != nil {
 		return Tx{}, fmt.Errorf("failed to transfer funds: %w", err)
 	}
@@ -311,7 +406,7 @@ func (c CLI) transferFunds(ctx context.Context, utxo Utxo, address, quantity str
 		return Tx{}, fmt.Errorf("failed to parse transaction: %w", err)
 	}
 
-	if err := c.Submit(ctx, signed); err != nil {
+	if err := c.Submit(WithTxKind(ctx, TxKindFund), signed); err != nil {
 		return Tx{}, fmt.Errorf("failed to transfer funds: %w", err)
 	}
 
@@ -319,7 +414,12 @@ func (c CLI) transferFunds(ctx context.Context, utxo Utxo, address, quantity str
 }
 
 type Tx struct {
-	ID string
+	ID       string
+	Fee      string     // Fee paid by the tx in lovelace
+	Inputs   []string   // Inputs consumed by the tx as hash#index
+	Outputs  []TxOutput // Outputs created by the tx
+	Mint     []Token    // Mint holds the tokens minted by the tx, negative when burned
+	Metadata Metadata
 }
 
 func ParseTx(data []byte) (Tx, error) {
@@ -347,8 +447,27 @@ func ParseTx(data []byte) (Tx, error) {
 		return Tx{}, fmt.Errorf("failed to write record body: %w", err)
 	}
 
+	// auxiliary data, when present, is always the final element of both the tx
+	// body envelope and the signed tx
+	var metadata Metadata
+	if n := len(record); n > 1 {
+		if metadata, err = decodeAuxData(record[n-1]); err != nil {
+			return Tx{}, fmt.Errorf("failed to parse tx: %w", err)
+		}
+	}
+
+	body, err := decodeTxBody(record[0])
+	if err != nil {
+		return Tx{}, fmt.Errorf("failed to parse tx: %w", err)
+	}
+
 	hash := h.Sum(nil)
 	return Tx{
-		ID: hex.EncodeToString(hash),
+		ID:       hex.EncodeToString(hash),
+		Fee:      body.Fee,
+		Inputs:   body.Inputs,
+		Outputs:  body.Outputs,
+		Mint:     body.Mint,
+		Metadata: metadata,
 	}, nil
 }
@@ -0,0 +1,677 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/savaki/zapctx"
+	"github.com/segmentio/ksuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultFanOut is the number of utxos the treasury is pre-split into
+	defaultFanOut = 50
+
+	// defaultFanOutLovelace is the value of each pre-split treasury utxo
+	defaultFanOutLovelace = 10_000_000_000
+
+	// treasuryMargin covers the fee and min ada change for a treasury transfer
+	treasuryMargin = 2_000_000
+
+	// treasuryInflightTTL is how long a reserved input is held before it is
+	// assumed the transaction that spent it was dropped
+	treasuryInflightTTL = 5 * time.Minute
+
+	// treasuryPollInterval is how often to check for an available input while waiting
+	treasuryPollInterval = time.Second
+
+	// treasuryWait is the maximum time to wait for an available input
+	treasuryWait = 90 * time.Second
+)
+
+// TreasuryOption configures a TreasuryPool
+type TreasuryOption func(*TreasuryPool)
+
+// FanOut sets the number of utxos the treasury is pre-split into
+func FanOut(n int) TreasuryOption {
+	return func(t *TreasuryPool) {
+		if n > 0 {
+			t.fanOut = n
+		}
+	}
+}
+
+// FanOutLovelace sets the lovelace held by each pre-split treasury utxo
+func FanOutLovelace(lovelace int64) TreasuryOption {
+	return func(t *TreasuryPool) {
+		if lovelace > 0 {
+			t.fanOutLovelace = big.NewInt(lovelace)
+		}
+	}
+}
+
+// TreasuryPool tracks the treasury inputs spent by in-flight transactions so
+// that concurrent requests are each handed a distinct input.  When the number
+// of free inputs runs low, the largest treasury utxo is split into many
+// fan-out utxos.
+type TreasuryPool struct {
+	fanOut         int
+	fanOutLovelace *big.Int
+
+	mutex     sync.Mutex
+	inflight  map[string]reservation // inflight holds txIn -> reservation
+	splitting bool
+}
+
+// reservation records when and from which source address an input was reserved;
+// treasury inputs have an empty source
+type reservation struct {
+	source string
+	at     time.Time
+}
+
+// NewTreasuryPool returns a new TreasuryPool
+func NewTreasuryPool(opts ...TreasuryOption) *TreasuryPool {
+	t := &TreasuryPool{
+		fanOut:         defaultFanOut,
+		fanOutLovelace: big.NewInt(defaultFanOutLovelace),
+		inflight:       map[string]reservation{},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// reserve selects the smallest free utxo able to cover required and marks it
+// in-flight.  While the largest utxo can be split, it is held back for
+// splitting unless required exceeds the fan-out value.  reserve also reports
+// whether the pool should be split.
+func (t *TreasuryPool) reserve(utxos Utxos, required *big.Int, now time.Time) (selected Utxo, ok, split bool) {
+	t.prune("", utxos, now)
+
+	free := t.free(utxos, false)
+	if len(free) == 0 {
+		return Utxo{}, false, false
+	}
+
+	var (
+		largest    = free[len(free)-1]
+		splittable = t.splittable(largest)
+		candidates = free
+	)
+	if splittable && required.Cmp(t.fanOutLovelace) <= 0 {
+		candidates = free[:len(free)-1]
+	}
+
+	for _, utxo := range candidates {
+		if value, _ := big.NewInt(0).SetString(utxo.Value, 10); value.Cmp(required) >= 0 {
+			selected, ok = utxo, true
+			t.inflight[utxo.TxIn()] = reservation{at: now}
+			break
+		}
+	}
+
+	remaining := len(free) - 1
+	if ok {
+		remaining--
+	}
+	split = splittable && !t.splitting && remaining < t.fanOut/4
+
+	return selected, ok, split
+}
+
+// splittable returns true if the utxo holds enough lovelace to be split
+func (t *TreasuryPool) splittable(utxo Utxo) bool {
+	value, ok := big.NewInt(0).SetString(utxo.Value, 10)
+	if !ok {
+		return false
+	}
+	min := big.NewInt(0).Mul(t.fanOutLovelace, big.NewInt(3))
+	return value.Cmp(min.Add(min, big.NewInt(treasuryMargin))) >= 0
+}
+
+// reserveLargest marks the largest free utxo in-flight for splitting
+func (t *TreasuryPool) reserveLargest(utxos Utxos, now time.Time) (Utxo, bool) {
+	t.prune("", utxos, now)
+
+	free := t.free(utxos, false)
+	if len(free) == 0 {
+		return Utxo{}, false
+	}
+
+	utxo := free[len(free)-1]
+	t.inflight[utxo.TxIn()] = reservation{at: now}
+	return utxo, true
+}
+
+// reserveValue selects free utxos from source, "" for the treasury, holding
+// the requested tokens and, together, at least lovelace and marks them in-flight
+func (t *TreasuryPool) reserveValue(source string, utxos Utxos, lovelace *big.Int, tokens map[string]*big.Int, now time.Time) (Utxos, error) {
+	t.prune(source, utxos, now)
+
+	var (
+		free     = t.free(utxos, true)
+		selected Utxos
+		value    = NewValue()
+	)
+	covered := func() bool {
+		for assetID, quantity := range tokens {
+			if value.Token(assetID).Cmp(quantity) < 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, utxo := range free {
+		if covered() {
+			break
+		}
+		needed := false
+		for _, token := range utxo.Tokens {
+			if q, ok := tokens[token.Asset.ID()]; ok && value.Token(token.Asset.ID()).Cmp(q) < 0 {
+				needed = true
+			}
+		}
+		if !needed {
+			continue
+		}
+		if err := value.Add(utxo); err != nil {
+			return nil, err
+		}
+		selected = append(selected, utxo)
+	}
+	if !covered() {
+		return nil, fmt.Errorf("insufficient tokens available")
+	}
+
+	for _, utxo := range free {
+		if value.Lovelace.Cmp(lovelace) >= 0 {
+			break
+		}
+		if len(utxo.Tokens) > 0 {
+			continue
+		}
+		if err := value.Add(utxo); err != nil {
+			return nil, err
+		}
+		selected = append(selected, utxo)
+	}
+	if value.Lovelace.Cmp(lovelace) < 0 {
+		return nil, fmt.Errorf("insufficient lovelace available")
+	}
+
+	for _, utxo := range selected {
+		t.inflight[utxo.TxIn()] = reservation{source: source, at: now}
+	}
+	return selected, nil
+}
+
+// release frees reserved inputs whose transaction was never submitted
+func (t *TreasuryPool) release(utxos ...Utxo) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, utxo := range utxos {
+		delete(t.inflight, utxo.TxIn())
+	}
+}
+
+// prune removes in-flight inputs from source that have been spent as well as
+// any reservation that has expired
+func (t *TreasuryPool) prune(source string, utxos Utxos, now time.Time) {
+	unspent := map[string]struct{}{}
+	for _, utxo := range utxos {
+		unspent[utxo.TxIn()] = struct{}{}
+	}
+	for txIn, r := range t.inflight {
+		if now.Sub(r.at) > treasuryInflightTTL {
+			delete(t.inflight, txIn)
+			continue
+		}
+		if _, ok := unspent[txIn]; !ok && r.source == source {
+			delete(t.inflight, txIn)
+		}
+	}
+}
+
+// free returns the utxos not in-flight sorted from smallest to largest
+// lovelace; utxos holding tokens are only included when withTokens is true
+func (t *TreasuryPool) free(utxos Utxos, withTokens bool) Utxos {
+	var free Utxos
+	for _, utxo := range utxos {
+		if len(utxo.Tokens) > 0 && !withTokens {
+			continue
+		}
+		if _, ok := t.inflight[utxo.TxIn()]; ok {
+			continue
+		}
+		if _, ok := big.NewInt(0).SetString(utxo.Value, 10); !ok {
+			continue
+		}
+		free = append(free, utxo)
+	}
+
+	sort.SliceStable(free, func(i, j int) bool {
+		a, _ := big.NewInt(0).SetString(free[i].Value, 10)
+		b, _ := big.NewInt(0).SetString(free[j].Value, 10)
+		return a.Cmp(b) < 0
+	})
+	return free
+}
+
+// reserveTreasuryUtxo returns a treasury utxo able to cover quantity lovelace
+// plus fees that no other in-flight request is spending.  The returned release
+// func must be called with false if the transaction is never submitted.
+func (c CLI) reserveTreasuryUtxo(ctx context.Context, quantity string) (Utxo, func(submitted bool), error) {
+	required, ok := big.NewInt(0).SetString(quantity, 10)
+	if !ok {
+		return Utxo{}, nil, fmt.Errorf("unable to reserve treasury utxo: invalid quantity, %v", quantity)
+	}
+	required.Add(required, big.NewInt(treasuryMargin))
+
+	// without a pool, fall back to the first sufficient utxo
+	if c.Treasury == nil {
+		utxos, err := c.Utxos(c.TreasuryAddr)
+		if err != nil {
+			return Utxo{}, nil, fmt.Errorf("unable to reserve treasury utxo: %w", err)
+		}
+		for _, utxo := range utxos {
+			if value, ok := big.NewInt(0).SetString(utxo.Value, 10); ok && len(utxo.Tokens) == 0 && value.Cmp(required) >= 0 {
+				return utxo, func(bool) {}, nil
+			}
+		}
+		return Utxo{}, nil, fmt.Errorf("unable to reserve treasury utxo: insufficient funds in treasury addr, %v", c.TreasuryAddr)
+	}
+
+	t := c.Treasury
+	timer := time.NewTimer(treasuryWait)
+	defer timer.Stop()
+
+	for {
+		utxos, err := c.Utxos(c.TreasuryAddr)
+		if err != nil {
+			return Utxo{}, nil, fmt.Errorf("unable to reserve treasury utxo: %w", err)
+		}
+		if len(utxos) == 0 {
+			return Utxo{}, nil, fmt.Errorf("unable to reserve treasury utxo: treasury addr is empty, %v", c.TreasuryAddr)
+		}
+
+		t.mutex.Lock()
+		utxo, ok, split := t.reserve(utxos, required, time.Now())
+		pending := len(t.inflight) > 0 || t.splitting || split
+		if split {
+			t.splitting = true
+		}
+		t.mutex.Unlock()
+
+		if split {
+			go c.splitTreasury(zapctx.NewContext(context.Background(), zapctx.FromContext(ctx)))
+		}
+		if ok {
+			release := func(submitted bool) {
+				if !submitted {
+					t.release(utxo)
+				}
+			}
+			return utxo, release, nil
+		}
+		if !pending {
+			return Utxo{}, nil, fmt.Errorf("unable to reserve treasury utxo: insufficient funds in treasury addr, %v", c.TreasuryAddr)
+		}
+
+		// change from in-flight transactions or a pending split will free up inputs
+		select {
+		case <-ctx.Done():
+			return Utxo{}, nil, ctx.Err()
+		case <-timer.C:
+			return Utxo{}, nil, fmt.Errorf("unable to reserve treasury utxo: timed out waiting for an available input")
+		case <-time.After(treasuryPollInterval):
+		}
+	}
+}
+
+// splitTreasury splits the largest treasury utxo into fan-out utxos
+func (c CLI) splitTreasury(ctx context.Context) {
+	t := c.Treasury
+	defer func() {
+		t.mutex.Lock()
+		t.splitting = false
+		t.mutex.Unlock()
+	}()
+
+	tx, n, err := c.SplitTreasury(ctx)
+	zapctx.FromContext(ctx).Info("split treasury",
+		zap.String("tx", tx.ID),
+		zap.Int("outputs", n),
+		zap.Error(err),
+	)
+}
+
+// SplitTreasury splits the largest free treasury utxo into fan-out utxos,
+// returning the split transaction and the number of fan-out utxos created
+func (c CLI) SplitTreasury(ctx context.Context) (Tx, int, error) {
+	t := c.Treasury
+	if t == nil {
+		t = NewTreasuryPool()
+	}
+
+	utxos, err := c.Utxos(c.TreasuryAddr)
+	if err != nil {
+		return Tx{}, 0, fmt.Errorf("failed to split treasury: %w", err)
+	}
+
+	t.mutex.Lock()
+	utxo, ok := t.reserveLargest(utxos, time.Now())
+	t.mutex.Unlock()
+	if !ok {
+		return Tx{}, 0, fmt.Errorf("failed to split treasury: no free treasury utxo")
+	}
+
+	tx, n, err := c.splitUtxo(ctx, utxo, t.fanOut, t.fanOutLovelace)
+	if err != nil {
+		t.release(utxo)
+		return Tx{}, 0, fmt.Errorf("failed to split treasury: %w", err)
+	}
+	return tx, n, nil
+}
+
+// splitUtxo spends utxo into up to n outputs of lovelace each plus change
+func (c CLI) splitUtxo(ctx context.Context, utxo Utxo, n int, lovelace *big.Int) (Tx, int, error) {
+	total, ok := big.NewInt(0).SetString(utxo.Value, 10)
+	if !ok {
+		return Tx{}, 0, fmt.Errorf("unable to parse utxo value, %v", utxo.Value)
+	}
+
+	// retain enough change to cover the fee and a future split
+	available := big.NewInt(0).Sub(total, big.NewInt(treasuryMargin))
+	if max := big.NewInt(0).Div(available, lovelace).Int64() - 1; int64(n) > max {
+		n = int(max)
+	}
+	if n < 2 {
+		return Tx{}, 0, fmt.Errorf("utxo, %v, too small to split", utxo.TxIn())
+	}
+
+	build := func(fee *big.Int) ([]byte, error) {
+		change := big.NewInt(0).Sub(total, fee)
+		opts := []BuildOption{
+			Fee(fee.String()),
+			TxIn(utxo.Address, utxo.Index),
+		}
+		for i := 0; i < n; i++ {
+			opts = append(opts, TxOut(c.TreasuryAddr, lovelace.String()))
+			change.Sub(change, lovelace)
+		}
+		opts = append(opts, TxOut(c.TreasuryAddr, change.String()))
+		return c.Build(opts...)
+	}
+
+	raw, err := build(big.NewInt(0))
+	if err != nil {
+		return Tx{}, 0, err
+	}
+
+	filename := filepath.Join(c.Dir, "tmp", ksuid.New().String())
+	defer os.Remove(filename)
+	if err := ioutil.WriteFile(filename, raw, 0644); err != nil {
+		return Tx{}, 0, fmt.Errorf("failed to write raw tx body: %w", err)
+	}
+
+	feeStr, err := c.MinFee(ctx, filename, 1, int32(n+1), 1)
+	if err != nil {
+		return Tx{}, 0, err
+	}
+	fee, err := strconv.ParseInt(feeStr, 10, 64)
+	if err != nil {
+		return Tx{}, 0, fmt.Errorf("failed to parse fee, %v", feeStr)
+	}
+
+	raw, err = build(big.NewInt(fee))
+	if err != nil {
+		return Tx{}, 0, err
+	}
+
+	signed, err := c.Sign(ctx, raw, "")
+	if err != nil {
+		return Tx{}, 0, err
+	}
+
+	tx, err := ParseTx(signed)
+	if err != nil {
+		return Tx{}, 0, fmt.Errorf("failed to parse transaction: %w", err)
+	}
+
+	if err := c.Submit(WithTxKind(ctx, TxKindSplit), signed); err != nil {
+		return Tx{}, 0, err
+	}
+
+	return tx, n, nil
+}
+
+// TreasuryStatus reports the balance and fragmentation of the treasury
+type TreasuryStatus struct {
+	Address      string
+	Lovelace     string // Lovelace held by the treasury
+	Utxos        int    // Utxos is the number of treasury utxos
+	InFlight     int    // InFlight is the number of utxos reserved by in-flight txs
+	Fundable     int    // Fundable is the number of free ada only utxos able to cover a payout
+	TokenUtxos   int    // TokenUtxos is the number of utxos holding native tokens
+	Dust         int    // Dust is the number of ada only utxos smaller than the fan-out value
+	DustLovelace string // DustLovelace is the lovelace held by dust utxos
+	Largest      string // Largest holds the lovelace of the largest utxo
+}
+
+// TreasuryStatus returns the balance and fragmentation of the treasury
+func (c CLI) TreasuryStatus() (TreasuryStatus, error) {
+	t := c.Treasury
+	if t == nil {
+		t = NewTreasuryPool()
+	}
+
+	utxos, err := c.Utxos(c.TreasuryAddr)
+	if err != nil {
+		return TreasuryStatus{}, fmt.Errorf("failed to get treasury status: %w", err)
+	}
+
+	var (
+		total   = big.NewInt(0)
+		dust    = big.NewInt(0)
+		largest = big.NewInt(0)
+		status  = TreasuryStatus{Address: c.TreasuryAddr, Utxos: len(utxos)}
+	)
+
+	inflight := map[string]struct{}{}
+	t.mutex.Lock()
+	for _, utxo := range utxos {
+		if _, ok := t.inflight[utxo.TxIn()]; ok {
+			inflight[utxo.TxIn()] = struct{}{}
+		}
+	}
+	t.mutex.Unlock()
+	status.InFlight = len(inflight)
+
+	margin := big.NewInt(treasuryMargin)
+	for _, utxo := range utxos {
+		value, ok := big.NewInt(0).SetString(utxo.Value, 10)
+		if !ok {
+			continue
+		}
+		if _, ok := inflight[utxo.TxIn()]; !ok && len(utxo.Tokens) == 0 && value.Cmp(margin) > 0 {
+			status.Fundable++
+		}
+		total.Add(total, value)
+		if value.Cmp(largest) > 0 {
+			largest = value
+		}
+		switch {
+		case len(utxo.Tokens) > 0:
+			status.TokenUtxos++
+		case value.Cmp(t.fanOutLovelace) < 0:
+			status.Dust++
+			dust.Add(dust, value)
+		}
+	}
+
+	status.Lovelace = total.String()
+	status.DustLovelace = dust.String()
+	status.Largest = largest.String()
+	return status, nil
+}
+
+// ConsolidateTreasury merges up to max free dust utxos, ada only utxos smaller
+// than the fan-out value, into a single treasury utxo returning the tx and the
+// number of utxos merged.  The returned tx is empty when there are fewer than
+// two dust utxos.
+func (c CLI) ConsolidateTreasury(ctx context.Context, max int) (tx Tx, n int, err error) {
+	defer func(begin time.Time) {
+		zapctx.FromContext(ctx).Info("consolidated treasury",
+			zap.String("tx", tx.ID),
+			zap.Int("utxos", n),
+			zap.Duration("elapsed", time.Since(begin).Round(time.Millisecond)),
+			zap.Error(err),
+		)
+	}(time.Now())
+
+	t := c.Treasury
+	if t == nil {
+		t = NewTreasuryPool()
+	}
+	if max < 2 {
+		max = t.fanOut
+	}
+
+	utxos, err := c.Utxos(c.TreasuryAddr)
+	if err != nil {
+		return Tx{}, 0, fmt.Errorf("failed to consolidate treasury: %w", err)
+	}
+
+	t.mutex.Lock()
+	selected := t.reserveDust(utxos, max, time.Now())
+	t.mutex.Unlock()
+	if len(selected) < 2 {
+		t.release(selected...)
+		return Tx{}, 0, nil
+	}
+
+	tx, err = c.transferAll(WithTxKind(ctx, TxKindConsolidate), selected, c.TreasuryAddr, c.TreasurySkeyFile)
+	if err != nil {
+		t.release(selected...)
+		return Tx{}, 0, fmt.Errorf("failed to consolidate treasury: %w", err)
+	}
+	return tx, len(selected), nil
+}
+
+// reserveDust marks up to max free dust utxos, smallest first, in-flight
+func (t *TreasuryPool) reserveDust(utxos Utxos, max int, now time.Time) Utxos {
+	t.prune("", utxos, now)
+
+	var selected Utxos
+	for _, utxo := range t.free(utxos, false) {
+		if len(selected) == max {
+			break
+		}
+		if value, _ := big.NewInt(0).SetString(utxo.Value, 10); value.Cmp(t.fanOutLovelace) >= 0 {
+			break
+		}
+		t.inflight[utxo.TxIn()] = reservation{at: now}
+		selected = append(selected, utxo)
+	}
+	return selected
+}
+
+// transferAll spends the ada only utxos, less the fee, to address in a single
+// output signed by the given signing key files
+func (c CLI) transferAll(ctx context.Context, utxos Utxos, address string, signingKeyFiles ...string) (Tx, error) {
+	total := big.NewInt(0)
+	for _, utxo := range utxos {
+		value, ok := big.NewInt(0).SetString(utxo.Value, 10)
+		if !ok {
+			return Tx{}, fmt.Errorf("unable to parse utxo value, %v", utxo.Value)
+		}
+		total.Add(total, value)
+	}
+
+	build := func(fee *big.Int) ([]byte, error) {
+		opts := []BuildOption{Fee(fee.String())}
+		for _, utxo := range utxos {
+			opts = append(opts, TxIn(utxo.Address, utxo.Index))
+		}
+		opts = append(opts, TxOut(address, big.NewInt(0).Sub(total, fee).String()))
+		return c.Build(opts...)
+	}
+
+	raw, err := build(big.NewInt(0))
+	if err != nil {
+		return Tx{}, err
+	}
+
+	filename := filepath.Join(c.Dir, "tmp", ksuid.New().String())
+	defer os.Remove(filename)
+	if err := ioutil.WriteFile(filename, raw, 0644); err != nil {
+		return Tx{}, fmt.Errorf("failed to write raw tx body: %w", err)
+	}
+
+	feeStr, err := c.MinFee(ctx, filename, int32(len(utxos)), 1, int32(len(signingKeyFiles)))
+	if err != nil {
+		return Tx{}, err
+	}
+	fee, ok := big.NewInt(0).SetString(feeStr, 10)
+	if !ok {
+		return Tx{}, fmt.Errorf("failed to parse fee, %v", feeStr)
+	}
+	if fee.Cmp(total) >= 0 {
+		return Tx{}, fmt.Errorf("utxos hold less lovelace, %v, than the fee, %v", total, fee)
+	}
+
+	raw, err = build(fee)
+	if err != nil {
+		return Tx{}, err
+	}
+
+	signed, err := c.signWithKeys(raw, signingKeyFiles...)
+	if err != nil {
+		return Tx{}, err
+	}
+
+	tx, err := ParseTx(signed)
+	if err != nil {
+		return Tx{}, fmt.Errorf("failed to parse transaction: %w", err)
+	}
+
+	if err := c.Submit(ctx, signed); err != nil {
+		return Tx{}, err
+	}
+	return tx, nil
+}
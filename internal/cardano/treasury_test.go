@@ -0,0 +1,143 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+func TestTreasuryPool_reserve(t *testing.T) {
+	var (
+		now      = time.Now()
+		required = big.NewInt(1_000_000_000)
+		pool     = NewTreasuryPool(FanOut(4), FanOutLovelace(10_000_000_000))
+		utxos    = Utxos{
+			{Address: "big", Index: 0, Value: "50000000000000"},
+			{Address: "fan", Index: 0, Value: "10000000000"},
+			{Address: "fan", Index: 1, Value: "10000000000"},
+			{Address: "fan", Index: 2, Value: "10000000000"},
+		}
+	)
+
+	t.Run("distinct inputs", func(t *testing.T) {
+		seen := map[string]struct{}{}
+		for i := 0; i < 3; i++ {
+			utxo, ok, _ := pool.reserve(utxos, required, now)
+			assert.True(t, ok)
+			assert.Equal(t, "fan", utxo.Address)
+			_, duplicate := seen[utxo.TxIn()]
+			assert.False(t, duplicate)
+			seen[utxo.TxIn()] = struct{}{}
+		}
+	})
+
+	t.Run("largest held for splitting", func(t *testing.T) {
+		_, ok, split := pool.reserve(utxos, required, now)
+		assert.False(t, ok)
+		assert.True(t, split)
+	})
+
+	t.Run("large requests use the largest utxo", func(t *testing.T) {
+		utxo, ok, _ := pool.reserve(utxos, big.NewInt(20_000_000_000), now)
+		assert.True(t, ok)
+		assert.Equal(t, "big#0", utxo.TxIn())
+	})
+
+	t.Run("spent inputs pruned", func(t *testing.T) {
+		pool.release(utxos[0])
+		remaining := append(Utxos{utxos[0]}, Utxo{Address: "change", Index: 0, Value: "9000000000"})
+		utxo, ok, _ := pool.reserve(remaining, required, now)
+		assert.True(t, ok)
+		assert.Equal(t, "change#0", utxo.TxIn())
+		assert.Len(t, pool.inflight, 1)
+	})
+
+	t.Run("expired reservations released", func(t *testing.T) {
+		pool := NewTreasuryPool(FanOut(4))
+		utxo, ok, _ := pool.reserve(utxos, required, now)
+		assert.True(t, ok)
+
+		again, ok, _ := pool.reserve(utxos, required, now.Add(treasuryInflightTTL+time.Second))
+		assert.True(t, ok)
+		assert.Equal(t, utxo.TxIn(), again.TxIn())
+		assert.Len(t, pool.inflight, 1)
+	})
+}
+
+func TestTreasuryPool_reserveValue(t *testing.T) {
+	var (
+		now     = time.Now()
+		assetID = "5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c.74657374"
+		token   = func(quantity string) []Token {
+			return []Token{{Asset: &Asset{PolicyId: "5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c", AssetName: "74657374"}, Quantity: quantity}}
+		}
+		utxos = Utxos{
+			{Address: "a", Index: 0, Value: "2000000", Tokens: token("60")},
+			{Address: "b", Index: 0, Value: "2000000", Tokens: token("60")},
+			{Address: "c", Index: 0, Value: "50000000"},
+		}
+		pool = NewTreasuryPool()
+	)
+
+	selected, err := pool.reserveValue("faucet", utxos, big.NewInt(10000000), map[string]*big.Int{assetID: big.NewInt(100)}, now)
+	assert.Nil(t, err)
+	assert.Len(t, selected, 3)
+
+	// everything is now in-flight
+	_, err = pool.reserveValue("faucet", utxos, big.NewInt(0), map[string]*big.Int{assetID: big.NewInt(1)}, now)
+	assert.NotNil(t, err)
+
+	// treasury reservations do not prune inputs reserved from another source
+	pool.prune("", Utxos{}, now)
+	assert.Len(t, pool.inflight, 3)
+
+	pool.release(selected...)
+	selected, err = pool.reserveValue("faucet", utxos, big.NewInt(0), map[string]*big.Int{assetID: big.NewInt(10)}, now)
+	assert.Nil(t, err)
+	assert.Len(t, selected, 1)
+}
+
+func TestTreasuryPool_reserveDust(t *testing.T) {
+	var (
+		now   = time.Now()
+		pool  = NewTreasuryPool(FanOut(4), FanOutLovelace(10_000_000_000))
+		utxos = Utxos{
+			{Address: "big", Index: 0, Value: "50000000000000"},
+			{Address: "dust", Index: 0, Value: "3000000"},
+			{Address: "dust", Index: 1, Value: "1000000"},
+			{Address: "dust", Index: 2, Value: "2000000"},
+			{Address: "token", Index: 0, Value: "1500000", Tokens: []Token{{Asset: &Asset{PolicyId: "abc"}, Quantity: "1"}}},
+		}
+	)
+
+	selected := pool.reserveDust(utxos, 2, now)
+	assert.Equal(t, Utxos{utxos[2], utxos[3]}, selected)
+
+	// reserved dust is not handed out twice
+	selected = pool.reserveDust(utxos, 2, now)
+	assert.Equal(t, Utxos{utxos[1]}, selected)
+}
@@ -0,0 +1,289 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// tx body map keys
+const (
+	txBodyInputs  = 0
+	txBodyOutputs = 1
+	txBodyFee     = 2
+	txBodyMint    = 9
+)
+
+// TxOutput holds an output created by a tx
+type TxOutput struct {
+	Address string
+	Value   string  // Value holds the lovelace paid to the address
+	Tokens  []Token // Tokens holds the native tokens paid to the address
+}
+
+// txBody holds the parts of a tx body the toolkit reports on
+type txBody struct {
+	Inputs  []string // Inputs as hash#index
+	Outputs []TxOutput
+	Fee     string
+	Mint    []Token // Mint holds the tokens minted, negative when burned
+}
+
+// decodeTxBody decodes the inputs, outputs, fee, and mint of a tx body
+func decodeTxBody(data []byte) (txBody, error) {
+	var body map[uint64]cbor.RawMessage
+	if err := cbor.Unmarshal(data, &body); err != nil {
+		return txBody{}, fmt.Errorf("unable to decode tx body: %w", err)
+	}
+
+	var (
+		inputs  []string
+		outputs []TxOutput
+		fee     uint64
+		mint    []Token
+	)
+
+	if raw, ok := body[txBodyInputs]; ok {
+		var items []struct {
+			_     struct{} `cbor:",toarray"`
+			Hash  []byte
+			Index uint64
+		}
+		if err := cbor.Unmarshal(raw, &items); err != nil {
+			return txBody{}, fmt.Errorf("unable to decode tx inputs: %w", err)
+		}
+		for _, item := range items {
+			inputs = append(inputs, fmt.Sprintf("%x#%v", item.Hash, item.Index))
+		}
+	}
+
+	if raw, ok := body[txBodyOutputs]; ok {
+		var items []cbor.RawMessage
+		if err := cbor.Unmarshal(raw, &items); err != nil {
+			return txBody{}, fmt.Errorf("unable to decode tx outputs: %w", err)
+		}
+		for _, item := range items {
+			output, err := decodeTxOutput(item)
+			if err != nil {
+				return txBody{}, err
+			}
+			outputs = append(outputs, output)
+		}
+	}
+
+	if raw, ok := body[txBodyFee]; ok {
+		if err := cbor.Unmarshal(raw, &fee); err != nil {
+			return txBody{}, fmt.Errorf("unable to decode tx fee: %w", err)
+		}
+	}
+
+	if raw, ok := body[txBodyMint]; ok {
+		err := decodeBytesMap(raw, func(policyID []byte, names cbor.RawMessage) error {
+			return decodeBytesMap(names, func(name []byte, raw cbor.RawMessage) error {
+				var quantity int64
+				if err := cbor.Unmarshal(raw, &quantity); err != nil {
+					return err
+				}
+				mint = append(mint, Token{
+					Asset: &Asset{
+						PolicyId:  hex.EncodeToString(policyID),
+						AssetName: hex.EncodeToString(name),
+					},
+					Quantity: strconv.FormatInt(quantity, 10),
+				})
+				return nil
+			})
+		})
+		if err != nil {
+			return txBody{}, fmt.Errorf("unable to decode tx mint: %w", err)
+		}
+	}
+
+	return txBody{
+		Inputs:  inputs,
+		Outputs: outputs,
+		Fee:     strconv.FormatUint(fee, 10),
+		Mint:    mint,
+	}, nil
+}
+
+// decodeTxOutput decodes both the legacy array and the post-alonzo map output formats
+func decodeTxOutput(data []byte) (TxOutput, error) {
+	var (
+		address []byte
+		value   cbor.RawMessage
+	)
+	if len(data) > 0 && data[0]>>5 == 5 { // major type 5, map
+		var output map[uint64]cbor.RawMessage
+		if err := cbor.Unmarshal(data, &output); err != nil {
+			return TxOutput{}, fmt.Errorf("unable to decode tx output: %w", err)
+		}
+		if err := cbor.Unmarshal(output[0], &address); err != nil {
+			return TxOutput{}, fmt.Errorf("unable to decode tx output address: %w", err)
+		}
+		value = output[1]
+	} else {
+		var output []cbor.RawMessage
+		if err := cbor.Unmarshal(data, &output); err != nil {
+			return TxOutput{}, fmt.Errorf("unable to decode tx output: %w", err)
+		}
+		if len(output) < 2 {
+			return TxOutput{}, fmt.Errorf("unable to decode tx output: expected address and value")
+		}
+		if err := cbor.Unmarshal(output[0], &address); err != nil {
+			return TxOutput{}, fmt.Errorf("unable to decode tx output address: %w", err)
+		}
+		value = output[1]
+	}
+
+	s, err := encodeAddress(address)
+	if err != nil {
+		return TxOutput{}, err
+	}
+	lovelace, tokens, err := decodeValue(value)
+	if err != nil {
+		return TxOutput{}, err
+	}
+	return TxOutput{
+		Address: s,
+		Value:   lovelace,
+		Tokens:  tokens,
+	}, nil
+}
+
+// decodeValue decodes either a plain lovelace value or lovelace along with a
+// multi-asset map of policy id to asset name to quantity
+func decodeValue(data []byte) (string, []Token, error) {
+	var lovelace uint64
+	if len(data) > 0 && data[0]>>5 == 0 { // major type 0, unsigned int
+		if err := cbor.Unmarshal(data, &lovelace); err != nil {
+			return "", nil, fmt.Errorf("unable to decode tx output value: %w", err)
+		}
+		return strconv.FormatUint(lovelace, 10), nil, nil
+	}
+
+	var value []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &value); err != nil {
+		return "", nil, fmt.Errorf("unable to decode tx output value: %w", err)
+	}
+	if len(value) != 2 {
+		return "", nil, fmt.Errorf("unable to decode tx output value: expected lovelace and assets")
+	}
+	if err := cbor.Unmarshal(value[0], &lovelace); err != nil {
+		return "", nil, fmt.Errorf("unable to decode tx output value: %w", err)
+	}
+
+	var tokens []Token
+	err := decodeBytesMap(value[1], func(policyID []byte, names cbor.RawMessage) error {
+		return decodeBytesMap(names, func(name []byte, raw cbor.RawMessage) error {
+			var quantity uint64
+			if err := cbor.Unmarshal(raw, &quantity); err != nil {
+				return err
+			}
+			tokens = append(tokens, Token{
+				Asset: &Asset{
+					PolicyId:  hex.EncodeToString(policyID),
+					AssetName: hex.EncodeToString(name),
+				},
+				Quantity: strconv.FormatUint(quantity, 10),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to decode tx output assets: %w", err)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Asset.ID() < tokens[j].Asset.ID()
+	})
+	return strconv.FormatUint(lovelace, 10), tokens, nil
+}
+
+// decodeBytesMap calls fn for each entry of a definite length cbor map keyed by
+// byte strings, which can not be decoded into a go map
+func decodeBytesMap(data []byte, fn func(key []byte, value cbor.RawMessage) error) error {
+	if len(data) == 0 || data[0]>>5 != 5 {
+		return fmt.Errorf("expected map")
+	}
+
+	var n uint64
+	switch info := data[0] & 0x1f; {
+	case info < 24:
+		n, data = uint64(info), data[1:]
+	case info >= 24 && info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < 1+size {
+			return fmt.Errorf("unexpected end of map")
+		}
+		for _, b := range data[1 : 1+size] {
+			n = n<<8 | uint64(b)
+		}
+		data = data[1+size:]
+	default:
+		return fmt.Errorf("indefinite length maps not supported")
+	}
+
+	decoder := cbor.NewDecoder(bytes.NewReader(data))
+	for i := uint64(0); i < n; i++ {
+		var key []byte
+		var value cbor.RawMessage
+		if err := decoder.Decode(&key); err != nil {
+			return err
+		}
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeAddress returns the bech32 encoding of a shelley address.  Byron
+// addresses, which are base58 encoded, are returned as hex.
+func encodeAddress(address []byte) (string, error) {
+	if len(address) == 0 {
+		return "", fmt.Errorf("unable to encode address: empty address")
+	}
+
+	var hrp string
+	switch header := address[0]; {
+	case header>>4 <= 7:
+		hrp = "addr"
+	case header>>4 == 14 || header>>4 == 15:
+		hrp = "stake"
+	default:
+		return hex.EncodeToString(address), nil
+	}
+	if address[0]&0x0f == 0 { // network id 0 identifies test networks
+		hrp += "_test"
+	}
+	return Bech32Encode(hrp, address)
+}
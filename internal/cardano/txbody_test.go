@@ -0,0 +1,95 @@
+package cardano
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/tj/assert"
+)
+
+func TestDecodeTxBody(t *testing.T) {
+	hash := bytes.Repeat([]byte{0xab}, 32)
+	address := append([]byte{0x60}, bytes.Repeat([]byte{0x01}, 28)...) // enterprise address, network 0
+	want, err := Bech32Encode("addr_test", address)
+	assert.Nil(t, err)
+
+	policyID := bytes.Repeat([]byte{0xcd}, 28)
+	multiAsset := []byte{0xa1} // map(1) keyed by byte strings, which go maps can not hold
+	for _, v := range []interface{}{policyID} {
+		data, err := cbor.Marshal(v)
+		assert.Nil(t, err)
+		multiAsset = append(multiAsset, data...)
+	}
+	multiAsset = append(multiAsset, 0xa1)
+	for _, v := range []interface{}{[]byte("abc"), uint64(10)} {
+		data, err := cbor.Marshal(v)
+		assert.Nil(t, err)
+		multiAsset = append(multiAsset, data...)
+	}
+	value := []interface{}{uint64(2e6), cbor.RawMessage(multiAsset)}
+
+	mint := []byte{0xa1}
+	for _, v := range []interface{}{policyID} {
+		data, err := cbor.Marshal(v)
+		assert.Nil(t, err)
+		mint = append(mint, data...)
+	}
+	mint = append(mint, 0xa1)
+	for _, v := range []interface{}{[]byte("abc"), int64(-5)} {
+		data, err := cbor.Marshal(v)
+		assert.Nil(t, err)
+		mint = append(mint, data...)
+	}
+
+	input := []interface{}{hash, uint64(1)}
+	testCases := map[string]struct {
+		Inputs interface{}
+		Output interface{}
+	}{
+		"legacy": {
+			Inputs: []interface{}{input},
+			Output: []interface{}{address, value},
+		},
+		"babbage": {
+			Inputs: cbor.Tag{Number: 258, Content: []interface{}{input}},
+			Output: map[uint64]interface{}{0: address, 1: value},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			body, err := cbor.Marshal(map[uint64]interface{}{
+				0: tc.Inputs,
+				1: []interface{}{tc.Output},
+				2: uint64(170000),
+				9: cbor.RawMessage(mint),
+			})
+			assert.Nil(t, err)
+
+			got, err := decodeTxBody(body)
+			assert.Nil(t, err)
+			assert.Equal(t, "170000", got.Fee)
+			assert.Equal(t, []string{"abababababababababababababababababababababababababababababababab#1"}, got.Inputs)
+			assert.Equal(t, []TxOutput{
+				{
+					Address: want,
+					Value:   "2000000",
+					Tokens: []Token{
+						{
+							Asset:    &Asset{PolicyId: hex.EncodeToString(policyID), AssetName: "616263"},
+							Quantity: "10",
+						},
+					},
+				},
+			}, got.Outputs)
+			assert.Equal(t, []Token{
+				{
+					Asset:    &Asset{PolicyId: hex.EncodeToString(policyID), AssetName: "616263"},
+					Quantity: "-5",
+				},
+			}, got.Mint)
+		})
+	}
+}
@@ -24,17 +24,26 @@ package cardano
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 var (
 	reUtxo   = regexp.MustCompile(`(?m)^([a-z0-9]+)\s+(\d+)\s+(\d+)\s+lovelace(.*)$`)
-	reTokens = regexp.MustCompile(`\+\s*(\d+)\s+([a-z0-9]+).(\S+)`)
-	reScript = regexp.MustCompile(`ScriptDataInAlonzoEra\s+"([^"]+)"`)
+	reTokens = regexp.MustCompile(`\+\s*(\d+)\s+([a-f0-9]{56})(?:\.(\S*))?`)
 )
 
+// ParseUtxos parses the output of cardano-cli query utxo rendered with utf-8 asset names
 func ParseUtxos(buf *bytes.Buffer) []Utxo {
+	return parseUtxos(buf, AssetNameUTF8)
+}
+
+// parseUtxos parses the output of cardano-cli query utxo, normalizing asset names to hex
+func parseUtxos(buf *bytes.Buffer, format AssetNameFormat) []Utxo {
 	var (
 		matches = reUtxo.FindAllStringSubmatch(buf.String(), -1)
 		utxos   []Utxo
@@ -54,7 +63,7 @@ func ParseUtxos(buf *bytes.Buffer) []Utxo {
 
 		if len(match) >= 5 {
 			if extra := match[4]; extra != "" {
-				if ss := reScript.FindStringSubmatch(extra); len(ss) == 2 {
+				if ss := reDatum.FindStringSubmatch(extra); len(ss) == 2 {
 					utxo.DatumHash = ss[1]
 				}
 
@@ -65,7 +74,7 @@ func ParseUtxos(buf *bytes.Buffer) []Utxo {
 					}
 					utxo.Tokens = append(utxo.Tokens, Token{
 						Asset: &Asset{
-							AssetName: token[3],
+							AssetName: parseCLIAssetName(token[3], format),
 							PolicyId:  token[2],
 						},
 						Quantity: token[1],
@@ -78,3 +87,68 @@ func ParseUtxos(buf *bytes.Buffer) []Utxo {
 	}
 	return utxos
 }
+
+// parseUtxoSet parses the json utxo set written by cardano-cli query utxo --out-file,
+// which is keyed by hash#index and holds the address and value of each utxo
+func parseUtxoSet(data []byte, format AssetNameFormat) (Utxos, error) {
+	var items map[string]struct {
+		Address   string                     `json:"address"`
+		DatumHash string                     `json:"datumhash"`
+		Value     map[string]json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("unable to parse utxo set: %w", err)
+	}
+
+	var utxos Utxos
+	for key, item := range items {
+		parts := strings.SplitN(key, "#", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unable to parse utxo set: invalid tx in, %v", key)
+		}
+		index, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse utxo set: invalid tx in, %v", key)
+		}
+
+		utxo := Utxo{
+			Address:   parts[0],
+			DatumHash: item.DatumHash,
+			Index:     int32(index),
+			Owner:     item.Address,
+		}
+		for policyID, raw := range item.Value {
+			if policyID == "lovelace" {
+				var lovelace json.Number
+				if err := json.Unmarshal(raw, &lovelace); err != nil {
+					return nil, fmt.Errorf("unable to parse utxo set: invalid lovelace, %v", key)
+				}
+				utxo.Value = lovelace.String()
+				continue
+			}
+
+			var assets map[string]json.Number
+			if err := json.Unmarshal(raw, &assets); err != nil {
+				return nil, fmt.Errorf("unable to parse utxo set: invalid assets, %v", key)
+			}
+			for assetName, quantity := range assets {
+				utxo.Tokens = append(utxo.Tokens, Token{
+					Asset: &Asset{
+						AssetName: parseCLIAssetName(assetName, format),
+						PolicyId:  policyID,
+					},
+					Quantity: quantity.String(),
+				})
+			}
+		}
+		sort.Slice(utxo.Tokens, func(i, j int) bool {
+			return utxo.Tokens[i].Asset.ID() < utxo.Tokens[j].Asset.ID()
+		})
+		utxos = append(utxos, utxo)
+	}
+
+	sort.Slice(utxos, func(i, j int) bool {
+		return utxos[i].TxIn() < utxos[j].TxIn()
+	})
+	return utxos, nil
+}
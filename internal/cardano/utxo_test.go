@@ -130,6 +130,44 @@ fe8337adb04a5d50fc204c407d5b982359b75abd777ecf1f5eafe23c88677a9d     1        25
 	_ = encoder.Encode(utxos)
 }
 
+func Test_parseUtxoSet(t *testing.T) {
+	data := []byte(`{
+  "f20dc6ca5a00bec962b46572981e060da08ae695b790dea4688c24e71dea475d#1": {
+    "address": "addr_test1alice",
+    "datumhash": null,
+    "value": {
+      "lovelace": 200000000,
+      "35c0b7b5066d1738c97ca04e2a5ce30b02cbb8e6edd0363e3d94cf32": {"pi4Ed": 10000000}
+    }
+  },
+  "df86eaf093f1c578261667379d404aa561039f19eb4f63d19aa6896e3d1658fa#0": {
+    "address": "addr_test1bob",
+    "value": {"lovelace": 1000000000}
+  }
+}`)
+	utxos, err := parseUtxoSet(data, AssetNameUTF8)
+	assert.Nil(t, err)
+	assert.Equal(t, Utxos{
+		{
+			Address: "df86eaf093f1c578261667379d404aa561039f19eb4f63d19aa6896e3d1658fa",
+			Owner:   "addr_test1bob",
+			Value:   "1000000000",
+		},
+		{
+			Address: "f20dc6ca5a00bec962b46572981e060da08ae695b790dea4688c24e71dea475d",
+			Index:   1,
+			Owner:   "addr_test1alice",
+			Tokens: []Token{
+				{
+					Asset:    &Asset{PolicyId: "35c0b7b5066d1738c97ca04e2a5ce30b02cbb8e6edd0363e3d94cf32", AssetName: "7069344564"},
+					Quantity: "10000000",
+				},
+			},
+			Value: "200000000",
+		},
+	}, utxos)
+}
+
 func TestDoubleEnv(t *testing.T) {
 	cmd := exec.Command("env")
 	cmd.Env = append(os.Environ(), "COMMAND_MODE=apple", "COMMAND_MODE=blah")
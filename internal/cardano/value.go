@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Value holds a quantity of lovelace along with native tokens keyed by asset id
+type Value struct {
+	Lovelace *big.Int
+	Tokens   map[string]*big.Int
+}
+
+func NewValue() Value {
+	return Value{
+		Lovelace: big.NewInt(0),
+		Tokens:   map[string]*big.Int{},
+	}
+}
+
+// Add adds the lovelace and tokens held by the utxo to the value
+func (v Value) Add(utxo Utxo) error {
+	lovelace, ok := big.NewInt(0).SetString(utxo.Value, 10)
+	if !ok {
+		return fmt.Errorf("failed to parse utxo value, %v", utxo.Value)
+	}
+	v.Lovelace.Add(v.Lovelace, lovelace)
+
+	for _, token := range utxo.Tokens {
+		quantity, ok := big.NewInt(0).SetString(token.Quantity, 10)
+		if !ok {
+			return fmt.Errorf("failed to parse token quantity, %v", token.Quantity)
+		}
+		v.AddToken(token.Asset.ID(), quantity)
+	}
+	return nil
+}
+
+// AddToken adds quantity, which may be negative, of the asset to the value
+func (v Value) AddToken(assetID string, quantity *big.Int) {
+	total, ok := v.Tokens[assetID]
+	if !ok {
+		total = big.NewInt(0)
+		v.Tokens[assetID] = total
+	}
+	total.Add(total, quantity)
+}
+
+// Token returns the quantity of the asset held by the value
+func (v Value) Token(assetID string) *big.Int {
+	if total, ok := v.Tokens[assetID]; ok {
+		return big.NewInt(0).Set(total)
+	}
+	return big.NewInt(0)
+}
+
+// TokenArgs returns the non-zero tokens held by the value in the form expected
+// by TxOut e.g. "100 policyId.assetName", sorted by asset id
+func (v Value) TokenArgs() []string {
+	var assetIDs []string
+	for assetID, quantity := range v.Tokens {
+		if quantity.Sign() == 0 {
+			continue
+		}
+		assetIDs = append(assetIDs, assetID)
+	}
+	sort.Strings(assetIDs)
+
+	var tokens []string
+	for _, assetID := range assetIDs {
+		tokens = append(tokens, v.Tokens[assetID].String()+" "+assetID)
+	}
+	return tokens
+}
+
+// Value returns the sum of the lovelace and tokens held by the utxos
+func (uu Utxos) Value() (Value, error) {
+	value := NewValue()
+	for _, utxo := range uu {
+		if err := value.Add(utxo); err != nil {
+			return Value{}, err
+		}
+	}
+	return value, nil
+}
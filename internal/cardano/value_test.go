@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cardano
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestUtxos_Value(t *testing.T) {
+	utxos := Utxos{
+		{
+			Value: "1000",
+			Tokens: []Token{
+				{Asset: &Asset{PolicyId: "b", AssetName: "two"}, Quantity: "20"},
+				{Asset: &Asset{PolicyId: "a", AssetName: "one"}, Quantity: "10"},
+			},
+		},
+		{
+			Value: "2000",
+			Tokens: []Token{
+				{Asset: &Asset{PolicyId: "a", AssetName: "one"}, Quantity: "5"},
+			},
+		},
+	}
+
+	value, err := utxos.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "3000", value.Lovelace.String())
+	assert.Equal(t, "15", value.Token("a.one").String())
+	assert.Equal(t, []string{"15 a.one", "20 b.two"}, value.TokenArgs())
+
+	value.AddToken("b.two", big.NewInt(-20))
+	assert.Equal(t, []string{"15 a.one"}, value.TokenArgs())
+
+	_, err = Utxos{{Value: "junk"}}.Value()
+	assert.NotNil(t, err)
+}
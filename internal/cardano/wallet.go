@@ -100,13 +100,13 @@ func (c CLI) CreateWallet(ctx context.Context, initialFunds, name string) (walle
 	// --stake-verification-key-file addresses/${ADDR}-stake.vkey \
 	// --testnet-magic 42 \
 	// --out-file addresses/${ADDR}.addr
-	_, err = c.exec(
+	args := []string{
 		"address", "build",
 		"--payment-verification-key-file", fmt.Sprintf("%v/%v.vkey", dirWallets, name),
 		"--stake-verification-key-file", fmt.Sprintf("%v/%v-stake.vkey", dirWallets, name),
-		"--testnet-magic", c.TestnetMagic,
 		"--out-file", fmt.Sprintf("%v/%v.addr", dirWallets, name),
-	)
+	}
+	_, err = c.exec(append(args, c.Network.Args()...)...)
 	if err != nil {
 		return "", fmt.Errorf("failed to create wallet: failed to create payment address: %w", err)
 	}
@@ -116,12 +116,12 @@ func (c CLI) CreateWallet(ctx context.Context, initialFunds, name string) (walle
 	// --stake-verification-key-file addresses/${ADDR}-stake.vkey \
 	// --testnet-magic 42 \
 	// --out-file addresses/${ADDR}-stake.addr
-	_, err = c.exec(
+	args = []string{
 		"stake-address", "build",
 		"--stake-verification-key-file", fmt.Sprintf("%v/%v-stake.vkey", dirWallets, name),
-		"--testnet-magic", c.TestnetMagic,
 		"--out-file", fmt.Sprintf("%v/%v-stake.addr", dirWallets, name),
-	)
+	}
+	_, err = c.exec(append(args, c.Network.Args()...)...)
 	if err != nil {
 		return "", fmt.Errorf("failed to create wallet: failed to create stake address: %w", err)
 	}
@@ -163,7 +163,9 @@ func (c CLI) CreateWallet(ctx context.Context, initialFunds, name string) (walle
 
 var reQuantity = regexp.MustCompile(`^\d+$`)
 
-func (c CLI) FundWallet(ctx context.Context, address, quantity string) (tx Tx, err error) {
+// FundWallet transfers quantity lovelace from the treasury to the address.  Any
+// additional build options e.g. MetadataJSON are applied to the funding tx
+func (c CLI) FundWallet(ctx context.Context, address, quantity string, opts ...BuildOption) (tx Tx, err error) {
 	defer func(begin time.Time) {
 		zapctx.FromContext(ctx).Info("funded wallet",
 			zap.String("address", address),
@@ -173,41 +175,82 @@ func (c CLI) FundWallet(ctx context.Context, address, quantity string) (tx Tx, e
 		)
 	}(time.Now())
 
-	address, err = c.NormalizeAddress(address)
-	if err != nil {
-		return Tx{}, err
-	}
-
 	if quantity == "" || quantity == "0" {
 		return Tx{}, nil
 	}
 
-	if address == c.TreasuryAddr {
-		return Tx{}, fmt.Errorf("unable to fund wallet: illegall attempt to fund treasury addr")
-	}
-	if !reQuantity.MatchString(quantity) {
-		return Tx{}, fmt.Errorf("unable to fund wallet: invalid quantity, %v", quantity)
-	}
-	if len(quantity) > 13 {
-		return Tx{}, fmt.Errorf("unable to fund wallet: quantity requested exceeds maximum 9,999,999,999")
+	address, err = c.ValidatePayout(address, quantity)
+	if err != nil {
+		return Tx{}, fmt.Errorf("unable to fund wallet: %w", err)
 	}
 
-	utxos, err := c.Utxos(c.TreasuryAddr)
+	utxo, release, err := c.reserveTreasuryUtxo(ctx, quantity)
 	if err != nil {
 		return Tx{}, fmt.Errorf("unable to fund wallet: %w", err)
 	}
-	if len(utxos) == 0 {
-		return Tx{}, fmt.Errorf("unable to fund wallet: treasury addr is empty, %v", c.TreasuryAddr)
+
+	tx, err = c.transferFunds(ctx, utxo, []Payout{{Address: address, Quantity: quantity}}, opts...)
+	release(err == nil)
+	return tx, err
+}
+
+// FundWallets pays out to many addresses from the treasury in a single tx.
+// Payout i is found at output i+1 of the returned tx.
+func (c CLI) FundWallets(ctx context.Context, payouts []Payout, opts ...BuildOption) (tx Tx, err error) {
+	defer func(begin time.Time) {
+		zapctx.FromContext(ctx).Info("funded wallets",
+			zap.Int("payouts", len(payouts)),
+			zap.String("tx", tx.ID),
+			zap.Duration("elapsed", time.Since(begin).Round(time.Millisecond)),
+			zap.Error(err),
+		)
+	}(time.Now())
+
+	if len(payouts) == 0 {
+		return Tx{}, fmt.Errorf("unable to fund wallets: no payouts")
 	}
 
-	for _, utxo := range utxos {
-		if len(utxo.Value) <= 10 {
-			continue
+	var (
+		normalized = make([]Payout, 0, len(payouts))
+		total      = big.NewInt(0)
+	)
+	for _, payout := range payouts {
+		address, err := c.ValidatePayout(payout.Address, payout.Quantity)
+		if err != nil {
+			return Tx{}, fmt.Errorf("unable to fund wallets: %w", err)
 		}
-		return c.transferFunds(ctx, utxo, address, quantity)
+		quantity, _ := big.NewInt(0).SetString(payout.Quantity, 10)
+		total.Add(total, quantity)
+		normalized = append(normalized, Payout{Address: address, Quantity: payout.Quantity})
+	}
+
+	utxo, release, err := c.reserveTreasuryUtxo(ctx, total.String())
+	if err != nil {
+		return Tx{}, fmt.Errorf("unable to fund wallets: %w", err)
 	}
 
-	return Tx{}, fmt.Errorf("unable to fund wallet: insufficient funds in treasury addr, %v", c.TreasuryAddr)
+	tx, err = c.transferFunds(ctx, utxo, normalized, opts...)
+	release(err == nil)
+	return tx, err
+}
+
+// ValidatePayout ensures quantity lovelace may be paid from the treasury to
+// the address and returns the normalized address
+func (c CLI) ValidatePayout(address, quantity string) (string, error) {
+	address, err := c.NormalizeAddress(address)
+	if err != nil {
+		return "", err
+	}
+	if address == c.TreasuryAddr {
+		return "", fmt.Errorf("illegall attempt to fund treasury addr")
+	}
+	if !reQuantity.MatchString(quantity) || quantity == "0" {
+		return "", fmt.Errorf("invalid quantity, %v", quantity)
+	}
+	if len(quantity) > 13 {
+		return "", fmt.Errorf("quantity requested exceeds maximum 9,999,999,999")
+	}
+	return address, nil
 }
 
 func (c CLI) RegisterStake(ctx context.Context, address string) (tx Tx, err error) {
@@ -227,35 +270,16 @@ func (c CLI) RegisterStake(ctx context.Context, address string) (tx Tx, err erro
 
 	// Fund the wallet with enough ADA to cover fees and registration deposit
 	amt := big.NewInt(5 * 1e6)
-	utxos, err := c.Utxos(
-		address,
-		AtLeast(int32(amt.Int64())), // bounds checking?
-		ExcludeScripts(true),
-		ExcludeTokens(true),
-	)
+	utxo, err := c.fundedUtxo(ctx, address, amt)
 	if err != nil {
 		return Tx{}, err
 	}
-	utxo := Utxo{}
-	if len(utxos) < 1 {
-		tx, err = c.FundWallet(ctx, address, amt.String())
-		if err != nil {
-			return Tx{}, fmt.Errorf("failed to fund wallet: %w", err)
-		}
-		utxo = Utxo{
-			Address: tx.ID,
-			Index:   1,
-			Value:   "2000000",
-		}
-	} else {
-		utxo = utxos[0]
-		amt, _ = big.NewInt(0).SetString(utxo.Value, 10)
-	}
+	amt, _ = big.NewInt(0).SetString(utxo.Value, 10)
 
 	// Estimate the fee for the tx to register the stake fee
 	cert := location + "-stake.reg.cert"
 	raw, err := c.Build(
-		TxIn(tx.ID, 1),
+		TxIn(utxo.Address, utxo.Index),
 		TxOut(address, amt.String()),
 		Certificate(cert),
 	)
@@ -283,7 +307,7 @@ func (c CLI) RegisterStake(ctx context.Context, address string) (tx Tx, err erro
 	amt = big.NewInt(0).Sub(amt, feeValue)
 	// Build, Sign, and Submit
 	raw, err = c.Build(
-		TxIn(tx.ID, 1),
+		TxIn(utxo.Address, utxo.Index),
 		TxOut(address, amt.String()),
 		Fee(fee),
 		Certificate(cert),
@@ -304,7 +328,7 @@ func (c CLI) RegisterStake(ctx context.Context, address string) (tx Tx, err erro
 	if err != nil {
 		return Tx{}, err
 	}
-	err = c.Submit(ctx, signed)
+	err = c.Submit(WithTxKind(ctx, TxKindRegister), signed)
 	if err != nil {
 		return Tx{}, err
 	}
@@ -328,30 +352,11 @@ func (c CLI) Delegate(ctx context.Context, address string) (tx Tx, err error) {
 
 	// Find a utxo containing at least 2 ada
 	amt := big.NewInt(2 * 1e6)
-	utxos, err := c.Utxos(
-		address,
-		AtLeast(int32(amt.Int64())), // bounds checking?
-		ExcludeScripts(true),
-		ExcludeTokens(true),
-	)
+	utxo, err := c.fundedUtxo(ctx, address, amt)
 	if err != nil {
 		return Tx{}, err
 	}
-	utxo := Utxo{}
-	if len(utxos) < 1 {
-		tx, err = c.FundWallet(ctx, address, amt.String())
-		if err != nil {
-			return Tx{}, fmt.Errorf("failed to fund wallet: %w", err)
-		}
-		utxo = Utxo{
-			Address: tx.ID,
-			Index:   1,
-			Value:   "2000000",
-		}
-	} else {
-		utxo = utxos[0]
-		amt, _ = big.NewInt(0).SetString(utxo.Value, 10)
-	}
+	amt, _ = big.NewInt(0).SetString(utxo.Value, 10)
 
 	// Estimate the fee for the tx to register the stake fee
 	cert := location + "-stake.delegate.cert"
@@ -405,9 +410,41 @@ func (c CLI) Delegate(ctx context.Context, address string) (tx Tx, err error) {
 	if err != nil {
 		return Tx{}, err
 	}
-	err = c.Submit(ctx, signed)
+	err = c.Submit(WithTxKind(ctx, TxKindDelegate), signed)
 	if err != nil {
 		return Tx{}, err
 	}
 	return tx, nil
 }
+
+// fundedUtxo returns an ada only utxo at address holding at least amt lovelace.
+// If none exists, the address is funded from the treasury and the funding
+// output returned once confirmed.
+func (c CLI) fundedUtxo(ctx context.Context, address string, amt *big.Int) (Utxo, error) {
+	utxos, err := c.Utxos(
+		address,
+		AtLeast(int32(amt.Int64())), // bounds checking?
+		ExcludeScripts(true),
+		ExcludeTokens(true),
+	)
+	if err != nil {
+		return Utxo{}, err
+	}
+	if len(utxos) > 0 {
+		return utxos[0], nil
+	}
+
+	tx, err := c.FundWallet(ctx, address, amt.String())
+	if err != nil {
+		return Utxo{}, fmt.Errorf("failed to fund wallet: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultAwaitTimeout)
+	defer cancel()
+
+	outputs, err := c.AwaitTx(ctx, tx.ID, address)
+	if err != nil {
+		return Utxo{}, fmt.Errorf("failed to fund wallet: %w", err)
+	}
+	return outputs[0], nil
+}
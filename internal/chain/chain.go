@@ -0,0 +1,172 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package chain describes historical chain data, blocks, txs, asset holders,
+// and stake history, that cardano-cli can not answer along with the Provider
+// interface implemented by sources of that data e.g. db-sync.
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+)
+
+var (
+	// ErrNotFound is returned when the requested block or tx does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnsupported is returned by providers unable to answer a query
+	ErrUnsupported = errors.New("not supported")
+)
+
+// stake event and certificate kinds
+const (
+	StakeRegistration   = "registration"
+	StakeDelegation     = "delegation"
+	StakeDeregistration = "deregistration"
+	PoolRegistration    = "pool_registration"
+	PoolRetirement      = "pool_retirement"
+)
+
+// Block describes a block
+type Block struct {
+	Hash    string
+	Number  int32
+	Slot    int32
+	Epoch   int32
+	Time    time.Time
+	Size    int32
+	TxCount int32
+}
+
+// Tx describes a tx included in a block.  Inputs, Outputs, Metadata, and
+// Certificates are only populated by Provider.Transaction.
+type Tx struct {
+	ID        string
+	BlockHash string
+	Block     int32 // Block number the tx was included in
+	Slot      int32
+	Epoch     int32
+	Time      time.Time
+	Fee       string
+	Deposit   string
+	Size      int32
+
+	Inputs       []Input
+	Outputs      []cardano.TxOutput // Outputs in index order
+	Metadata     json.RawMessage    // Metadata holds json metadata keyed by label, if any
+	Certificates []Certificate
+}
+
+// Input describes a utxo consumed by a tx
+type Input struct {
+	TxID    string
+	Index   int32
+	Address string // Address of the consumed output, when known
+	Value   string // Value of the consumed output, when known
+}
+
+// Certificate describes a certificate included in a tx
+type Certificate struct {
+	Kind         string
+	StakeAddress string // StakeAddress of stake certificates
+	Pool         string // Pool holds the bech32 pool id of delegation and pool certificates
+	Epoch        int32  // Epoch the certificate takes effect
+}
+
+// Holder holds the quantity of an asset held by an address
+type Holder struct {
+	Address  string
+	Quantity string
+}
+
+// HoldersOf sums the quantity of the asset, policyId.assetNameHex, held by the
+// Owner of each utxo, largest holders first
+func HoldersOf(utxos cardano.Utxos, assetID string) []Holder {
+	totals := map[string]*big.Int{}
+	for _, utxo := range utxos {
+		for _, token := range utxo.Tokens {
+			if token.Asset == nil || token.Asset.ID() != assetID {
+				continue
+			}
+			quantity, ok := big.NewInt(0).SetString(token.Quantity, 10)
+			if !ok {
+				continue
+			}
+			if total, ok := totals[utxo.Owner]; ok {
+				total.Add(total, quantity)
+			} else {
+				totals[utxo.Owner] = quantity
+			}
+		}
+	}
+
+	var holders []Holder
+	for address, total := range totals {
+		holders = append(holders, Holder{Address: address, Quantity: total.String()})
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if cmp := totals[holders[i].Address].Cmp(totals[holders[j].Address]); cmp != 0 {
+			return cmp > 0
+		}
+		return holders[i].Address < holders[j].Address
+	})
+	return holders
+}
+
+// Mint records a tx that minted, or when Quantity is negative burned, an asset
+type Mint struct {
+	TxID     string
+	Slot     int32
+	Time     time.Time
+	Quantity string
+}
+
+// StakeEvent describes a change to a stake address; registration, delegation,
+// or deregistration
+type StakeEvent struct {
+	Kind  string
+	Pool  string // Pool holds the bech32 pool id of delegations
+	Epoch int32  // Epoch the event takes effect
+	TxID  string
+	Slot  int32
+}
+
+// Provider serves historical chain queries.  Lists are returned newest first
+// and paged using the id (hash) of the last item of the previous page as after.
+type Provider interface {
+	Block(ctx context.Context, hash string) (Block, error)
+	BlockByNumber(ctx context.Context, number int32) (Block, error)
+	Blocks(ctx context.Context, first int, after string) ([]Block, error)
+	BlockTransactions(ctx context.Context, hash string) ([]Tx, error)
+	Transaction(ctx context.Context, id string) (Tx, error)
+	AddressTransactions(ctx context.Context, address string, first int, after string) ([]Tx, error)
+	AssetHolders(ctx context.Context, policyID, assetNameHex string) ([]Holder, error)
+	AssetMints(ctx context.Context, policyID, assetNameHex string) ([]Mint, error)
+	StakeHistory(ctx context.Context, stakeAddress string) ([]StakeEvent, error)
+}
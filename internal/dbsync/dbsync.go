@@ -0,0 +1,530 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package dbsync implements chain.Provider using the Postgres database
+// populated by cardano-db-sync.  Queries target the db-sync 11 schema used by
+// the docker-compose deployment, where native tokens are held in ma_tx_out.
+package dbsync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+	_ "github.com/lib/pq"
+)
+
+// DefaultFirst is the default page size
+const DefaultFirst = 20
+
+// Config holds the Postgres connection settings, typically POSTGRES_*
+type Config struct {
+	Host     string
+	Port     string
+	DB       string
+	User     string
+	Password string
+	SSLMode  string // SSLMode defaults to disable
+}
+
+// Enabled returns true if a database host has been configured
+func (c Config) Enabled() bool {
+	return c.Host != ""
+}
+
+// DSN returns the lib/pq connection string for the config
+func (c Config) DSN() string {
+	sslMode := c.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	var parts []string
+	for _, item := range []struct{ key, value string }{
+		{key: "host", value: c.Host},
+		{key: "port", value: c.Port},
+		{key: "dbname", value: c.DB},
+		{key: "user", value: c.User},
+		{key: "password", value: c.Password},
+		{key: "sslmode", value: sslMode},
+	} {
+		if item.value == "" {
+			continue
+		}
+		value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(item.value)
+		parts = append(parts, fmt.Sprintf("%v='%v'", item.key, value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// DB queries db-sync
+type DB struct {
+	db *sql.DB
+}
+
+var _ chain.Provider = (*DB)(nil)
+
+// Open returns a DB for the configured database.  Connections are established
+// lazily so the toolkit may start before Postgres or db-sync are ready.
+func Open(config Config) (*DB, error) {
+	db, err := sql.Open("postgres", config.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open db-sync: %w", err)
+	}
+	return New(db), nil
+}
+
+// New returns a DB using an existing connection pool
+func New(db *sql.DB) *DB {
+	return &DB{db: db}
+}
+
+// Close the underlying connection pool
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+const selectBlock = `
+SELECT encode(b.hash, 'hex'), COALESCE(b.block_no, 0), COALESCE(b.slot_no, 0), COALESCE(b.epoch_no, 0), b.time, b.size, b.tx_count
+FROM block b`
+
+// Block returns the block with the hex encoded hash
+func (d *DB) Block(ctx context.Context, hash string) (chain.Block, error) {
+	row := d.db.QueryRowContext(ctx, selectBlock+` WHERE b.hash = decode($1, 'hex')`, hash)
+	block, err := scanBlock(row)
+	if err != nil {
+		return chain.Block{}, fmt.Errorf("failed to find block, %v: %w", hash, err)
+	}
+	return block, nil
+}
+
+// BlockByNumber returns the block with the block number
+func (d *DB) BlockByNumber(ctx context.Context, number int32) (chain.Block, error) {
+	row := d.db.QueryRowContext(ctx, selectBlock+` WHERE b.block_no = $1`, number)
+	block, err := scanBlock(row)
+	if err != nil {
+		return chain.Block{}, fmt.Errorf("failed to find block, %v: %w", number, err)
+	}
+	return block, nil
+}
+
+// Blocks returns blocks newest first starting after the block with hash after
+func (d *DB) Blocks(ctx context.Context, first int, after string) ([]chain.Block, error) {
+	rows, err := d.db.QueryContext(ctx, selectBlock+`
+WHERE b.block_no IS NOT NULL
+  AND ($2 = '' OR b.block_no < (SELECT block_no FROM block WHERE hash = decode($2, 'hex')))
+ORDER BY b.block_no DESC
+LIMIT $1`, pageSize(first), after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blocks: %w", err)
+	}
+	defer rows.Close()
+
+	var blocks []chain.Block
+	for rows.Next() {
+		block, err := scanBlock(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find blocks: %w", err)
+		}
+		blocks = append(blocks, block)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+const txColumns = `encode(tx.hash, 'hex'), encode(b.hash, 'hex'), COALESCE(b.block_no, 0), COALESCE(b.slot_no, 0), COALESCE(b.epoch_no, 0), b.time, tx.fee::text, tx.deposit::text, tx.size`
+
+// BlockTransactions returns the txs included in the block in block order
+func (d *DB) BlockTransactions(ctx context.Context, hash string) ([]chain.Tx, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT `+txColumns+`
+FROM tx
+JOIN block b ON b.id = tx.block_id
+WHERE b.hash = decode($1, 'hex')
+ORDER BY tx.block_index`, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find txs for block, %v: %w", hash, err)
+	}
+	defer rows.Close()
+
+	var txs []chain.Tx
+	for rows.Next() {
+		var tx chain.Tx
+		if err := rows.Scan(txFields(&tx)...); err != nil {
+			return nil, fmt.Errorf("failed to find txs for block, %v: %w", hash, err)
+		}
+		txs = append(txs, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find txs for block, %v: %w", hash, err)
+	}
+	return txs, nil
+}
+
+// Transaction returns the tx with the hex encoded id along with its inputs,
+// outputs, metadata, and certificates
+func (d *DB) Transaction(ctx context.Context, id string) (chain.Tx, error) {
+	var (
+		tx   chain.Tx
+		txID int64
+	)
+	row := d.db.QueryRowContext(ctx, `
+SELECT `+txColumns+`, tx.id
+FROM tx
+JOIN block b ON b.id = tx.block_id
+WHERE tx.hash = decode($1, 'hex')`, id)
+	if err := row.Scan(append(txFields(&tx), &txID)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = chain.ErrNotFound
+		}
+		return chain.Tx{}, fmt.Errorf("failed to find tx, %v: %w", id, err)
+	}
+
+	var err error
+	if tx.Inputs, err = d.txInputs(ctx, txID); err != nil {
+		return chain.Tx{}, fmt.Errorf("failed to find tx, %v: %w", id, err)
+	}
+	if tx.Outputs, err = d.txOutputs(ctx, txID); err != nil {
+		return chain.Tx{}, fmt.Errorf("failed to find tx, %v: %w", id, err)
+	}
+	if tx.Metadata, err = d.txMetadata(ctx, txID); err != nil {
+		return chain.Tx{}, fmt.Errorf("failed to find tx, %v: %w", id, err)
+	}
+	if tx.Certificates, err = d.txCertificates(ctx, txID); err != nil {
+		return chain.Tx{}, fmt.Errorf("failed to find tx, %v: %w", id, err)
+	}
+	return tx, nil
+}
+
+func (d *DB) txInputs(ctx context.Context, txID int64) ([]chain.Input, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT encode(src.hash, 'hex'), i.tx_out_index, COALESCE(o.address, ''), COALESCE(o.value::text, '')
+FROM tx_in i
+JOIN tx src ON src.id = i.tx_out_id
+LEFT JOIN tx_out o ON o.tx_id = i.tx_out_id AND o.index = i.tx_out_index
+WHERE i.tx_in_id = $1
+ORDER BY i.id`, txID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query inputs: %w", err)
+	}
+	defer rows.Close()
+
+	var inputs []chain.Input
+	for rows.Next() {
+		var input chain.Input
+		if err := rows.Scan(&input.TxID, &input.Index, &input.Address, &input.Value); err != nil {
+			return nil, fmt.Errorf("unable to scan input: %w", err)
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, rows.Err()
+}
+
+func (d *DB) txOutputs(ctx context.Context, txID int64) ([]cardano.TxOutput, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT o.index, o.address, o.value::text, COALESCE(encode(ma.policy, 'hex'), ''), COALESCE(encode(ma.name, 'hex'), ''), COALESCE(ma.quantity::text, '')
+FROM tx_out o
+LEFT JOIN ma_tx_out ma ON ma.tx_out_id = o.id
+WHERE o.tx_id = $1
+ORDER BY o.index, ma.id`, txID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query outputs: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		outputs []cardano.TxOutput
+		last    = -1
+	)
+	for rows.Next() {
+		var (
+			index                    int
+			output                   cardano.TxOutput
+			policyID, name, quantity string
+		)
+		if err := rows.Scan(&index, &output.Address, &output.Value, &policyID, &name, &quantity); err != nil {
+			return nil, fmt.Errorf("unable to scan output: %w", err)
+		}
+		if index != last {
+			outputs = append(outputs, output)
+			last = index
+		}
+		if policyID != "" {
+			last := &outputs[len(outputs)-1]
+			last.Tokens = append(last.Tokens, cardano.Token{
+				Asset:    &cardano.Asset{AssetName: name, PolicyId: policyID},
+				Quantity: quantity,
+			})
+		}
+	}
+	return outputs, rows.Err()
+}
+
+// txMetadata returns the json metadata of the tx as an object keyed by label
+func (d *DB) txMetadata(ctx context.Context, txID int64) (json.RawMessage, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT key::text, COALESCE(json::text, 'null')
+FROM tx_metadata
+WHERE tx_id = $1
+ORDER BY key`, txID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query metadata: %w", err)
+	}
+	defer rows.Close()
+
+	metadata := map[string]json.RawMessage{}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("unable to scan metadata: %w", err)
+		}
+		metadata[key] = json.RawMessage(value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(metadata)
+}
+
+func (d *DB) txCertificates(ctx context.Context, txID int64) ([]chain.Certificate, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT kind, stake_address, pool, epoch
+FROM (
+    SELECT $2::text AS kind, s.view::text AS stake_address, ''::text AS pool, r.epoch_no::bigint AS epoch, r.cert_index
+    FROM stake_registration r
+    JOIN stake_address s ON s.id = r.addr_id
+    WHERE r.tx_id = $1
+  UNION ALL
+    SELECT $3::text, s.view::text, p.view::text, d.active_epoch_no::bigint, d.cert_index
+    FROM delegation d
+    JOIN stake_address s ON s.id = d.addr_id
+    JOIN pool_hash p ON p.id = d.pool_hash_id
+    WHERE d.tx_id = $1
+  UNION ALL
+    SELECT $4::text, s.view::text, '', r.epoch_no::bigint, r.cert_index
+    FROM stake_deregistration r
+    JOIN stake_address s ON s.id = r.addr_id
+    WHERE r.tx_id = $1
+  UNION ALL
+    SELECT $5::text, '', p.view::text, u.active_epoch_no::bigint, u.cert_index
+    FROM pool_update u
+    JOIN pool_hash p ON p.id = u.hash_id
+    WHERE u.registered_tx_id = $1
+  UNION ALL
+    SELECT $6::text, '', p.view::text, r.retiring_epoch::bigint, r.cert_index
+    FROM pool_retire r
+    JOIN pool_hash p ON p.id = r.hash_id
+    WHERE r.announced_tx_id = $1
+) certs
+ORDER BY cert_index`,
+		txID, chain.StakeRegistration, chain.StakeDelegation, chain.StakeDeregistration, chain.PoolRegistration, chain.PoolRetirement)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query certificates: %w", err)
+	}
+	defer rows.Close()
+
+	var certificates []chain.Certificate
+	for rows.Next() {
+		var certificate chain.Certificate
+		if err := rows.Scan(&certificate.Kind, &certificate.StakeAddress, &certificate.Pool, &certificate.Epoch); err != nil {
+			return nil, fmt.Errorf("unable to scan certificate: %w", err)
+		}
+		certificates = append(certificates, certificate)
+	}
+	return certificates, rows.Err()
+}
+
+// AddressTransactions returns the txs that paid to or spent from the address
+func (d *DB) AddressTransactions(ctx context.Context, address string, first int, after string) ([]chain.Tx, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT `+txColumns+`
+FROM tx
+JOIN block b ON b.id = tx.block_id
+WHERE tx.id IN (
+    SELECT o.tx_id FROM tx_out o WHERE o.address = $1
+    UNION
+    SELECT i.tx_in_id
+    FROM tx_in i
+    JOIN tx_out o ON o.tx_id = i.tx_out_id AND o.index = i.tx_out_index
+    WHERE o.address = $1
+  )
+  AND ($3 = '' OR tx.id < (SELECT id FROM tx WHERE hash = decode($3, 'hex')))
+ORDER BY tx.id DESC
+LIMIT $2`, address, pageSize(first), after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find txs for address, %v: %w", address, err)
+	}
+	defer rows.Close()
+
+	var txs []chain.Tx
+	for rows.Next() {
+		var tx chain.Tx
+		if err := rows.Scan(txFields(&tx)...); err != nil {
+			return nil, fmt.Errorf("failed to find txs for address, %v: %w", address, err)
+		}
+		txs = append(txs, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find txs for address, %v: %w", address, err)
+	}
+	return txs, nil
+}
+
+// AssetHolders returns the addresses holding unspent outputs containing the
+// asset, largest holders first
+func (d *DB) AssetHolders(ctx context.Context, policyID, assetNameHex string) ([]chain.Holder, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT o.address, SUM(ma.quantity)::text
+FROM ma_tx_out ma
+JOIN tx_out o ON o.id = ma.tx_out_id
+LEFT JOIN tx_in i ON i.tx_out_id = o.tx_id AND i.tx_out_index = o.index
+WHERE ma.policy = decode($1, 'hex')
+  AND ma.name = decode($2, 'hex')
+  AND i.id IS NULL
+GROUP BY o.address
+ORDER BY SUM(ma.quantity) DESC, o.address`, policyID, assetNameHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find asset holders: %w", err)
+	}
+	defer rows.Close()
+
+	var holders []chain.Holder
+	for rows.Next() {
+		var holder chain.Holder
+		if err := rows.Scan(&holder.Address, &holder.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to find asset holders: %w", err)
+		}
+		holders = append(holders, holder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find asset holders: %w", err)
+	}
+	return holders, nil
+}
+
+// AssetMints returns the txs that minted or burned the asset, newest first
+func (d *DB) AssetMints(ctx context.Context, policyID, assetNameHex string) ([]chain.Mint, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT encode(tx.hash, 'hex'), COALESCE(b.slot_no, 0), b.time, m.quantity::text
+FROM ma_tx_mint m
+JOIN tx ON tx.id = m.tx_id
+JOIN block b ON b.id = tx.block_id
+WHERE m.policy = decode($1, 'hex')
+  AND m.name = decode($2, 'hex')
+ORDER BY tx.id DESC, m.id`, policyID, assetNameHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find asset mints: %w", err)
+	}
+	defer rows.Close()
+
+	var mints []chain.Mint
+	for rows.Next() {
+		var mint chain.Mint
+		if err := rows.Scan(&mint.TxID, &mint.Slot, &mint.Time, &mint.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to find asset mints: %w", err)
+		}
+		mints = append(mints, mint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find asset mints: %w", err)
+	}
+	return mints, nil
+}
+
+// StakeHistory returns the registrations, delegations, and deregistrations of
+// the stake address, newest first
+func (d *DB) StakeHistory(ctx context.Context, stakeAddress string) ([]chain.StakeEvent, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT kind, pool, epoch, encode(tx.hash, 'hex'), COALESCE(b.slot_no, 0)
+FROM (
+    SELECT $2::text AS kind, ''::text AS pool, r.epoch_no::bigint AS epoch, r.tx_id, r.cert_index
+    FROM stake_registration r
+    JOIN stake_address s ON s.id = r.addr_id
+    WHERE s.view = $1
+  UNION ALL
+    SELECT $3::text, p.view::text, d.active_epoch_no::bigint, d.tx_id, d.cert_index
+    FROM delegation d
+    JOIN stake_address s ON s.id = d.addr_id
+    JOIN pool_hash p ON p.id = d.pool_hash_id
+    WHERE s.view = $1
+  UNION ALL
+    SELECT $4::text, '', r.epoch_no::bigint, r.tx_id, r.cert_index
+    FROM stake_deregistration r
+    JOIN stake_address s ON s.id = r.addr_id
+    WHERE s.view = $1
+) events
+JOIN tx ON tx.id = events.tx_id
+JOIN block b ON b.id = tx.block_id
+ORDER BY tx.id DESC, events.cert_index DESC`,
+		stakeAddress, chain.StakeRegistration, chain.StakeDelegation, chain.StakeDeregistration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stake history, %v: %w", stakeAddress, err)
+	}
+	defer rows.Close()
+
+	var events []chain.StakeEvent
+	for rows.Next() {
+		var event chain.StakeEvent
+		if err := rows.Scan(&event.Kind, &event.Pool, &event.Epoch, &event.TxID, &event.Slot); err != nil {
+			return nil, fmt.Errorf("failed to find stake history, %v: %w", stakeAddress, err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find stake history, %v: %w", stakeAddress, err)
+	}
+	return events, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBlock(row scanner) (chain.Block, error) {
+	var block chain.Block
+	err := row.Scan(&block.Hash, &block.Number, &block.Slot, &block.Epoch, &block.Time, &block.Size, &block.TxCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return chain.Block{}, chain.ErrNotFound
+	}
+	return block, err
+}
+
+func txFields(tx *chain.Tx) []interface{} {
+	return []interface{}{&tx.ID, &tx.BlockHash, &tx.Block, &tx.Slot, &tx.Epoch, &tx.Time, &tx.Fee, &tx.Deposit, &tx.Size}
+}
+
+func pageSize(first int) int {
+	if first <= 0 {
+		return DefaultFirst
+	}
+	return first
+}
@@ -0,0 +1,186 @@
+package dbsync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+	"github.com/segmentio/ksuid"
+	"github.com/tj/assert"
+)
+
+func TestConfig_DSN(t *testing.T) {
+	config := Config{
+		Host:     "localhost",
+		Port:     "5432",
+		DB:       "cardano",
+		User:     "postgres",
+		Password: `it's secret`,
+	}
+	assert.Equal(t, `host='localhost' port='5432' dbname='cardano' user='postgres' password='it\'s secret' sslmode='disable'`, config.DSN())
+	assert.True(t, config.Enabled())
+	assert.False(t, Config{}.Enabled())
+}
+
+// fixtureDB loads testdata into a new schema of the Postgres database
+// identified by the POSTGRES_* env vars, skipping the test when unset
+func fixtureDB(t *testing.T) *DB {
+	config := Config{
+		Host:     os.Getenv("POSTGRES_HOST"),
+		Port:     os.Getenv("POSTGRES_PORT"),
+		DB:       os.Getenv("POSTGRES_DB"),
+		User:     os.Getenv("POSTGRES_USER"),
+		Password: os.Getenv("POSTGRES_PASSWORD"),
+	}
+	if !config.Enabled() {
+		t.Skip("POSTGRES_HOST not set")
+	}
+
+	schema := "dbsync_test_" + strings.ToLower(ksuid.New().String())
+	admin, err := sql.Open("postgres", config.DSN())
+	assert.Nil(t, err)
+	defer admin.Close()
+
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		db, err := sql.Open("postgres", config.DSN())
+		if err == nil {
+			_, _ = db.Exec("DROP SCHEMA " + schema + " CASCADE")
+			db.Close()
+		}
+	})
+
+	db, err := sql.Open("postgres", fmt.Sprintf("%v search_path='%v'", config.DSN(), schema))
+	assert.Nil(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	for _, filename := range []string{"testdata/schema.sql", "testdata/fixtures.sql"} {
+		data, err := ioutil.ReadFile(filename)
+		assert.Nil(t, err)
+		_, err = db.Exec(string(data))
+		assert.Nil(t, err, filename)
+	}
+
+	return New(db)
+}
+
+func TestDB(t *testing.T) {
+	ctx := context.Background()
+	db := fixtureDB(t)
+
+	t.Run("block", func(t *testing.T) {
+		block, err := db.Block(ctx, "02")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, block.Number)
+		assert.EqualValues(t, 20, block.Slot)
+		assert.EqualValues(t, 512, block.Size)
+		assert.EqualValues(t, 1, block.TxCount)
+
+		block, err = db.BlockByNumber(ctx, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, "03", block.Hash)
+		assert.EqualValues(t, 1, block.Epoch)
+
+		_, err = db.Block(ctx, "ff")
+		assert.True(t, errors.Is(err, chain.ErrNotFound))
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		blocks, err := db.Blocks(ctx, 2, "")
+		assert.Nil(t, err)
+		assert.Len(t, blocks, 2)
+		assert.Equal(t, "03", blocks[0].Hash)
+		assert.Equal(t, "02", blocks[1].Hash)
+
+		blocks, err = db.Blocks(ctx, 2, "02")
+		assert.Nil(t, err)
+		assert.Len(t, blocks, 1)
+		assert.Equal(t, "01", blocks[0].Hash)
+	})
+
+	t.Run("address transactions", func(t *testing.T) {
+		txs, err := db.AddressTransactions(ctx, "addr_test1alice", 10, "")
+		assert.Nil(t, err)
+		assert.Len(t, txs, 2)
+		assert.Equal(t, "bb", txs[0].ID)
+		assert.Equal(t, "200000", txs[0].Fee)
+		assert.EqualValues(t, 3, txs[0].Block)
+		assert.Equal(t, "aa", txs[1].ID)
+
+		txs, err = db.AddressTransactions(ctx, "addr_test1alice", 10, "bb")
+		assert.Nil(t, err)
+		assert.Len(t, txs, 1)
+		assert.Equal(t, "aa", txs[0].ID)
+
+		txs, err = db.AddressTransactions(ctx, "addr_test1treasury", 10, "")
+		assert.Nil(t, err)
+		assert.Len(t, txs, 1)
+	})
+
+	t.Run("block transactions", func(t *testing.T) {
+		txs, err := db.BlockTransactions(ctx, "03")
+		assert.Nil(t, err)
+		assert.Len(t, txs, 1)
+		assert.Equal(t, "bb", txs[0].ID)
+		assert.Equal(t, "03", txs[0].BlockHash)
+		assert.Equal(t, "2000000", txs[0].Deposit)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		tx, err := db.Transaction(ctx, "bb")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, tx.Epoch)
+		assert.Equal(t, []chain.Input{
+			{TxID: "aa", Index: 1, Address: "addr_test1alice", Value: "10000000"},
+		}, tx.Inputs)
+		assert.Len(t, tx.Outputs, 2)
+		assert.Equal(t, "addr_test1bob", tx.Outputs[1].Address)
+		assert.Len(t, tx.Outputs[1].Tokens, 1)
+		assert.Equal(t, "40", tx.Outputs[1].Tokens[0].Quantity)
+		assert.Equal(t, "544f4b", tx.Outputs[1].Tokens[0].Asset.AssetName)
+		assert.JSONEq(t, `{"674":{"msg":["hello"]}}`, string(tx.Metadata))
+		assert.Equal(t, []chain.Certificate{
+			{Kind: chain.StakeRegistration, StakeAddress: "stake_test1alice", Epoch: 1},
+			{Kind: chain.StakeDelegation, StakeAddress: "stake_test1alice", Pool: "pool1pool", Epoch: 3},
+		}, tx.Certificates)
+
+		_, err = db.Transaction(ctx, "ff")
+		assert.True(t, errors.Is(err, chain.ErrNotFound))
+	})
+
+	t.Run("asset holders", func(t *testing.T) {
+		holders, err := db.AssetHolders(ctx, "cafe", "544f4b")
+		assert.Nil(t, err)
+		assert.Equal(t, []chain.Holder{
+			{Address: "addr_test1alice", Quantity: "60"},
+			{Address: "addr_test1bob", Quantity: "40"},
+		}, holders)
+	})
+
+	t.Run("asset mints", func(t *testing.T) {
+		mints, err := db.AssetMints(ctx, "cafe", "544f4b")
+		assert.Nil(t, err)
+		assert.Len(t, mints, 1)
+		assert.Equal(t, "aa", mints[0].TxID)
+		assert.Equal(t, "100", mints[0].Quantity)
+		assert.EqualValues(t, 20, mints[0].Slot)
+	})
+
+	t.Run("stake history", func(t *testing.T) {
+		events, err := db.StakeHistory(ctx, "stake_test1alice")
+		assert.Nil(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, chain.StakeDelegation, events[0].Kind)
+		assert.Equal(t, "pool1pool", events[0].Pool)
+		assert.EqualValues(t, 3, events[0].Epoch)
+		assert.Equal(t, chain.StakeRegistration, events[1].Kind)
+		assert.Equal(t, "bb", events[1].TxID)
+	})
+}
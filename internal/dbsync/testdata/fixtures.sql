@@ -0,0 +1,44 @@
+-- three blocks; block 2 mints tokens to alice and funds alice and bob, block 3 has alice send tokens to bob
+-- with metadata and register and delegate her stake address
+
+INSERT INTO block (id, hash, epoch_no, slot_no, block_no, previous_id, size, time, tx_count) VALUES
+  (1, decode('01', 'hex'), 0, 10, 1, NULL, 4, '2021-09-01 00:00:10', 0),
+  (2, decode('02', 'hex'), 0, 20, 2, 1, 512, '2021-09-01 00:00:20', 1),
+  (3, decode('03', 'hex'), 1, 30, 3, 2, 1024, '2021-09-01 00:00:30', 1);
+
+INSERT INTO tx (id, hash, block_id, block_index, out_sum, fee, deposit, size) VALUES
+  (1, decode('aa', 'hex'), 2, 0, 30000000, 170000, 0, 300),
+  (2, decode('bb', 'hex'), 3, 0, 9800000, 200000, 2000000, 400);
+
+INSERT INTO tx_out (id, tx_id, index, address, value) VALUES
+  (1, 1, 0, 'addr_test1treasury', 10000000),
+  (2, 1, 1, 'addr_test1alice', 10000000),
+  (3, 1, 2, 'addr_test1bob', 10000000),
+  (4, 2, 0, 'addr_test1alice', 7800000),
+  (5, 2, 1, 'addr_test1bob', 2000000);
+
+INSERT INTO tx_in (id, tx_in_id, tx_out_id, tx_out_index) VALUES
+  (1, 2, 1, 1);
+
+INSERT INTO ma_tx_out (id, policy, name, quantity, tx_out_id) VALUES
+  (1, decode('cafe', 'hex'), decode('544f4b', 'hex'), 100, 2),
+  (2, decode('cafe', 'hex'), decode('544f4b', 'hex'), 60, 4),
+  (3, decode('cafe', 'hex'), decode('544f4b', 'hex'), 40, 5);
+
+INSERT INTO ma_tx_mint (id, policy, name, quantity, tx_id) VALUES
+  (1, decode('cafe', 'hex'), decode('544f4b', 'hex'), 100, 1);
+
+INSERT INTO stake_address (id, hash_raw, view) VALUES
+  (1, decode('e0a1', 'hex'), 'stake_test1alice');
+
+INSERT INTO pool_hash (id, hash_raw, view) VALUES
+  (1, decode('b1', 'hex'), 'pool1pool');
+
+INSERT INTO stake_registration (id, addr_id, cert_index, epoch_no, tx_id) VALUES
+  (1, 1, 0, 1, 2);
+
+INSERT INTO delegation (id, addr_id, cert_index, pool_hash_id, active_epoch_no, tx_id, slot_no) VALUES
+  (1, 1, 1, 1, 3, 2, 30);
+
+INSERT INTO tx_metadata (id, key, json, bytes, tx_id) VALUES
+  (1, 674, '{"msg": ["hello"]}', decode('a1', 'hex'), 2);
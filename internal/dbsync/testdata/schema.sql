@@ -0,0 +1,122 @@
+-- subset of the cardano-db-sync 11 schema queried by the toolkit
+
+CREATE TABLE block (
+  id          bigserial PRIMARY KEY,
+  hash        bytea NOT NULL UNIQUE,
+  epoch_no    integer,
+  slot_no     bigint,
+  block_no    integer,
+  previous_id bigint,
+  size        integer NOT NULL,
+  time        timestamp NOT NULL,
+  tx_count    bigint NOT NULL
+);
+
+CREATE TABLE tx (
+  id          bigserial PRIMARY KEY,
+  hash        bytea NOT NULL UNIQUE,
+  block_id    bigint NOT NULL REFERENCES block (id),
+  block_index integer NOT NULL,
+  out_sum     numeric(20, 0) NOT NULL,
+  fee         numeric(20, 0) NOT NULL,
+  deposit     bigint NOT NULL,
+  size        integer NOT NULL
+);
+
+CREATE TABLE tx_out (
+  id        bigserial PRIMARY KEY,
+  tx_id     bigint NOT NULL REFERENCES tx (id),
+  index     smallint NOT NULL,
+  address   varchar NOT NULL,
+  value     numeric(20, 0) NOT NULL,
+  data_hash bytea
+);
+
+CREATE TABLE tx_in (
+  id           bigserial PRIMARY KEY,
+  tx_in_id     bigint NOT NULL REFERENCES tx (id),
+  tx_out_id    bigint NOT NULL REFERENCES tx (id),
+  tx_out_index smallint NOT NULL
+);
+
+CREATE TABLE ma_tx_out (
+  id        bigserial PRIMARY KEY,
+  policy    bytea NOT NULL,
+  name      bytea NOT NULL,
+  quantity  numeric(20, 0) NOT NULL,
+  tx_out_id bigint NOT NULL REFERENCES tx_out (id)
+);
+
+CREATE TABLE ma_tx_mint (
+  id       bigserial PRIMARY KEY,
+  policy   bytea NOT NULL,
+  name     bytea NOT NULL,
+  quantity numeric(20, 0) NOT NULL,
+  tx_id    bigint NOT NULL REFERENCES tx (id)
+);
+
+CREATE TABLE tx_metadata (
+  id    bigserial PRIMARY KEY,
+  key   numeric(20, 0) NOT NULL,
+  json  jsonb,
+  bytes bytea NOT NULL,
+  tx_id bigint NOT NULL REFERENCES tx (id)
+);
+
+CREATE TABLE stake_address (
+  id       bigserial PRIMARY KEY,
+  hash_raw bytea NOT NULL UNIQUE,
+  view     varchar NOT NULL
+);
+
+CREATE TABLE pool_hash (
+  id       bigserial PRIMARY KEY,
+  hash_raw bytea NOT NULL UNIQUE,
+  view     varchar NOT NULL
+);
+
+CREATE TABLE stake_registration (
+  id         bigserial PRIMARY KEY,
+  addr_id    bigint NOT NULL REFERENCES stake_address (id),
+  cert_index integer NOT NULL,
+  epoch_no   integer NOT NULL,
+  tx_id      bigint NOT NULL REFERENCES tx (id)
+);
+
+CREATE TABLE stake_deregistration (
+  id         bigserial PRIMARY KEY,
+  addr_id    bigint NOT NULL REFERENCES stake_address (id),
+  cert_index integer NOT NULL,
+  epoch_no   integer NOT NULL,
+  tx_id      bigint NOT NULL REFERENCES tx (id)
+);
+
+CREATE TABLE delegation (
+  id              bigserial PRIMARY KEY,
+  addr_id         bigint NOT NULL REFERENCES stake_address (id),
+  cert_index      integer NOT NULL,
+  pool_hash_id    bigint NOT NULL REFERENCES pool_hash (id),
+  active_epoch_no bigint NOT NULL,
+  tx_id           bigint NOT NULL REFERENCES tx (id),
+  slot_no         bigint NOT NULL
+);
+
+CREATE TABLE pool_update (
+  id               bigserial PRIMARY KEY,
+  hash_id          bigint NOT NULL REFERENCES pool_hash (id),
+  cert_index       integer NOT NULL,
+  vrf_key_hash     bytea NOT NULL,
+  pledge           numeric(20, 0) NOT NULL,
+  active_epoch_no  bigint NOT NULL,
+  margin           double precision NOT NULL,
+  fixed_cost       numeric(20, 0) NOT NULL,
+  registered_tx_id bigint NOT NULL REFERENCES tx (id)
+);
+
+CREATE TABLE pool_retire (
+  id              bigserial PRIMARY KEY,
+  hash_id         bigint NOT NULL REFERENCES pool_hash (id),
+  cert_index      integer NOT NULL,
+  announced_tx_id bigint NOT NULL REFERENCES tx (id),
+  retiring_epoch  integer NOT NULL
+);
@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const configFile = "config.json" // configFile is the node config shared by every node
+
+// nodeConfig writes the node config shared by every node along with the
+// genesis hashes required by db-sync.  The chain starts in the alonzo era.
+func (g generator) nodeConfig(ctx context.Context) error {
+	hashes := map[string]string{}
+	for _, item := range []struct {
+		key      string
+		filename string
+		args     []string
+	}{
+		{key: "ByronGenesisHash", filename: byronGenesisFile, args: []string{"byron", "genesis", "print-genesis-hash", "--genesis-json"}},
+		{key: "ShelleyGenesisHash", filename: shelleyGenesisFile, args: []string{"genesis", "hash", "--genesis"}},
+		{key: "AlonzoGenesisHash", filename: alonzoGenesisFile, args: []string{"genesis", "hash", "--genesis"}},
+	} {
+		data, err := g.exec(ctx, append(item.args, g.path(item.filename))...)
+		if err != nil {
+			return fmt.Errorf("unable to hash %v: %w", item.filename, err)
+		}
+		hash := strings.TrimSpace(string(data))
+		hashFile := strings.TrimSuffix(item.filename, filepath.Ext(item.filename)) + ".hash"
+		if err := ioutil.WriteFile(g.path(hashFile), []byte(hash+"\n"), 0644); err != nil {
+			return fmt.Errorf("unable to write %v: %w", hashFile, err)
+		}
+		hashes[item.key] = hash
+	}
+
+	config := map[string]interface{}{
+		"Protocol":                    "Cardano",
+		"RequiresNetworkMagic":        "RequiresMagic",
+		"ByronGenesisFile":            byronGenesisFile,
+		"ByronGenesisHash":            hashes["ByronGenesisHash"],
+		"ShelleyGenesisFile":          shelleyGenesisFile,
+		"ShelleyGenesisHash":          hashes["ShelleyGenesisHash"],
+		"AlonzoGenesisFile":           alonzoGenesisFile,
+		"AlonzoGenesisHash":           hashes["AlonzoGenesisHash"],
+		"LastKnownBlockVersion-Major": 5,
+		"LastKnownBlockVersion-Minor": 0,
+		"LastKnownBlockVersion-Alt":   0,
+		"TestShelleyHardForkAtEpoch":  0,
+		"TestAllegraHardForkAtEpoch":  0,
+		"TestMaryHardForkAtEpoch":     0,
+		"TestAlonzoHardForkAtEpoch":   0,
+		"TurnOnLogging":               true,
+		"TurnOnLogMetrics":            false,
+		"minSeverity":                 "Info",
+		"setupBackends":               []string{"KatipBK"},
+		"defaultBackends":             []string{"KatipBK"},
+		"setupScribes": []map[string]string{
+			{"scKind": "StdoutSK", "scName": "stdout", "scFormat": "ScText"},
+		},
+		"defaultScribes": [][]string{{"StdoutSK", "stdout"}},
+		"options":        map[string]interface{}{},
+	}
+	if err := writeJSON(g.path(configFile), config); err != nil {
+		return fmt.Errorf("unable to write node config: %w", err)
+	}
+	return nil
+}
+
+// topology writes the topology of node, which connects to every other node
+func (g generator) topology(node Node, nodes []Node) error {
+	type Producer struct {
+		Addr    string `json:"addr"`
+		Port    int    `json:"port"`
+		Valency int    `json:"valency"`
+	}
+
+	producers := []Producer{}
+	for _, peer := range nodes {
+		if peer.Name == node.Name {
+			continue
+		}
+		producers = append(producers, Producer{Addr: "127.0.0.1", Port: peer.Port, Valency: 1})
+	}
+
+	topology := map[string]interface{}{"Producers": producers}
+	if err := writeJSON(filepath.Join(node.Dir, "topology.json"), topology); err != nil {
+		return fmt.Errorf("unable to write %v topology: %w", node.Name, err)
+	}
+	return nil
+}
+
+// runScript writes run.sh, which starts the node from its own dir
+func (g generator) runScript(node Node) error {
+	args := []string{
+		"cardano-node", "run",
+		"--config", filepath.Join("..", configFile),
+		"--topology", "topology.json",
+		"--database-path", "db",
+		"--socket-path", "node.sock",
+		"--host-addr", "127.0.0.1",
+		"--port", strconv.Itoa(node.Port),
+		"--shelley-kes-key", "shelley/kes.skey",
+		"--shelley-vrf-key", "shelley/vrf.skey",
+		"--shelley-operational-certificate", "shelley/node.cert",
+	}
+	if !node.Pool {
+		args = append(args,
+			"--byron-delegation-certificate", "byron/delegate.cert",
+			"--byron-signing-key", "byron/delegate.key",
+		)
+	}
+
+	script := "#!/bin/sh\n\n" +
+		"cd \"$(dirname \"$0\")\"\n\n" +
+		"exec " + strings.Join(args, " \\\n  ") + "\n"
+	if err := ioutil.WriteFile(filepath.Join(node.Dir, "run.sh"), []byte(script), 0755); err != nil {
+		return fmt.Errorf("unable to write %v run script: %w", node.Name, err)
+	}
+	return nil
+}
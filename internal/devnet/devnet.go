@@ -0,0 +1,493 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package devnet prepares the files needed to run a fresh local cardano chain:
+// byron, shelley, and alonzo genesis files, BFT and stake pool keys, a node
+// config and topology, and a treasury address funded at genesis.  Keys and
+// genesis files are generated with cardano-cli; everything else is written
+// from Go so a devnet can be prepared reproducibly without hand run scripts.
+package devnet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTestnetMagic is the default network magic of the devnet
+	DefaultTestnetMagic = "42"
+
+	// DefaultBFTNodes is the default number of BFT nodes
+	DefaultBFTNodes = 2
+
+	// DefaultPools is the default number of stake pools
+	DefaultPools = 1
+
+	// DefaultSupply is the default lovelace held by the genesis utxo keys
+	DefaultSupply = "1000000000000000"
+
+	// DefaultTreasuryFunds is the default lovelace paid to the treasury at genesis
+	DefaultTreasuryFunds = "10000000000000000"
+
+	// DefaultSlotLength is the default slot length
+	DefaultSlotLength = time.Second
+
+	// DefaultEpochLength is the default number of slots per epoch
+	DefaultEpochLength = 500
+
+	// DefaultSecurityParam is the default security parameter, k
+	DefaultSecurityParam = 10
+
+	// DefaultBasePort is the port of the first node; each node listens on the next port
+	DefaultBasePort = 3001
+
+	// DefaultStartDelay is the default delay before the chain starts
+	DefaultStartDelay = 30 * time.Second
+)
+
+// ExecFunc runs cardano-cli with args and returns its output
+type ExecFunc func(ctx context.Context, args ...string) ([]byte, error)
+
+// Option configures Init
+type Option func(*options)
+
+type options struct {
+	exec           ExecFunc
+	magic          string
+	bftNodes       int
+	pools          int
+	supply         string
+	treasuryFunds  string
+	slotLength     time.Duration
+	epochLength    int
+	securityParam  int
+	basePort       int
+	startTime      time.Time
+	alonzoTemplate string
+}
+
+// Cmd sets the cardano-cli invocation e.g. cardano-cli or docker exec node cardano-cli
+func Cmd(cmd ...string) Option {
+	return func(o *options) {
+		if len(cmd) > 0 {
+			o.exec = execCmd(cmd)
+		}
+	}
+}
+
+// Exec overrides how cardano-cli is run
+func Exec(fn ExecFunc) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.exec = fn
+		}
+	}
+}
+
+// TestnetMagic sets the network magic of the devnet
+func TestnetMagic(magic string) Option {
+	return func(o *options) {
+		if magic != "" {
+			o.magic = magic
+		}
+	}
+}
+
+// BFTNodes sets the number of BFT nodes, each holding a genesis delegate key
+func BFTNodes(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.bftNodes = n
+		}
+	}
+}
+
+// Pools sets the number of stake pools to generate keys and certificates for
+func Pools(n int) Option {
+	return func(o *options) {
+		if n >= 0 {
+			o.pools = n
+		}
+	}
+}
+
+// Supply sets the lovelace held by the genesis utxo keys
+func Supply(lovelace string) Option {
+	return func(o *options) {
+		if lovelace != "" {
+			o.supply = lovelace
+		}
+	}
+}
+
+// TreasuryFunds sets the lovelace paid to the treasury address at genesis
+func TreasuryFunds(lovelace string) Option {
+	return func(o *options) {
+		if lovelace != "" {
+			o.treasuryFunds = lovelace
+		}
+	}
+}
+
+// SlotLength sets the slot length
+func SlotLength(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.slotLength = d
+		}
+	}
+}
+
+// EpochLength sets the number of slots per epoch
+func EpochLength(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.epochLength = n
+		}
+	}
+}
+
+// SecurityParam sets the security parameter, k
+func SecurityParam(k int) Option {
+	return func(o *options) {
+		if k > 0 {
+			o.securityParam = k
+		}
+	}
+}
+
+// BasePort sets the port of the first node
+func BasePort(port int) Option {
+	return func(o *options) {
+		if port > 0 {
+			o.basePort = port
+		}
+	}
+}
+
+// StartTime sets the system start of the chain
+func StartTime(t time.Time) Option {
+	return func(o *options) {
+		if !t.IsZero() {
+			o.startTime = t
+		}
+	}
+}
+
+// AlonzoTemplate sets the alonzo genesis spec used in place of the default,
+// e.g. to provide plutus cost models
+func AlonzoTemplate(filename string) Option {
+	return func(o *options) {
+		o.alonzoTemplate = filename
+	}
+}
+
+// Node describes a generated node
+type Node struct {
+	Name string // Name of the node e.g. node-bft1 or node-pool1
+	Dir  string // Dir holds the keys, topology, and run script of the node
+	Port int    // Port the node listens on
+	Pool bool   // Pool is true for stake pool nodes and false for BFT nodes
+}
+
+// Devnet describes the generated devnet
+type Devnet struct {
+	Dir              string
+	TestnetMagic     string
+	Config           string // Config holds the path of the node config
+	ByronGenesis     string
+	ShelleyGenesis   string
+	AlonzoGenesis    string
+	TreasuryAddr     string
+	TreasuryAddrFile string
+	TreasurySkeyFile string
+	Nodes            []Node
+}
+
+// Init generates a devnet into dir, which must not exist or be empty
+func Init(ctx context.Context, dir string, opts ...Option) (Devnet, error) {
+	o := options{
+		exec:          execCmd([]string{"cardano-cli"}),
+		magic:         DefaultTestnetMagic,
+		bftNodes:      DefaultBFTNodes,
+		pools:         DefaultPools,
+		supply:        DefaultSupply,
+		treasuryFunds: DefaultTreasuryFunds,
+		slotLength:    DefaultSlotLength,
+		epochLength:   DefaultEpochLength,
+		securityParam: DefaultSecurityParam,
+		basePort:      DefaultBasePort,
+		startTime:     time.Now().Add(DefaultStartDelay),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Devnet{}, fmt.Errorf("failed to init devnet: %w", err)
+	}
+	if files, err := ioutil.ReadDir(dir); err == nil && len(files) > 0 {
+		return Devnet{}, fmt.Errorf("failed to init devnet: dir, %v, is not empty", dir)
+	}
+
+	g := generator{options: o, dir: dir}
+	devnet, err := g.generate(ctx)
+	if err != nil {
+		return Devnet{}, fmt.Errorf("failed to init devnet: %w", err)
+	}
+	return devnet, nil
+}
+
+// generator generates the files of a devnet
+type generator struct {
+	options
+	dir string
+}
+
+func (g generator) generate(ctx context.Context) (Devnet, error) {
+	for _, path := range []string{"addresses", "shelley"} {
+		if err := os.MkdirAll(g.path(path), 0755); err != nil {
+			return Devnet{}, fmt.Errorf("unable to create dir: %w", err)
+		}
+	}
+
+	if err := g.byronGenesis(ctx); err != nil {
+		return Devnet{}, err
+	}
+	if err := g.shelleyGenesis(ctx); err != nil {
+		return Devnet{}, err
+	}
+	treasuryAddr, err := g.address(ctx, "treasury")
+	if err != nil {
+		return Devnet{}, err
+	}
+	if err := g.fundGenesis(treasuryAddr); err != nil {
+		return Devnet{}, err
+	}
+
+	var nodes []Node
+	for i := 1; i <= g.bftNodes; i++ {
+		node, err := g.bftNode(ctx, i, g.basePort+len(nodes))
+		if err != nil {
+			return Devnet{}, err
+		}
+		nodes = append(nodes, node)
+	}
+	for i := 1; i <= g.pools; i++ {
+		node, err := g.poolNode(ctx, i, g.basePort+len(nodes))
+		if err != nil {
+			return Devnet{}, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if err := g.nodeConfig(ctx); err != nil {
+		return Devnet{}, err
+	}
+	for _, node := range nodes {
+		if err := g.topology(node, nodes); err != nil {
+			return Devnet{}, err
+		}
+		if err := g.runScript(node); err != nil {
+			return Devnet{}, err
+		}
+	}
+
+	return Devnet{
+		Dir:              g.dir,
+		TestnetMagic:     g.magic,
+		Config:           g.path(configFile),
+		ByronGenesis:     g.path(byronGenesisFile),
+		ShelleyGenesis:   g.path(shelleyGenesisFile),
+		AlonzoGenesis:    g.path(alonzoGenesisFile),
+		TreasuryAddr:     treasuryAddr,
+		TreasuryAddrFile: g.path("addresses", "treasury.addr"),
+		TreasurySkeyFile: g.path("addresses", "treasury.skey"),
+		Nodes:            nodes,
+	}, nil
+}
+
+// address generates payment and stake keys along with the payment address,
+// stake address, and stake registration certificate of addresses/{name}
+func (g generator) address(ctx context.Context, name string) (string, error) {
+	prefix := g.path("addresses", name)
+	steps := [][]string{
+		{"address", "key-gen", "--verification-key-file", prefix + ".vkey", "--signing-key-file", prefix + ".skey"},
+		{"stake-address", "key-gen", "--verification-key-file", prefix + "-stake.vkey", "--signing-key-file", prefix + "-stake.skey"},
+		{"address", "build", "--payment-verification-key-file", prefix + ".vkey", "--stake-verification-key-file", prefix + "-stake.vkey", "--testnet-magic", g.magic, "--out-file", prefix + ".addr"},
+		{"stake-address", "build", "--stake-verification-key-file", prefix + "-stake.vkey", "--testnet-magic", g.magic, "--out-file", prefix + "-stake.addr"},
+		{"stake-address", "registration-certificate", "--stake-verification-key-file", prefix + "-stake.vkey", "--out-file", prefix + "-stake.reg.cert"},
+	}
+	if err := g.run(ctx, steps...); err != nil {
+		return "", fmt.Errorf("unable to generate address, %v: %w", name, err)
+	}
+
+	data, err := ioutil.ReadFile(prefix + ".addr")
+	if err != nil {
+		return "", fmt.Errorf("unable to read address, %v: %w", name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// bftNode copies the genesis delegate keys of BFT node i into node-bft{i} and
+// issues its KES key and operational certificate
+func (g generator) bftNode(ctx context.Context, i, port int) (Node, error) {
+	name := "node-bft" + strconv.Itoa(i)
+	for _, path := range []string{"shelley", "byron"} {
+		if err := os.MkdirAll(g.path(name, path), 0755); err != nil {
+			return Node{}, fmt.Errorf("unable to create dir: %w", err)
+		}
+	}
+
+	var (
+		delegate = g.path("shelley", "delegate-keys", "delegate"+strconv.Itoa(i))
+		index    = fmt.Sprintf("%03d", i-1) // byron delegate keys are numbered from 000
+	)
+	for src, dst := range map[string]string{
+		delegate + ".skey":     g.path(name, "shelley", "operator.skey"),
+		delegate + ".vkey":     g.path(name, "shelley", "operator.vkey"),
+		delegate + ".counter":  g.path(name, "shelley", "operator.counter"),
+		delegate + ".vrf.skey": g.path(name, "shelley", "vrf.skey"),
+		delegate + ".vrf.vkey": g.path(name, "shelley", "vrf.vkey"),
+		g.path(byronGenCommandDir, "delegate-keys."+index+".key"):    g.path(name, "byron", "delegate.key"),
+		g.path(byronGenCommandDir, "delegation-cert."+index+".json"): g.path(name, "byron", "delegate.cert"),
+	} {
+		if err := copyFile(src, dst); err != nil {
+			return Node{}, fmt.Errorf("unable to create %v: %w", name, err)
+		}
+	}
+
+	if err := g.operationalCertificate(ctx, name); err != nil {
+		return Node{}, err
+	}
+	return Node{Name: name, Dir: g.path(name), Port: port}, nil
+}
+
+// poolNode generates the cold, VRF, and KES keys of stake pool i into
+// node-pool{i}, along with the pool owner address and the certificates needed
+// to register the pool
+func (g generator) poolNode(ctx context.Context, i, port int) (Node, error) {
+	name := "node-pool" + strconv.Itoa(i)
+	if err := os.MkdirAll(g.path(name, "shelley"), 0755); err != nil {
+		return Node{}, fmt.Errorf("unable to create dir: %w", err)
+	}
+
+	shelley := g.path(name, "shelley")
+	steps := [][]string{
+		{"node", "key-gen", "--cold-verification-key-file", filepath.Join(shelley, "operator.vkey"), "--cold-signing-key-file", filepath.Join(shelley, "operator.skey"), "--operational-certificate-issue-counter-file", filepath.Join(shelley, "operator.counter")},
+		{"node", "key-gen-VRF", "--verification-key-file", filepath.Join(shelley, "vrf.vkey"), "--signing-key-file", filepath.Join(shelley, "vrf.skey")},
+	}
+	if err := g.run(ctx, steps...); err != nil {
+		return Node{}, fmt.Errorf("unable to create %v: %w", name, err)
+	}
+	if err := g.operationalCertificate(ctx, name); err != nil {
+		return Node{}, err
+	}
+
+	owner := "pool-owner" + strconv.Itoa(i)
+	if _, err := g.address(ctx, owner); err != nil {
+		return Node{}, err
+	}
+
+	ownerStake := g.path("addresses", owner+"-stake.vkey")
+	err := g.run(ctx, []string{
+		"stake-pool", "registration-certificate",
+		"--cold-verification-key-file", filepath.Join(shelley, "operator.vkey"),
+		"--vrf-verification-key-file", filepath.Join(shelley, "vrf.vkey"),
+		"--pool-pledge", "0",
+		"--pool-cost", "0",
+		"--pool-margin", "0",
+		"--pool-reward-account-verification-key-file", ownerStake,
+		"--pool-owner-stake-verification-key-file", ownerStake,
+		"--pool-relay-ipv4", "127.0.0.1",
+		"--pool-relay-port", strconv.Itoa(port),
+		"--testnet-magic", g.magic,
+		"--out-file", g.path(name, "registration.cert"),
+	})
+	if err != nil {
+		return Node{}, fmt.Errorf("unable to create %v registration certificate: %w", name, err)
+	}
+
+	return Node{Name: name, Dir: g.path(name), Port: port, Pool: true}, nil
+}
+
+// operationalCertificate generates the KES key of a node and issues its
+// operational certificate using the node's cold key
+func (g generator) operationalCertificate(ctx context.Context, name string) error {
+	shelley := g.path(name, "shelley")
+	steps := [][]string{
+		{"node", "key-gen-KES", "--verification-key-file", filepath.Join(shelley, "kes.vkey"), "--signing-key-file", filepath.Join(shelley, "kes.skey")},
+		{"node", "issue-op-cert", "--kes-verification-key-file", filepath.Join(shelley, "kes.vkey"), "--cold-signing-key-file", filepath.Join(shelley, "operator.skey"), "--operational-certificate-issue-counter", filepath.Join(shelley, "operator.counter"), "--kes-period", "0", "--out-file", filepath.Join(shelley, "node.cert")},
+	}
+	if err := g.run(ctx, steps...); err != nil {
+		return fmt.Errorf("unable to issue %v operational certificate: %w", name, err)
+	}
+	return nil
+}
+
+// run runs each cardano-cli command in turn
+func (g generator) run(ctx context.Context, commands ...[]string) error {
+	for _, args := range commands {
+		if _, err := g.exec(ctx, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns the path of elem within the devnet dir
+func (g generator) path(elem ...string) string {
+	return filepath.Join(append([]string{g.dir}, elem...)...)
+}
+
+// execCmd returns an ExecFunc that runs cmd
+func execCmd(cmd []string) ExecFunc {
+	return func(ctx context.Context, args ...string) ([]byte, error) {
+		stmt := append(cmd[0:len(cmd):len(cmd)], args...)
+		buf := bytes.NewBuffer(nil)
+		c := exec.CommandContext(ctx, stmt[0], stmt[1:]...)
+		c.Stdout = buf
+		c.Stderr = buf
+		if err := c.Run(); err != nil {
+			return nil, fmt.Errorf("exec failed: %v -> %v: %w", strings.Join(stmt, " "), buf.String(), err)
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// copyFile copies src to dst
+func copyFile(src, dst string) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, data, 0600)
+}
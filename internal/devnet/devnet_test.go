@@ -0,0 +1,130 @@
+package devnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/tj/assert"
+)
+
+// fakeCLI mimics the files written by cardano-cli
+type fakeCLI struct {
+	t        *testing.T
+	address  []byte
+	commands []string
+}
+
+func (f *fakeCLI) exec(_ context.Context, args ...string) ([]byte, error) {
+	command := strings.Join(args, " ")
+	f.commands = append(f.commands, command)
+
+	flag := func(name string) string {
+		for i, arg := range args {
+			if arg == name && i+1 < len(args) {
+				return args[i+1]
+			}
+		}
+		f.t.Fatalf("flag, %v, not found in %v", name, command)
+		return ""
+	}
+	write := func(filename, content string) {
+		assert.Nil(f.t, os.MkdirAll(filepath.Dir(filename), 0755))
+		assert.Nil(f.t, ioutil.WriteFile(filename, []byte(content), 0644))
+	}
+
+	switch {
+	case strings.HasPrefix(command, "byron genesis genesis"):
+		dir := flag("--genesis-output-dir")
+		write(filepath.Join(dir, "genesis.json"), "{}")
+		for i := 0; i < 2; i++ {
+			write(filepath.Join(dir, fmt.Sprintf("delegate-keys.%03d.key", i)), "key")
+			write(filepath.Join(dir, fmt.Sprintf("delegation-cert.%03d.json", i)), "{}")
+		}
+	case strings.HasPrefix(command, "genesis create"):
+		dir := flag("--genesis-dir")
+		write(filepath.Join(dir, "genesis.json"), `{"initialFunds":{"00":1000},"maxLovelaceSupply":1000,"protocolParams":{"protocolVersion":{"major":2,"minor":0}}}`)
+		data, err := ioutil.ReadFile(filepath.Join(dir, "genesis.alonzo.spec.json"))
+		assert.Nil(f.t, err)
+		write(filepath.Join(dir, "genesis.alonzo.json"), string(data))
+		for i := 1; i <= 2; i++ {
+			for _, ext := range []string{"skey", "vkey", "counter", "vrf.skey", "vrf.vkey"} {
+				write(filepath.Join(dir, "delegate-keys", fmt.Sprintf("delegate%v.%v", i, ext)), "{}")
+			}
+		}
+	case strings.HasPrefix(command, "address build"):
+		address, err := cardano.Bech32Encode("addr_test", f.address)
+		assert.Nil(f.t, err)
+		write(flag("--out-file"), address)
+	case strings.Contains(command, "genesis hash"), strings.Contains(command, "print-genesis-hash"):
+		return []byte("abc123\n"), nil
+	default:
+		// inputs already exist so only outputs are written
+		for i, arg := range args {
+			if strings.HasSuffix(arg, "-file") && i+1 < len(args) {
+				if _, err := os.Stat(args[i+1]); os.IsNotExist(err) {
+					write(args[i+1], "{}")
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+func TestInit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devnet")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	fake := &fakeCLI{t: t, address: append([]byte{0x00}, bytes.Repeat([]byte{0x01}, 56)...)}
+	ctx := context.Background()
+	devnet, err := Init(ctx, dir,
+		Exec(fake.exec),
+		TestnetMagic("31415"),
+		StartTime(time.Unix(1600000000, 0)),
+		TreasuryFunds("5000"),
+		Supply("1000"),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "31415", devnet.TestnetMagic)
+	assert.Len(t, devnet.Nodes, 3)
+	assert.Equal(t, 3003, devnet.Nodes[2].Port)
+	assert.True(t, devnet.Nodes[2].Pool)
+
+	genesis, err := readJSON(devnet.ShelleyGenesis)
+	assert.Nil(t, err)
+	initialFunds := genesis["initialFunds"].(map[string]interface{})
+	assert.EqualValues(t, "5000", initialFunds[hex.EncodeToString(fake.address)])
+	assert.EqualValues(t, "7000", genesis["maxLovelaceSupply"])
+	assert.EqualValues(t, "1", genesis["slotLength"])
+
+	config, err := readJSON(devnet.Config)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc123", config["ShelleyGenesisHash"])
+
+	for _, filename := range []string{
+		"node-bft1/shelley/node.cert",
+		"node-bft2/byron/delegate.key",
+		"node-pool1/registration.cert",
+		"node-pool1/run.sh",
+		"node-pool1/topology.json",
+		"addresses/pool-owner1-stake.reg.cert",
+		"addresses/treasury.skey",
+		"byron-genesis.hash",
+	} {
+		_, err := os.Stat(filepath.Join(dir, filename))
+		assert.Nil(t, err, filename)
+	}
+
+	// refuse to overwrite an existing devnet
+	_, err = Init(ctx, dir, Exec(fake.exec))
+	assert.NotNil(t, err)
+}
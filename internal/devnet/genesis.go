@@ -0,0 +1,228 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package devnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+)
+
+const (
+	byronGenCommandDir = "byron-gen-command"    // byronGenCommandDir holds the output of byron genesis genesis
+	byronGenesisFile   = "byron-genesis.json"   // byronGenesisFile is the byron genesis referenced by the node config
+	shelleyGenesisFile = "shelley-genesis.json" // shelleyGenesisFile is the shelley genesis referenced by the node config
+	alonzoGenesisFile  = "alonzo-genesis.json"  // alonzoGenesisFile is the alonzo genesis referenced by the node config
+
+	// activeSlotsCoeff is the fraction of slots in which a block is expected
+	activeSlotsCoeff = "0.5"
+)
+
+// byronParams holds the byron protocol parameters of the devnet
+var byronParams = map[string]interface{}{
+	"heavyDelThd":     "300000000000",
+	"maxBlockSize":    "2000000",
+	"maxTxSize":       "4096",
+	"maxHeaderSize":   "2000000",
+	"maxProposalSize": "700",
+	"mpcThd":          "20000000000000",
+	"scriptVersion":   0,
+	"slotDuration":    "1000",
+	"softforkRule": map[string]string{
+		"initThd":      "900000000000000",
+		"minThd":       "600000000000000",
+		"thdDecrement": "50000000000000",
+	},
+	"txFeePolicy": map[string]string{
+		"multiplier": "43946000000",
+		"summand":    "155381000000000",
+	},
+	"unlockStakeEpoch":  "18446744073709551615",
+	"updateImplicit":    "10000",
+	"updateProposalThd": "100000000000000",
+	"updateVoteThd":     "1000000000000",
+}
+
+// alonzoSpec holds the default alonzo genesis spec.  Plutus cost models are
+// left empty; provide them via AlonzoTemplate to run plutus scripts.
+var alonzoSpec = map[string]interface{}{
+	"lovelacePerUTxOWord": 34482,
+	"executionPrices": map[string]interface{}{
+		"prSteps": map[string]int{"numerator": 721, "denominator": 10000000},
+		"prMem":   map[string]int{"numerator": 577, "denominator": 10000},
+	},
+	"maxTxExUnits":         map[string]int64{"exUnitsMem": 10000000, "exUnitsSteps": 10000000000},
+	"maxBlockExUnits":      map[string]int64{"exUnitsMem": 50000000, "exUnitsSteps": 40000000000},
+	"maxValueSize":         5000,
+	"collateralPercentage": 150,
+	"maxCollateralInputs":  3,
+	"costModels":           map[string]interface{}{},
+}
+
+// byronGenesis generates the byron genesis along with the byron delegate keys
+// of the BFT nodes
+func (g generator) byronGenesis(ctx context.Context) error {
+	params := g.path("byron.params.json")
+	if err := writeJSON(params, byronParams); err != nil {
+		return fmt.Errorf("unable to write byron params: %w", err)
+	}
+
+	_, err := g.exec(ctx,
+		"byron", "genesis", "genesis",
+		"--protocol-magic", g.magic,
+		"--start-time", strconv.FormatInt(g.startTime.Unix(), 10),
+		"--k", strconv.Itoa(g.securityParam),
+		"--n-poor-addresses", "0",
+		"--n-delegate-addresses", strconv.Itoa(g.bftNodes),
+		"--total-balance", g.supply,
+		"--delegate-share", "1",
+		"--avvm-entry-count", "0",
+		"--avvm-entry-balance", "0",
+		"--protocol-parameters-file", params,
+		"--genesis-output-dir", g.path(byronGenCommandDir),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to generate byron genesis: %w", err)
+	}
+
+	if err := copyFile(g.path(byronGenCommandDir, "genesis.json"), g.path(byronGenesisFile)); err != nil {
+		return fmt.Errorf("unable to copy byron genesis: %w", err)
+	}
+	return nil
+}
+
+// shelleyGenesis generates the shelley and alonzo genesis files along with the
+// genesis, delegate, and utxo keys
+func (g generator) shelleyGenesis(ctx context.Context) error {
+	spec := g.path("shelley", "genesis.alonzo.spec.json")
+	if g.alonzoTemplate != "" {
+		if err := copyFile(g.alonzoTemplate, spec); err != nil {
+			return fmt.Errorf("unable to copy alonzo template: %w", err)
+		}
+	} else if err := writeJSON(spec, alonzoSpec); err != nil {
+		return fmt.Errorf("unable to write alonzo genesis spec: %w", err)
+	}
+
+	_, err := g.exec(ctx,
+		"genesis", "create",
+		"--genesis-dir", g.path("shelley"),
+		"--gen-genesis-keys", strconv.Itoa(g.bftNodes),
+		"--gen-utxo-keys", "1",
+		"--start-time", g.startTime.UTC().Format("2006-01-02T15:04:05Z"),
+		"--supply", g.supply,
+		"--testnet-magic", g.magic,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to generate shelley genesis: %w", err)
+	}
+
+	genesis, err := readJSON(g.path("shelley", "genesis.json"))
+	if err != nil {
+		return fmt.Errorf("unable to read shelley genesis: %w", err)
+	}
+	genesis["slotLength"] = json.Number(strconv.FormatFloat(g.slotLength.Seconds(), 'f', -1, 64))
+	genesis["activeSlotsCoeff"] = json.Number(activeSlotsCoeff)
+	genesis["securityParam"] = g.securityParam
+	genesis["epochLength"] = g.epochLength
+	genesis["updateQuorum"] = g.bftNodes
+	if params, ok := genesis["protocolParams"].(map[string]interface{}); ok {
+		params["protocolVersion"] = map[string]int{"major": 5, "minor": 0} // alonzo
+	}
+	if err := writeJSON(g.path(shelleyGenesisFile), genesis); err != nil {
+		return fmt.Errorf("unable to write shelley genesis: %w", err)
+	}
+
+	if err := copyFile(g.path("shelley", "genesis.alonzo.json"), g.path(alonzoGenesisFile)); err != nil {
+		return fmt.Errorf("unable to copy alonzo genesis: %w", err)
+	}
+	return nil
+}
+
+// fundGenesis pays the treasury funds to address at genesis and raises the max
+// lovelace supply to cover the byron, shelley, and treasury funds
+func (g generator) fundGenesis(address string) error {
+	_, data, err := cardano.Bech32Decode(address)
+	if err != nil {
+		return fmt.Errorf("unable to fund treasury: %w", err)
+	}
+
+	supply, ok := big.NewInt(0).SetString(g.supply, 10)
+	if !ok {
+		return fmt.Errorf("unable to fund treasury: invalid supply, %v", g.supply)
+	}
+	funds, ok := big.NewInt(0).SetString(g.treasuryFunds, 10)
+	if !ok {
+		return fmt.Errorf("unable to fund treasury: invalid treasury funds, %v", g.treasuryFunds)
+	}
+	total := big.NewInt(0).Add(big.NewInt(0).Mul(supply, big.NewInt(2)), funds)
+
+	filename := g.path(shelleyGenesisFile)
+	genesis, err := readJSON(filename)
+	if err != nil {
+		return fmt.Errorf("unable to fund treasury: %w", err)
+	}
+	initialFunds, ok := genesis["initialFunds"].(map[string]interface{})
+	if !ok {
+		initialFunds = map[string]interface{}{}
+	}
+	initialFunds[hex.EncodeToString(data)] = json.Number(funds.String())
+	genesis["initialFunds"] = initialFunds
+	genesis["maxLovelaceSupply"] = json.Number(total.String())
+
+	if err := writeJSON(filename, genesis); err != nil {
+		return fmt.Errorf("unable to fund treasury: %w", err)
+	}
+	return nil
+}
+
+// readJSON reads a json object preserving the precision of numbers
+func readJSON(filename string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var v map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %w", filename, err)
+	}
+	return v, nil
+}
+
+// writeJSON writes v as indented json
+func writeJSON(filename string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
@@ -0,0 +1,221 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package faucet queues treasury funding requests for a short window and pays
+// them out together in a single multi-output transaction.
+package faucet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/savaki/zapctx"
+)
+
+const (
+	// DefaultWindow is the default time requests are collected before being paid out
+	DefaultWindow = time.Second
+
+	// DefaultMaxBatch is the default maximum number of payouts in a single tx
+	DefaultMaxBatch = 100
+)
+
+// Funder pays out from the treasury
+type Funder interface {
+	FundWallets(ctx context.Context, payouts []cardano.Payout, opts ...cardano.BuildOption) (cardano.Tx, error)
+	ValidatePayout(address, quantity string) (string, error)
+}
+
+// Payout describes a completed faucet payout
+type Payout struct {
+	Address  string
+	Quantity string
+	TxID     string
+	Index    int32      // Index of the payout output within the tx
+	Tx       cardano.Tx // Tx holds the shared payout tx
+}
+
+// Option configures the Faucet
+type Option func(*Faucet)
+
+// Window sets the time requests are collected before being paid out
+func Window(d time.Duration) Option {
+	return func(f *Faucet) {
+		if d > 0 {
+			f.window = d
+		}
+	}
+}
+
+// MaxBatch sets the maximum number of payouts in a single tx
+func MaxBatch(n int) Option {
+	return func(f *Faucet) {
+		if n > 0 {
+			f.maxBatch = n
+		}
+	}
+}
+
+type result struct {
+	payout Payout
+	err    error
+}
+
+type request struct {
+	payout cardano.Payout
+	done   chan result
+}
+
+// Faucet batches funding requests
+type Faucet struct {
+	funder   Funder
+	window   time.Duration
+	maxBatch int
+
+	mutex   sync.Mutex
+	pending []request
+	timer   *time.Timer
+}
+
+// New returns a new Faucet paying out via funder
+func New(funder Funder, opts ...Option) *Faucet {
+	f := &Faucet{
+		funder:   funder,
+		window:   DefaultWindow,
+		maxBatch: DefaultMaxBatch,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Fund queues quantity lovelace to be paid to address and blocks until the
+// batch containing the request has been submitted.  If ctx is cancelled
+// before the batch is taken, the request is withdrawn and ctx.Err() returned;
+// afterwards Fund waits for the outcome of the payout
+func (f *Faucet) Fund(ctx context.Context, address, quantity string) (Payout, error) {
+	address, err := f.funder.ValidatePayout(address, quantity)
+	if err != nil {
+		return Payout{}, fmt.Errorf("unable to fund wallet: %w", err)
+	}
+
+	req := request{
+		payout: cardano.Payout{Address: address, Quantity: quantity},
+		done:   make(chan result, 1),
+	}
+
+	// payouts are submitted independently of any single caller's context
+	flushCtx := zapctx.NewContext(context.Background(), zapctx.FromContext(ctx))
+
+	f.mutex.Lock()
+	f.pending = append(f.pending, req)
+	switch n := len(f.pending); {
+	case n >= f.maxBatch:
+		if f.timer != nil {
+			f.timer.Stop()
+			f.timer = nil
+		}
+		batch := f.pending
+		f.pending = nil
+		go f.payout(flushCtx, batch)
+	case n == 1:
+		f.timer = time.AfterFunc(f.window, func() { f.flush(flushCtx) })
+	}
+	f.mutex.Unlock()
+
+	select {
+	case <-ctx.Done():
+		// a request still pending is never paid out; one already taken by a
+		// batch may be, so its outcome is returned rather than ctx.Err()
+		if f.remove(req) {
+			return Payout{}, ctx.Err()
+		}
+	case res := <-req.done:
+		return res.payout, res.err
+	}
+
+	res := <-req.done
+	return res.payout, res.err
+}
+
+// remove drops req from the pending requests, returning false if it has
+// already been taken by a batch
+func (f *Faucet) remove(req request) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for i, item := range f.pending {
+		if item.done == req.done {
+			f.pending = append(f.pending[:i], f.pending[i+1:]...)
+			if len(f.pending) == 0 && f.timer != nil {
+				f.timer.Stop()
+				f.timer = nil
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// flush pays out all pending requests
+func (f *Faucet) flush(ctx context.Context) {
+	f.mutex.Lock()
+	batch := f.pending
+	f.pending = nil
+	f.timer = nil
+	f.mutex.Unlock()
+
+	f.payout(ctx, batch)
+}
+
+// payout pays out the batch in a single tx
+func (f *Faucet) payout(ctx context.Context, batch []request) {
+	if len(batch) == 0 {
+		return
+	}
+
+	payouts := make([]cardano.Payout, 0, len(batch))
+	for _, req := range batch {
+		payouts = append(payouts, req.payout)
+	}
+
+	tx, err := f.funder.FundWallets(ctx, payouts)
+	for i, req := range batch {
+		if err != nil {
+			req.done <- result{err: fmt.Errorf("unable to fund wallet: %w", err)}
+			continue
+		}
+		req.done <- result{
+			payout: Payout{
+				Address:  req.payout.Address,
+				Quantity: req.payout.Quantity,
+				TxID:     tx.ID,
+				Index:    int32(i + 1), // output 0 holds the treasury change
+				Tx:       tx,
+			},
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package faucet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/tj/assert"
+)
+
+type Mock struct {
+	mutex   sync.Mutex
+	batches [][]cardano.Payout
+	err     error
+	wait    chan struct{} // wait optionally blocks FundWallets until closed
+}
+
+func (m *Mock) FundWallets(_ context.Context, payouts []cardano.Payout, _ ...cardano.BuildOption) (cardano.Tx, error) {
+	if m.wait != nil {
+		<-m.wait
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return cardano.Tx{}, m.err
+	}
+	m.batches = append(m.batches, payouts)
+	return cardano.Tx{ID: fmt.Sprintf("tx-%v", len(m.batches))}, nil
+}
+
+func (m *Mock) ValidatePayout(address, quantity string) (string, error) {
+	if address == "treasury" {
+		return "", fmt.Errorf("illegall attempt to fund treasury addr")
+	}
+	return address, nil
+}
+
+func fundAll(t *testing.T, f *Faucet, n int) []Payout {
+	var (
+		wg      sync.WaitGroup
+		payouts = make([]Payout, n)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payout, err := f.Fund(context.Background(), fmt.Sprintf("addr-%v", i), "1000000")
+			assert.Nil(t, err)
+			payouts[i] = payout
+		}(i)
+	}
+	wg.Wait()
+	return payouts
+}
+
+func TestFaucet_Fund(t *testing.T) {
+	t.Run("batched", func(t *testing.T) {
+		mock := &Mock{}
+		f := New(mock, Window(50*time.Millisecond))
+
+		payouts := fundAll(t, f, 20)
+		assert.Len(t, mock.batches, 1)
+		assert.Len(t, mock.batches[0], 20)
+
+		indexes := map[int32]struct{}{}
+		for _, payout := range payouts {
+			assert.Equal(t, "tx-1", payout.TxID)
+			assert.Equal(t, mock.batches[0][payout.Index-1].Address, payout.Address)
+			indexes[payout.Index] = struct{}{}
+		}
+		assert.Len(t, indexes, 20)
+	})
+
+	t.Run("max batch", func(t *testing.T) {
+		mock := &Mock{}
+		f := New(mock, Window(50*time.Millisecond), MaxBatch(5))
+
+		fundAll(t, f, 20)
+		assert.Len(t, mock.batches, 4)
+	})
+
+	t.Run("invalid payout rejected before queueing", func(t *testing.T) {
+		mock := &Mock{}
+		f := New(mock, Window(10*time.Millisecond))
+
+		_, err := f.Fund(context.Background(), "treasury", "1000000")
+		assert.NotNil(t, err)
+		assert.Len(t, mock.batches, 0)
+	})
+
+	t.Run("failed batch", func(t *testing.T) {
+		mock := &Mock{err: fmt.Errorf("boom")}
+		f := New(mock, Window(10*time.Millisecond))
+
+		_, err := f.Fund(context.Background(), "addr", "1000000")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestFaucet_FundCancelled(t *testing.T) {
+	t.Run("pending", func(t *testing.T) {
+		mock := &Mock{}
+		f := New(mock, Window(50*time.Millisecond))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := f.Fund(ctx, "addr", "1000000")
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		time.Sleep(100 * time.Millisecond)
+		mock.mutex.Lock()
+		defer mock.mutex.Unlock()
+		assert.Len(t, mock.batches, 0)
+	})
+
+	t.Run("taken by a batch", func(t *testing.T) {
+		mock := &Mock{wait: make(chan struct{})}
+		f := New(mock, Window(10*time.Millisecond))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		time.AfterFunc(100*time.Millisecond, func() { close(mock.wait) })
+		payout, err := f.Fund(ctx, "addr", "1000000")
+		assert.Nil(t, err)
+		assert.Equal(t, "tx-1", payout.TxID)
+	})
+}
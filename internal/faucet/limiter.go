@@ -0,0 +1,333 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	fileQuotas = "quotas.json" // fileQuotas holds the persisted quota state
+
+	// DefaultQuotaWindow is the default rolling window for per-address and per-ip quotas
+	DefaultQuotaWindow = 24 * time.Hour
+)
+
+// Limits configures the faucet quotas; zero values are unlimited
+type Limits struct {
+	Window      time.Duration // Window is the rolling window for PerAddress and PerIP
+	PerAddress  *big.Int      // PerAddress is the lovelace an address may receive per Window
+	PerIP       *big.Int      // PerIP is the lovelace a client ip may request per Window
+	DailyBudget *big.Int      // DailyBudget is the lovelace paid out across all clients per UTC day
+
+	TokensPerRequest *big.Int // TokensPerRequest is the quantity of any one asset a single request may receive
+	TokenQuota       *big.Int // TokenQuota is the quantity of tokens an address or client ip may receive per Window
+}
+
+// LimitError is returned when a funding request would exceed a quota
+type LimitError struct {
+	Limit      string // Limit identifies the quota exceeded; address, ip, daily, address-tokens, or ip-tokens
+	RetryAfter time.Duration
+	Exceeds    bool // Exceeds is true when the request alone exceeds the quota and will never be allowed
+}
+
+func (e *LimitError) Error() string {
+	if e.Exceeds {
+		return fmt.Sprintf("faucet %v quota exceeded; request exceeds the quota", e.Limit)
+	}
+	return fmt.Sprintf("faucet %v quota exceeded; retry after %v", e.Limit, e.RetryAfter.Round(time.Second))
+}
+
+// Extensions exposes the limit details to graphql clients; retryAfter is
+// omitted when the request will never be allowed
+func (e *LimitError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{
+		"code":  "FAUCET_QUOTA_EXCEEDED",
+		"limit": e.Limit,
+	}
+	if !e.Exceeds {
+		extensions["retryAfter"] = int64(e.RetryAfter.Round(time.Second) / time.Second)
+	}
+	return extensions
+}
+
+// newLimitError returns the error for a request of q exceeding limit.  The
+// request may be retried once the usage counted against the quota expires.
+func newLimitError(name string, limit, q *big.Int, expires, now time.Time) *LimitError {
+	if q.Cmp(limit) > 0 {
+		return &LimitError{Limit: name, Exceeds: true}
+	}
+	retryAfter := expires.Sub(now)
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	return &LimitError{Limit: name, RetryAfter: retryAfter}
+}
+
+// grant records a single payout against the quotas
+type grant struct {
+	Time     time.Time `json:"time"`
+	Address  string    `json:"address,omitempty"`
+	IP       string    `json:"ip,omitempty"`
+	Quantity string    `json:"quantity"`
+	Tokens   string    `json:"tokens,omitempty"` // Tokens holds the quantity of tokens paid, summed across assets
+}
+
+// Limiter enforces faucet quotas.  Grants are persisted so quotas survive restarts.
+type Limiter struct {
+	filename string
+	limits   Limits
+
+	mutex  sync.Mutex
+	grants []grant
+}
+
+// NewLimiter returns a Limiter persisting its state under dir
+func NewLimiter(dir string, limits Limits) (*Limiter, error) {
+	if limits.Window <= 0 {
+		limits.Window = DefaultQuotaWindow
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create limiter: %w", err)
+	}
+
+	l := &Limiter{
+		filename: filepath.Join(dir, fileQuotas),
+		limits:   limits,
+	}
+
+	data, err := ioutil.ReadFile(l.filename)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to create limiter: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &l.grants); err != nil {
+			return nil, fmt.Errorf("failed to create limiter: unable to decode %v: %w", fileQuotas, err)
+		}
+	}
+
+	return l, nil
+}
+
+// Allow records quantity lovelace paid to address on behalf of ip, returning
+// a *LimitError if any quota would be exceeded.  The returned cancel func
+// removes the grant should the payout fail.  A nil Limiter allows everything.
+func (l *Limiter) Allow(address, ip, quantity string, now time.Time) (cancel func(), err error) {
+	return l.allow(address, ip, quantity, big.NewInt(0), now)
+}
+
+// AllowTokens records quantity lovelace paid to address along with the tokens,
+// the quantity of each asset, returning an error if any asset exceeds the
+// per-request limit or any quota would be exceeded.  The returned cancel func
+// removes the grant should the payout fail.  A nil Limiter allows everything.
+func (l *Limiter) AllowTokens(address, ip, quantity string, tokens []string, now time.Time) (cancel func(), err error) {
+	total := big.NewInt(0)
+	for _, token := range tokens {
+		v, ok := big.NewInt(0).SetString(token, 10)
+		if !ok || v.Sign() < 0 {
+			return func() {}, fmt.Errorf("invalid token quantity, %v", token)
+		}
+		if l != nil && exceeds(l.limits.TokensPerRequest, big.NewInt(0), v) {
+			return func() {}, fmt.Errorf("faucet token quantity, %v, exceeds the per request maximum of %v", v, l.limits.TokensPerRequest)
+		}
+		total.Add(total, v)
+	}
+	return l.allow(address, ip, quantity, total, now)
+}
+
+func (l *Limiter) allow(address, ip, quantity string, tokens *big.Int, now time.Time) (cancel func(), err error) {
+	cancel = func() {}
+	if l == nil {
+		return cancel, nil
+	}
+
+	q, ok := big.NewInt(0).SetString(quantity, 10)
+	if !ok {
+		return cancel, fmt.Errorf("invalid quantity, %v", quantity)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.prune(now)
+
+	var (
+		windowStart     = now.Add(-l.limits.Window)
+		dayStart        = now.UTC().Truncate(24 * time.Hour)
+		byAddress       = newUsage()
+		byIP            = newUsage()
+		daily           = newUsage()
+		tokensByAddress = newUsage()
+		tokensByIP      = newUsage()
+	)
+	for _, g := range l.grants {
+		v, _ := big.NewInt(0).SetString(g.Quantity, 10)
+		if v == nil {
+			continue
+		}
+		if g.Time.After(windowStart) {
+			if address != "" && g.Address == address {
+				byAddress.add(g.Time, v)
+			}
+			if ip != "" && g.IP == ip {
+				byIP.add(g.Time, v)
+			}
+			if t, _ := big.NewInt(0).SetString(g.Tokens, 10); t != nil && t.Sign() > 0 {
+				if address != "" && g.Address == address {
+					tokensByAddress.add(g.Time, t)
+				}
+				if ip != "" && g.IP == ip {
+					tokensByIP.add(g.Time, t)
+				}
+			}
+		}
+		if !g.Time.Before(dayStart) {
+			daily.add(g.Time, v)
+		}
+	}
+
+	if exceeds(l.limits.PerAddress, byAddress.total, q) {
+		return cancel, newLimitError("address", l.limits.PerAddress, q, byAddress.first.Add(l.limits.Window), now)
+	}
+	if exceeds(l.limits.PerIP, byIP.total, q) {
+		return cancel, newLimitError("ip", l.limits.PerIP, q, byIP.first.Add(l.limits.Window), now)
+	}
+	if exceeds(l.limits.DailyBudget, daily.total, q) {
+		return cancel, newLimitError("daily", l.limits.DailyBudget, q, dayStart.Add(24*time.Hour), now)
+	}
+	if tokens.Sign() > 0 {
+		if exceeds(l.limits.TokenQuota, tokensByAddress.total, tokens) {
+			return cancel, newLimitError("address-tokens", l.limits.TokenQuota, tokens, tokensByAddress.first.Add(l.limits.Window), now)
+		}
+		if exceeds(l.limits.TokenQuota, tokensByIP.total, tokens) {
+			return cancel, newLimitError("ip-tokens", l.limits.TokenQuota, tokens, tokensByIP.first.Add(l.limits.Window), now)
+		}
+	}
+
+	g := grant{
+		Time:     now,
+		Address:  address,
+		IP:       ip,
+		Quantity: quantity,
+	}
+	if tokens.Sign() > 0 {
+		g.Tokens = tokens.String()
+	}
+	l.grants = append(l.grants, g)
+	if err := l.save(); err != nil {
+		return cancel, err
+	}
+
+	cancel = func() {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		for i, item := range l.grants {
+			if item == g {
+				l.grants = append(l.grants[:i], l.grants[i+1:]...)
+				_ = l.save()
+				return
+			}
+		}
+	}
+	return cancel, nil
+}
+
+// prune discards grants no longer relevant to any quota
+func (l *Limiter) prune(now time.Time) {
+	cutoff := now.Add(-l.limits.Window)
+	if dayStart := now.UTC().Truncate(24 * time.Hour); dayStart.Before(cutoff) {
+		cutoff = dayStart
+	}
+
+	grants := l.grants[:0]
+	for _, g := range l.grants {
+		if g.Time.After(cutoff) || g.Time.Equal(cutoff) {
+			grants = append(grants, g)
+		}
+	}
+	l.grants = grants
+}
+
+func (l *Limiter) save() error {
+	data, err := json.Marshal(l.grants)
+	if err != nil {
+		return fmt.Errorf("unable to save quotas: %w", err)
+	}
+
+	tmp := l.filename + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("unable to save quotas: %w", err)
+	}
+	if err := os.Rename(tmp, l.filename); err != nil {
+		return fmt.Errorf("unable to save quotas: %w", err)
+	}
+	return nil
+}
+
+type usage struct {
+	first time.Time
+	total *big.Int
+}
+
+func newUsage() *usage {
+	return &usage{total: big.NewInt(0)}
+}
+
+func (u *usage) add(t time.Time, v *big.Int) {
+	if u.first.IsZero() || t.Before(u.first) {
+		u.first = t
+	}
+	u.total.Add(u.total, v)
+}
+
+// exceeds returns true if limit is set and used + q is greater than limit
+func exceeds(limit, used, q *big.Int) bool {
+	if limit == nil || limit.Sign() <= 0 {
+		return false
+	}
+	return big.NewInt(0).Add(used, q).Cmp(limit) > 0
+}
+
+type ctxKey int
+
+const ctxClientIP ctxKey = iota
+
+// WithClientIP returns a context holding the ip of the client making the request
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ctxClientIP, ip)
+}
+
+// ClientIP returns the client ip stored in the context, if any
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(ctxClientIP).(string)
+	return ip
+}
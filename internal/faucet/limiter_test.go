@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package faucet
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "limiter")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		now    = time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+		limits = Limits{
+			Window:      time.Hour,
+			PerAddress:  big.NewInt(100),
+			PerIP:       big.NewInt(150),
+			DailyBudget: big.NewInt(250),
+		}
+	)
+
+	limiter, err := NewLimiter(dir, limits)
+	assert.Nil(t, err)
+
+	_, err = limiter.Allow("alice", "1.2.3.4", "100", now)
+	assert.Nil(t, err)
+
+	t.Run("per address", func(t *testing.T) {
+		_, err := limiter.Allow("alice", "5.6.7.8", "1", now)
+		assert.NotNil(t, err)
+		limitErr, ok := err.(*LimitError)
+		assert.True(t, ok)
+		assert.Equal(t, "address", limitErr.Limit)
+		assert.Equal(t, time.Hour, limitErr.RetryAfter)
+	})
+
+	t.Run("per ip", func(t *testing.T) {
+		_, err := limiter.Allow("bob", "1.2.3.4", "60", now)
+		assert.NotNil(t, err)
+		assert.Equal(t, "ip", err.(*LimitError).Limit)
+	})
+
+	t.Run("cancel releases quota", func(t *testing.T) {
+		cancel, err := limiter.Allow("bob", "1.2.3.4", "50", now)
+		assert.Nil(t, err)
+		cancel()
+
+		_, err = limiter.Allow("bob", "1.2.3.4", "50", now)
+		assert.Nil(t, err)
+	})
+
+	t.Run("persisted", func(t *testing.T) {
+		limiter, err := NewLimiter(dir, limits)
+		assert.Nil(t, err)
+
+		_, err = limiter.Allow("alice", "", "1", now)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("window rolls", func(t *testing.T) {
+		later := now.Add(time.Hour + time.Minute)
+		_, err := limiter.Allow("alice", "1.2.3.4", "100", later)
+		assert.Nil(t, err)
+	})
+
+	t.Run("daily budget", func(t *testing.T) {
+		later := now.Add(2 * time.Hour)
+		_, err := limiter.Allow("carol", "9.9.9.9", "100", later)
+		assert.NotNil(t, err)
+		assert.Equal(t, "daily", err.(*LimitError).Limit)
+
+		tomorrow := now.Add(24 * time.Hour)
+		_, err = limiter.Allow("carol", "9.9.9.9", "100", tomorrow)
+		assert.Nil(t, err)
+	})
+
+	t.Run("request exceeds quota", func(t *testing.T) {
+		_, err := limiter.Allow("dave", "8.8.8.8", "101", now)
+		assert.NotNil(t, err)
+		limitErr := err.(*LimitError)
+		assert.Equal(t, "address", limitErr.Limit)
+		assert.True(t, limitErr.Exceeds)
+		assert.Equal(t, time.Duration(0), limitErr.RetryAfter)
+
+		extensions := limitErr.Extensions()
+		assert.Equal(t, "FAUCET_QUOTA_EXCEEDED", extensions["code"])
+		_, ok := extensions["retryAfter"]
+		assert.False(t, ok)
+	})
+
+	t.Run("nil limiter", func(t *testing.T) {
+		var limiter *Limiter
+		_, err := limiter.Allow("alice", "1.2.3.4", "1000000", now)
+		assert.Nil(t, err)
+	})
+}
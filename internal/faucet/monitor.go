@@ -0,0 +1,154 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package faucet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/savaki/zapctx"
+	"go.uber.org/zap"
+)
+
+// DefaultCheckInterval is the default time between treasury checks
+const DefaultCheckInterval = time.Minute
+
+// Treasury reports the treasury status
+type Treasury interface {
+	TreasuryStatus() (cardano.TreasuryStatus, error)
+}
+
+// Health describes the treasury as of the last check
+type Health struct {
+	Status   cardano.TreasuryStatus
+	LowWater string    // LowWater holds the low-water threshold, if any
+	Low      bool      // Low is true when the treasury holds less than LowWater or nothing fundable
+	Checked  time.Time // Checked holds the time of the last check; zero until the first check
+	Err      error     // Err holds the error of the last check, if any
+}
+
+// Healthy returns true unless the last check failed or found the treasury low
+func (h Health) Healthy() bool {
+	return h.Err == nil && !h.Low
+}
+
+// Monitor periodically checks the treasury balance and warns when it falls
+// below the low-water threshold so the faucet can be refilled before it runs dry
+type Monitor struct {
+	treasury Treasury
+	lowWater *big.Int
+
+	mutex  sync.Mutex
+	health Health
+}
+
+// NewMonitor returns a Monitor of treasury.  lowWater optionally holds the
+// lovelace below which the treasury is considered low.
+func NewMonitor(treasury Treasury, lowWater string) (*Monitor, error) {
+	m := &Monitor{treasury: treasury}
+	if lowWater != "" {
+		v, ok := big.NewInt(0).SetString(lowWater, 10)
+		if !ok || v.Sign() < 0 {
+			return nil, fmt.Errorf("invalid treasury low-water, %v: expected lovelace", lowWater)
+		}
+		m.lowWater = v
+		m.health.LowWater = v.String()
+	}
+	return m, nil
+}
+
+// Health returns the treasury health as of the last check
+func (m *Monitor) Health() Health {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.health
+}
+
+// Check queries the treasury status, logging a warning when the treasury
+// becomes low and again once it has recovered
+func (m *Monitor) Check(ctx context.Context) (Health, error) {
+	status, err := m.treasury.TreasuryStatus()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	prev := m.health
+	m.health.Checked = time.Now()
+	m.health.Err = err
+	if err != nil {
+		zapctx.FromContext(ctx).Warn("unable to check treasury", zap.Error(err))
+		return m.health, err
+	}
+	m.health.Status = status
+	m.health.Low = m.low(status)
+
+	logger := zapctx.FromContext(ctx).With(
+		zap.String("treasury", status.Address),
+		zap.String("lovelace", status.Lovelace),
+		zap.Int("fundable", status.Fundable),
+	)
+	switch {
+	case m.health.Low && !prev.Low:
+		logger.Warn("treasury is low; refill it before the faucet stops working", zap.String("lowWater", m.health.LowWater))
+	case !m.health.Low && prev.Low:
+		logger.Info("treasury recovered", zap.String("lowWater", m.health.LowWater))
+	}
+	return m.health, nil
+}
+
+// Watch checks the treasury every interval until the context is cancelled
+func (m *Monitor) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// low returns true when the treasury holds less than the low-water threshold
+// or has no utxos left to fund payouts from
+func (m *Monitor) low(status cardano.TreasuryStatus) bool {
+	if status.Fundable == 0 && status.InFlight == 0 {
+		return true
+	}
+	if m.lowWater == nil {
+		return false
+	}
+	lovelace, ok := big.NewInt(0).SetString(status.Lovelace, 10)
+	return ok && lovelace.Cmp(m.lowWater) < 0
+}
@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package faucet
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/tj/assert"
+)
+
+type fakeTreasury struct {
+	status cardano.TreasuryStatus
+	err    error
+}
+
+func (f *fakeTreasury) TreasuryStatus() (cardano.TreasuryStatus, error) {
+	return f.status, f.err
+}
+
+func TestMonitor_Check(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		treasury = &fakeTreasury{status: cardano.TreasuryStatus{Lovelace: "5000", Fundable: 2}}
+	)
+
+	monitor, err := NewMonitor(treasury, "1000")
+	assert.Nil(t, err)
+	assert.True(t, monitor.Health().Healthy())
+
+	health, err := monitor.Check(ctx)
+	assert.Nil(t, err)
+	assert.False(t, health.Low)
+	assert.Equal(t, "1000", health.LowWater)
+
+	treasury.status = cardano.TreasuryStatus{Lovelace: "999", Fundable: 1}
+	health, err = monitor.Check(ctx)
+	assert.Nil(t, err)
+	assert.True(t, health.Low)
+	assert.False(t, monitor.Health().Healthy())
+
+	t.Run("nothing fundable", func(t *testing.T) {
+		treasury.status = cardano.TreasuryStatus{Lovelace: "5000", Fundable: 0}
+		health, err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.True(t, health.Low)
+
+		treasury.status.InFlight = 1
+		health, err = monitor.Check(ctx)
+		assert.Nil(t, err)
+		assert.False(t, health.Low)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		treasury.err = fmt.Errorf("boom")
+		_, err := monitor.Check(ctx)
+		assert.NotNil(t, err)
+		assert.False(t, monitor.Health().Healthy())
+	})
+}
+
+func TestNewMonitor(t *testing.T) {
+	monitor, err := NewMonitor(&fakeTreasury{}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "", monitor.Health().LowWater)
+
+	_, err = NewMonitor(&fakeTreasury{}, "lots")
+	assert.NotNil(t, err)
+}
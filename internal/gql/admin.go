@@ -0,0 +1,52 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+type authorizationKey struct{}
+
+// withAuthorization returns a context holding the Authorization header of the request
+func withAuthorization(ctx context.Context, authorization string) context.Context {
+	return context.WithValue(ctx, authorizationKey{}, authorization)
+}
+
+// requireAdmin returns an error unless the request bears the admin token,
+// Authorization: Bearer {token}
+func (r *Resolver) requireAdmin(ctx context.Context) error {
+	if r.config.AdminToken == "" {
+		return fmt.Errorf("admin mutations are disabled; set ADMIN_TOKEN to enable")
+	}
+
+	authorization, _ := ctx.Value(authorizationKey{}).(string)
+	token := strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+	if subtle.ConstantTimeCompare([]byte(token), []byte(r.config.AdminToken)) != 1 {
+		return fmt.Errorf("unauthorized: admin token required")
+	}
+	return nil
+}
@@ -24,27 +24,28 @@ package gql
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"math/big"
-	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/faucet"
 	"github.com/savaki/zapctx"
 	"go.uber.org/zap"
 )
 
 type BuildMintTxInput struct {
-	AssetName string
-	Fee       string
-	Quantity  string
-	TxIn      cardano.Utxo
-	Wallet    string
+	Assets     []MintAsset
+	Fee        string
+	Policy     cardano.Policy
+	Recipients []MintRecipient
+	TxIn       cardano.Utxos
+	Wallet     string
 }
 
+// buildMintTx builds a tx that mints the assets, distributes them to the recipients,
+// and returns the remaining tokens and ADA to the wallet as the first output
 func (r *Resolver) buildMintTx(ctx context.Context, input BuildMintTxInput) (raw []byte, err error) {
 	defer func(begin time.Time) {
 		zapctx.FromContext(ctx).Info("build mint-tx",
@@ -52,87 +53,230 @@ func (r *Resolver) buildMintTx(ctx context.Context, input BuildMintTxInput) (raw
 			zap.Error(err),
 		)
 	}(time.Now())
-	keyHash, err := r.config.CLI.KeyHash(ctx, input.Wallet)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build mint tx: %w", err)
-	}
 
-	script, scriptCleanup, err := buildScript(r.config.CLI.DataDir(), keyHash)
+	address, err := r.config.CLI.NormalizeAddress(input.Wallet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build mint tx: %w", err)
 	}
-	defer scriptCleanup()
 
-	policyID, err := r.config.CLI.PolicyID(ctx, script)
+	change, err := input.TxIn.Value()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build mint tx: %w", err)
 	}
 
-	address, err := r.config.CLI.NormalizeAddress(input.Wallet)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build mint tx: %w", err)
+	fee, ok := big.NewInt(0).SetString(input.Fee, 10)
+	if !ok {
+		return nil, fmt.Errorf("mint failed to parse fee, %v", input.Fee)
 	}
+	change.Lovelace.Sub(change.Lovelace, fee)
 
-	value, ok := big.NewInt(0).SetString(input.TxIn.Value, 10)
-	if !ok {
-		return nil, fmt.Errorf("mint failed to parse txIn amount, %v", input.TxIn.Value)
+	var (
+		minted []string
+		nfts   = map[string]cardano.NFTMetadata{}
+	)
+	for _, asset := range input.Assets {
+		quantity, _ := big.NewInt(0).SetString(asset.Quantity, 10)
+		assetNameHex, err := cardano.ParseAssetName(asset.AssetName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build mint tx: %w", err)
+		}
+		assetID := input.Policy.ID + "." + assetNameHex
+		change.AddToken(assetID, quantity)
+		minted = append(minted, fmt.Sprintf("%v %v", quantity, assetID))
+		if asset.Nft != nil {
+			name := cardano.Asset{PolicyId: input.Policy.ID, AssetName: assetNameHex}.DisplayName()
+			nfts[name] = asset.Nft.toMetadata()
+		}
 	}
 
-	fee, ok := big.NewInt(0).SetString(input.Fee, 10)
-	if !ok {
-		return nil, fmt.Errorf("mint failed to parse fee, %v", fee)
+	var outputs []cardano.BuildOption
+	for _, recipient := range input.Recipients {
+		lovelace, _ := big.NewInt(0).SetString(StringValue(recipient.Lovelace), 10)
+		change.Lovelace.Sub(change.Lovelace, lovelace)
+
+		value := cardano.NewValue()
+		if recipient.Assets != nil {
+			for _, asset := range *recipient.Assets {
+				quantity, ok := big.NewInt(0).SetString(asset.Quantity, 10)
+				if !ok || quantity.Sign() <= 0 {
+					return nil, fmt.Errorf("failed to build mint tx: invalid quantity for %v, %v", asset.AssetName, asset.Quantity)
+				}
+				assetNameHex, err := cardano.ParseAssetName(asset.AssetName)
+				if err != nil {
+					return nil, fmt.Errorf("failed to build mint tx: %w", err)
+				}
+				assetID := input.Policy.ID + "." + assetNameHex
+				value.AddToken(assetID, quantity)
+				change.AddToken(assetID, big.NewInt(0).Neg(quantity))
+			}
+		}
+		outputs = append(outputs, cardano.TxOut(recipient.Address, lovelace.String(), value.TokenArgs()...))
 	}
 
-	remain := big.NewInt(0).Sub(value, fee)
+	if change.Lovelace.Sign() < 0 {
+		return nil, fmt.Errorf("failed to build mint tx: insufficient lovelace in inputs")
+	}
+	for assetID, quantity := range change.Tokens {
+		if quantity.Sign() < 0 {
+			return nil, fmt.Errorf("failed to build mint tx: recipients were sent more %v than minted", assetID)
+		}
+	}
 
-	mintedTokens := fmt.Sprintf("%v %v.%v", input.Quantity, policyID, input.AssetName)
-	return r.config.CLI.Build(
+	opts := []cardano.BuildOption{
 		cardano.Fee(input.Fee),
-		cardano.TxIn(input.TxIn.Address, input.TxIn.Index),
-		cardano.TxOut(address, remain.String(), mintedTokens),
-		cardano.Mint(mintedTokens),
-		cardano.MintScriptFile(fmt.Sprintf(script)),
+	}
+	for _, utxo := range input.TxIn {
+		opts = append(opts, cardano.TxIn(utxo.Address, utxo.Index))
+	}
+	opts = append(opts, cardano.TxOut(address, change.Lovelace.String(), change.TokenArgs()...))
+	opts = append(opts, outputs...)
+	opts = append(opts,
+		cardano.Mint(strings.Join(minted, "+")),
+		cardano.MintScriptFile(input.Policy.ScriptFile),
+		cardano.InvalidHereafter(input.Policy.Before),
 	)
+	if len(nfts) > 0 {
+		metadata, err := cardano.NFTMetadataJSON(input.Policy.ID, nfts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build mint tx: %w", err)
+		}
+		opts = append(opts, cardano.MetadataJSON(metadata, cardano.MetadataNoSchema))
+	}
+	return r.config.CLI.Build(opts...)
+}
+
+// mintingPolicy returns the stored policy identified by policyID or, if no
+// policyID was provided, the wallet's policy creating it if necessary
+func (r *Resolver) mintingPolicy(ctx context.Context, wallet string, policyID *string, before *int32) (cardano.Policy, error) {
+	if id := StringValue(policyID); id != "" {
+		return r.findPolicy(wallet, id)
+	}
+
+	var slot int32
+	if before != nil {
+		slot = *before
+	}
+	return r.config.CLI.CreatePolicy(ctx, wallet, slot)
 }
 
-func (r *Resolver) fundWallet(ctx context.Context, address, quantity string) (err error) {
+// findPolicy returns the existing policy, policyID, owned by wallet
+func (r *Resolver) findPolicy(wallet, policyID string) (cardano.Policy, error) {
+	policy, err := r.config.CLI.FindPolicy(policyID)
+	if err != nil {
+		return cardano.Policy{}, err
+	}
+	if policy.Wallet != wallet {
+		return cardano.Policy{}, fmt.Errorf("policy, %v, is not owned by wallet, %v", policyID, wallet)
+	}
+	return policy, nil
+}
+
+func (r *Resolver) fundWallet(ctx context.Context, address, quantity string, opts ...cardano.BuildOption) (tx cardano.Tx, err error) {
 	defer func(begin time.Time) {
 		zapctx.FromContext(ctx).Info("funded wallet",
 			zap.String("address", address),
 			zap.String("quantity", quantity),
+			zap.String("tx", tx.ID),
 			zap.Duration("elapsed", time.Now().Sub(begin).Round(time.Millisecond)),
 			zap.Error(err),
 		)
 	}(time.Now())
 
-	if _, err := r.config.CLI.FundWallet(ctx, address, quantity); err != nil {
-		return err
+	if quantity == "" || quantity == "0" {
+		return cardano.Tx{}, nil
+	}
+
+	// per request build options e.g. metadata can not be applied to a batch
+	if r.config.Faucet != nil && len(opts) == 0 {
+		payout, err := r.config.Faucet.Fund(ctx, address, quantity)
+		if err != nil {
+			return cardano.Tx{}, err
+		}
+		return payout.Tx, nil
+	}
+
+	return r.config.CLI.FundWallet(ctx, address, quantity, opts...)
+}
+
+// awaitTx waits, up to the default timeout, for outputs of the tx to the
+// address to be confirmed
+func (r *Resolver) awaitTx(ctx context.Context, txID, address string) error {
+	if txID == "" {
+		return nil
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, cardano.DefaultAwaitTimeout)
+	defer cancel()
+
+	if _, err := r.config.CLI.AwaitTx(ctx, txID, address); err != nil {
+		return err
+	}
 	return nil
 }
 
-func buildScript(dir, keyHash string) (string, func(), error) {
-	f, err := ioutil.TempFile(filepath.Join(dir, "/tmp"), "script")
+// allowFunding applies the faucet quotas to a funding request made on behalf
+// of the client.  The returned cancel func must be called if the payout fails.
+func (r *Resolver) allowFunding(ctx context.Context, address, quantity string) (cancel func(), err error) {
+	if r.config.Limiter == nil || quantity == "" || quantity == "0" {
+		return func() {}, nil
+	}
+
+	if address != "" {
+		address, err = r.config.CLI.NormalizeAddress(address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r.config.Limiter.Allow(address, faucet.ClientIP(ctx), quantity, time.Now())
+}
+
+// allowFundingAssets applies the faucet quotas to a request for native tokens
+// paid along with quantity lovelace.  The returned cancel func must be called
+// if the payout fails.
+func (r *Resolver) allowFundingAssets(ctx context.Context, address, quantity string, assets []cardano.AssetQuantity) (cancel func(), err error) {
+	if r.config.Limiter == nil {
+		return func() {}, nil
+	}
+
+	address, err = r.config.CLI.NormalizeAddress(address)
 	if err != nil {
-		return "", func() {}, fmt.Errorf("failed to create script: %w", err)
+		return nil, err
 	}
-	defer f.Close()
 
-	type Record struct {
-		Type    string `json:"type,omitempty"`
-		KeyHash string `json:"keyHash,omitempty"`
+	var tokens []string
+	for _, asset := range assets {
+		tokens = append(tokens, asset.Quantity)
 	}
-	record := Record{
-		Type:    "sig",
-		KeyHash: keyHash,
+	return r.config.Limiter.AllowTokens(address, faucet.ClientIP(ctx), quantity, tokens, time.Now())
+}
+
+// metadataOptions returns the build options needed to attach the json metadata
+// to a transaction using the graphql MetadataSchema enum
+func metadataOptions(metadata *string, schema string) ([]cardano.BuildOption, error) {
+	if metadata == nil || *metadata == "" {
+		return nil, nil
 	}
-	if err := json.NewEncoder(f).Encode(record); err != nil {
-		f.Close()
-		_ = os.Remove(f.Name())
-		return "", func() {}, fmt.Errorf("failed to write token policy script: %w", err)
+
+	s, err := metadataSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []byte(*metadata)
+	if err := cardano.ValidateMetadataJSON(data, s); err != nil {
+		return nil, err
 	}
+	return []cardano.BuildOption{cardano.MetadataJSON(data, s)}, nil
+}
 
-	filename := f.Name()
-	return filename, func() { _ = os.Remove(filename) }, nil
+func metadataSchema(schema string) (cardano.MetadataSchema, error) {
+	switch schema {
+	case "", "NO_SCHEMA":
+		return cardano.MetadataNoSchema, nil
+	case "DETAILED_SCHEMA":
+		return cardano.MetadataDetailedSchema, nil
+	default:
+		return "", fmt.Errorf("unknown metadata schema, %v", schema)
+	}
 }
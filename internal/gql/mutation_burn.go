@@ -0,0 +1,181 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+)
+
+type BurnArgs struct {
+	AssetName string
+	Policy    string
+	Quantity  string
+	Wallet    string
+}
+
+func (r *Resolver) Burn(ctx context.Context, args BurnArgs) (*TxResultResolver, error) {
+	if args.Policy == "" {
+		return nil, fmt.Errorf("failed to burn tokens: policy required")
+	}
+
+	quantity, ok := big.NewInt(0).SetString(args.Quantity, 10)
+	if !ok || quantity.Sign() <= 0 {
+		return nil, fmt.Errorf("failed to burn tokens: invalid quantity, %v", args.Quantity)
+	}
+
+	assetNameHex, err := cardano.ParseAssetName(args.AssetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to burn tokens: %w", err)
+	}
+
+	policy, err := r.findPolicy(args.Wallet, args.Policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to burn tokens: %w", err)
+	}
+
+	utxos, err := r.config.CLI.Utxos(args.Wallet, cardano.ExcludeScripts(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to burn tokens: %w", err)
+	}
+
+	// select enough utxos holding the asset to cover the quantity burned
+	var (
+		assetID  = policy.ID + "." + assetNameHex
+		selected cardano.Utxos
+		value    = cardano.NewValue()
+	)
+	for _, utxo := range utxos.Filter(cardano.HasToken(assetID)) {
+		if value.Token(assetID).Cmp(quantity) >= 0 {
+			break
+		}
+		if err := value.Add(utxo); err != nil {
+			return nil, fmt.Errorf("failed to burn tokens: %w", err)
+		}
+		selected = append(selected, utxo)
+	}
+	if value.Token(assetID).Cmp(quantity) < 0 {
+		return nil, fmt.Errorf("failed to burn tokens: wallet, %v, holds %v of %v; unable to burn %v", args.Wallet, value.Token(assetID), assetID, quantity)
+	}
+
+	// include an ada only utxo, when available, to cover the fee
+	for _, utxo := range utxos {
+		if len(utxo.Tokens) > 0 {
+			continue
+		}
+		if err := value.Add(utxo); err != nil {
+			return nil, fmt.Errorf("failed to burn tokens: %w", err)
+		}
+		selected = append(selected, utxo)
+		break
+	}
+
+	value.AddToken(assetID, big.NewInt(0).Neg(quantity))
+
+	input := BuildBurnTxInput{
+		AssetID: assetID,
+		Change:  value,
+		Fee:     "0",
+		Policy:  policy,
+		Burned:  quantity,
+		TxIn:    selected,
+		Wallet:  args.Wallet,
+	}
+	raw, err := r.buildBurnTx(input)
+	if err != nil {
+		return nil, err
+	}
+
+	feeArgs := TxFeeArgs{
+		Raw:       base64.StdEncoding.EncodeToString(raw),
+		TxIn:      int32(len(selected)),
+		TxOut:     1,
+		Witnesses: 1,
+	}
+	fee, err := r.TxFee(ctx, feeArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Fee = fee
+	raw, err = r.buildBurnTx(input)
+	if err != nil {
+		return nil, err
+	}
+
+	signArgs := TxSignArgs{
+		Raw:    base64.StdEncoding.EncodeToString(raw),
+		Wallet: args.Wallet,
+	}
+	signed, err := r.TxSign(ctx, signArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	submitArgs := TxSubmitArgs{Signed: signed.body}
+	return r.TxSubmit(cardano.WithTxKind(ctx, cardano.TxKindBurn), submitArgs)
+}
+
+type BuildBurnTxInput struct {
+	AssetID string
+	Burned  *big.Int
+	Change  cardano.Value // Change holds the value of the inputs less the burned tokens
+	Fee     string
+	Policy  cardano.Policy
+	TxIn    cardano.Utxos
+	Wallet  string
+}
+
+func (r *Resolver) buildBurnTx(input BuildBurnTxInput) ([]byte, error) {
+	address, err := r.config.CLI.NormalizeAddress(input.Wallet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build burn tx: %w", err)
+	}
+
+	fee, ok := big.NewInt(0).SetString(input.Fee, 10)
+	if !ok {
+		return nil, fmt.Errorf("burn failed to parse fee, %v", input.Fee)
+	}
+
+	remain := big.NewInt(0).Sub(input.Change.Lovelace, fee)
+	if remain.Sign() < 0 {
+		return nil, fmt.Errorf("failed to build burn tx: insufficient lovelace to cover fee, %v", input.Fee)
+	}
+
+	var opts []cardano.BuildOption
+	for _, utxo := range input.TxIn {
+		opts = append(opts, cardano.TxIn(utxo.Address, utxo.Index))
+	}
+	opts = append(opts,
+		cardano.Fee(input.Fee),
+		cardano.TxOut(address, remain.String(), input.Change.TokenArgs()...),
+		cardano.Mint(fmt.Sprintf("-%v %v", input.Burned, input.AssetID)),
+		cardano.MintScriptFile(input.Policy.ScriptFile),
+		cardano.InvalidHereafter(input.Policy.Before),
+	)
+	return r.config.CLI.Build(opts...)
+}
@@ -0,0 +1,92 @@
+package gql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/tj/assert"
+)
+
+func TestResolver_Burn(t *testing.T) {
+	tokens := []cardano.Token{
+		{Asset: &cardano.Asset{PolicyId: "PolicyID", AssetName: "424c4148"}, Quantity: "100"},
+		{Asset: &cardano.Asset{PolicyId: "PolicyID", AssetName: "4f54484552"}, Quantity: "5"},
+	}
+
+	t.Run("partial burn", func(t *testing.T) {
+		var (
+			ctx  = context.Background()
+			mock = &Mock{
+				fee:      "1000",
+				owner:    "Test",
+				quantity: "10000000",
+				tokens:   tokens,
+			}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		args := BurnArgs{
+			AssetName: "BLAH",
+			Policy:    "PolicyID",
+			Quantity:  "40",
+			Wallet:    "Test",
+		}
+		_, err := resolver.Burn(ctx, args)
+		assert.Nil(t, err)
+		assert.Len(t, mock.options, 2)
+
+		option := mock.options[1]
+		assert.Len(t, option.TxIn, 2)
+		assert.Equal(t, "-40 PolicyID.424c4148", option.Mint)
+		assert.Equal(t, "19999000", option.TxOut[0].Quantity)
+		assert.Equal(t, []string{"60 PolicyID.424c4148", "5 PolicyID.4f54484552"}, option.TxOut[0].Tokens)
+	})
+
+	t.Run("insufficient tokens", func(t *testing.T) {
+		var (
+			ctx  = context.Background()
+			mock = &Mock{
+				owner:    "Test",
+				quantity: "10000000",
+				tokens:   tokens,
+			}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		args := BurnArgs{
+			AssetName: "BLAH",
+			Policy:    "PolicyID",
+			Quantity:  "101",
+			Wallet:    "Test",
+		}
+		_, err := resolver.Burn(ctx, args)
+		assert.NotNil(t, err)
+		assert.Len(t, mock.options, 0)
+	})
+
+	t.Run("policy required", func(t *testing.T) {
+		var (
+			ctx  = context.Background()
+			mock = &Mock{
+				owner:    "Test",
+				quantity: "10000000",
+				tokens:   tokens,
+			}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		args := BurnArgs{
+			AssetName: "BLAH",
+			Quantity:  "40",
+			Wallet:    "Test",
+		}
+		_, err := resolver.Burn(ctx, args)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "policy required")
+		assert.Len(t, mock.options, 0)
+	})
+}
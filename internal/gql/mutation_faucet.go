@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/faucet"
+)
+
+type FaucetArgs struct {
+	Address  string
+	Quantity string
+}
+
+func (r *Resolver) Faucet(ctx context.Context, args FaucetArgs) (*PayoutResolver, error) {
+	if r.config.Faucet == nil {
+		return nil, fmt.Errorf("unable to fund wallet: faucet not configured")
+	}
+
+	cancel, err := r.allowFunding(ctx, args.Address, args.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	payout, err := r.config.Faucet.Fund(ctx, args.Address, args.Quantity)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &PayoutResolver{payout: payout}, nil
+}
+
+type PayoutResolver struct {
+	payout faucet.Payout
+}
+
+func (p *PayoutResolver) Address() string  { return p.payout.Address }
+func (p *PayoutResolver) Index() int32     { return p.payout.Index }
+func (p *PayoutResolver) Quantity() string { return p.payout.Quantity }
+func (p *PayoutResolver) TxId() string     { return p.payout.TxID }
@@ -26,33 +26,205 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"time"
+	"math/big"
 
 	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
 )
 
-type MintArgs struct {
+const (
+	// defaultRecipientLovelace holds the lovelace sent along with minted tokens
+	// to a recipient that did not specify an amount
+	defaultRecipientLovelace = "2000000"
+
+	// mintChangeLovelace holds the lovelace reserved for the change output and fee
+	mintChangeLovelace = 2000000
+
+	// DefaultMaxRecipientLovelace is the default maximum lovelace sent to a single
+	// mint recipient
+	DefaultMaxRecipientLovelace = 100000000
+)
+
+type MintAsset struct {
 	AssetName string
+	Nft       *NftMetadata
 	Quantity  string
-	Wallet    string
 }
 
-func (r *Resolver) Mint(ctx context.Context, args MintArgs) (*Resolver, error) {
+type NftFile struct {
+	MediaType string
+	Name      *string
+	Src       string
+}
+
+type NftMetadata struct {
+	Description *string
+	Files       *[]NftFile
+	Image       string
+	MediaType   *string
+	Name        string
+}
+
+func (n NftMetadata) toMetadata() cardano.NFTMetadata {
+	metadata := cardano.NFTMetadata{
+		Name:        n.Name,
+		Image:       n.Image,
+		MediaType:   StringValue(n.MediaType),
+		Description: StringValue(n.Description),
+	}
+	if n.Files != nil {
+		for _, file := range *n.Files {
+			metadata.Files = append(metadata.Files, cardano.NFTFile{
+				Name:      StringValue(file.Name),
+				MediaType: file.MediaType,
+				Src:       file.Src,
+			})
+		}
+	}
+	return metadata
+}
+
+type MintRecipient struct {
+	Address  string
+	Assets   *[]MintAsset
+	Lovelace *string
+}
+
+type MintArgs struct {
+	AssetName  *string
+	Assets     *[]MintAsset
+	Before     *int32
+	Nft        *NftMetadata
+	PolicyId   *string
+	Quantity   *string
+	Recipients *[]MintRecipient
+	Wallet     string
+}
+
+// mintAssets returns the combined list of assets to be minted
+func (m MintArgs) mintAssets() ([]MintAsset, error) {
+	var assets []MintAsset
+	if m.AssetName != nil || m.Quantity != nil {
+		assets = append(assets, MintAsset{
+			AssetName: StringValue(m.AssetName),
+			Nft:       m.Nft,
+			Quantity:  StringValue(m.Quantity),
+		})
+	}
+	if m.Assets != nil {
+		assets = append(assets, *m.Assets...)
+	}
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("no assets specified")
+	}
+
+	seen := map[string]struct{}{}
+	for _, asset := range assets {
+		if asset.AssetName == "" {
+			return nil, fmt.Errorf("assetName required")
+		}
+		assetNameHex, err := cardano.ParseAssetName(asset.AssetName)
+		if err != nil {
+			return nil, err
+		}
+		if asset.Nft != nil {
+			if err := asset.Nft.toMetadata().Validate(); err != nil {
+				return nil, fmt.Errorf("%v: %w", asset.AssetName, err)
+			}
+		}
+		if _, ok := seen[assetNameHex]; ok {
+			return nil, fmt.Errorf("duplicate assetName, %v", asset.AssetName)
+		}
+		seen[assetNameHex] = struct{}{}
+
+		if v, ok := big.NewInt(0).SetString(asset.Quantity, 10); !ok || v.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid quantity for %v, %v", asset.AssetName, asset.Quantity)
+		}
+	}
+	return assets, nil
+}
+
+// recipients returns the recipients with their lovelace defaulted along with the
+// total lovelace sent to them.  Recipients may receive at most max lovelace.
+// Recipient addresses, or wallet names, are normalized via cli.
+func (m MintArgs) recipients(cli Cardano, max *big.Int) ([]MintRecipient, *big.Int, error) {
+	if m.Recipients == nil {
+		return nil, big.NewInt(0), nil
+	}
+
+	var (
+		recipients []MintRecipient
+		total      = big.NewInt(0)
+	)
+	for _, recipient := range *m.Recipients {
+		if recipient.Address == "" {
+			return nil, nil, fmt.Errorf("recipient address required")
+		}
+		address, err := cli.NormalizeAddress(recipient.Address)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid recipient, %v: %w", recipient.Address, err)
+		}
+		recipient.Address = address
+		if recipient.Assets != nil {
+			for _, asset := range *recipient.Assets {
+				if asset.Nft != nil {
+					return nil, nil, fmt.Errorf("recipient, %v: nft metadata may only be provided with the minted assets", recipient.Address)
+				}
+			}
+		}
+		if recipient.Lovelace == nil || *recipient.Lovelace == "" {
+			recipient.Lovelace = String(defaultRecipientLovelace)
+		}
+		lovelace, ok := big.NewInt(0).SetString(*recipient.Lovelace, 10)
+		if !ok || lovelace.Sign() <= 0 {
+			return nil, nil, fmt.Errorf("invalid lovelace for recipient, %v: %v", recipient.Address, *recipient.Lovelace)
+		}
+		if lovelace.Cmp(max) > 0 {
+			return nil, nil, fmt.Errorf("lovelace for recipient, %v, exceeds maximum: %v > %v", recipient.Address, lovelace, max)
+		}
+		total.Add(total, lovelace)
+		recipients = append(recipients, recipient)
+	}
+	return recipients, total, nil
+}
+
+func (r *Resolver) Mint(ctx context.Context, args MintArgs) (*TxResultResolver, error) {
+	assets, err := args.mintAssets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint tokens: %w", err)
+	}
+	maxRecipientLovelace := r.config.MaxRecipientLovelace
+	if maxRecipientLovelace == nil {
+		maxRecipientLovelace = big.NewInt(DefaultMaxRecipientLovelace)
+	}
+	recipients, lovelace, err := args.recipients(r.config.CLI, maxRecipientLovelace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint tokens: %w", err)
+	}
+	required := big.NewInt(0).Add(lovelace, big.NewInt(mintChangeLovelace))
+
 	utxos, err := r.config.CLI.Utxos(args.Wallet, cardano.ExcludeScripts(true), cardano.ExcludeTokens(true))
 	if err != nil {
 		return nil, err
 	}
 
-	if len(utxos) < 2 {
-		if err := r.fundWallet(ctx, args.Wallet, "10000000"); err != nil {
+	available, err := utxos.Value()
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint tokens: %w", err)
+	}
+
+	if len(utxos) < 2 || available.Lovelace.Cmp(required) < 0 {
+		amount := big.NewInt(0).Add(lovelace, big.NewInt(10000000))
+		cancel, err := r.allowFunding(ctx, args.Wallet, amount.String())
+		if err != nil {
+			return nil, err
+		}
+		tx, err := r.fundWallet(ctx, args.Wallet, amount.String())
+		if err != nil {
+			cancel()
 			return nil, fmt.Errorf("failed to mint tokens: %w", err)
 		}
-
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(5 * time.Second):
-			// ok
+		if err := r.awaitTx(ctx, tx.ID, args.Wallet); err != nil {
+			return nil, fmt.Errorf("failed to mint tokens: %w", err)
 		}
 
 		utxos, err = r.config.CLI.Utxos(args.Wallet, cardano.ExcludeScripts(true), cardano.ExcludeTokens(true))
@@ -61,12 +233,36 @@ func (r *Resolver) Mint(ctx context.Context, args MintArgs) (*Resolver, error) {
 		}
 	}
 
+	// select enough utxos to cover the lovelace sent to recipients
+	var (
+		selected cardano.Utxos
+		value    = cardano.NewValue()
+	)
+	for _, utxo := range utxos {
+		if value.Lovelace.Cmp(required) >= 0 {
+			break
+		}
+		if err := value.Add(utxo); err != nil {
+			return nil, fmt.Errorf("failed to mint tokens: %w", err)
+		}
+		selected = append(selected, utxo)
+	}
+	if value.Lovelace.Cmp(required) < 0 {
+		return nil, fmt.Errorf("failed to mint tokens: insufficient funds in wallet, %v: %v lovelace required", args.Wallet, required)
+	}
+
+	policy, err := r.mintingPolicy(ctx, args.Wallet, args.PolicyId, args.Before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint tokens: %w", err)
+	}
+
 	input := BuildMintTxInput{
-		Fee:       "0",
-		Quantity:  args.Quantity,
-		AssetName: args.AssetName,
-		TxIn:      utxos[0],
-		Wallet:    args.Wallet,
+		Assets:     assets,
+		Fee:        "0",
+		Policy:     policy,
+		Recipients: recipients,
+		TxIn:       selected,
+		Wallet:     args.Wallet,
 	}
 	raw, err := r.buildMintTx(ctx, input)
 	if err != nil {
@@ -75,8 +271,8 @@ func (r *Resolver) Mint(ctx context.Context, args MintArgs) (*Resolver, error) {
 
 	feeArgs := TxFeeArgs{
 		Raw:       base64.StdEncoding.EncodeToString(raw),
-		TxIn:      1,
-		TxOut:     1,
+		TxIn:      int32(len(selected)),
+		TxOut:     int32(1 + len(recipients)),
 		Witnesses: 1,
 	}
 	fee, err := r.TxFee(ctx, feeArgs)
@@ -100,9 +296,5 @@ func (r *Resolver) Mint(ctx context.Context, args MintArgs) (*Resolver, error) {
 	}
 
 	submitArgs := TxSubmitArgs{Signed: signed.body}
-	if _, err := r.TxSubmit(ctx, submitArgs); err != nil {
-		return nil, err
-	}
-
-	return r, nil
+	return r.TxSubmit(cardano.WithTxKind(ctx, cardano.TxKindMint), submitArgs)
 }
@@ -2,19 +2,25 @@ package gql
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/faucet"
 	"github.com/segmentio/ksuid"
 	"github.com/tj/assert"
 )
 
 type Mock struct {
 	Cardano
-	fee      string // fee is the value returned by #MinFee
-	quantity string // quantity of lovelace every utxo returned by #Utxos will have
+	fee      string          // fee is the value returned by #MinFee
+	owner    string          // owner is the wallet of every policy returned by #FindPolicy
+	quantity string          // quantity of lovelace every utxo returned by #Utxos will have
+	tokens   []cardano.Token // tokens held by the first utxo returned by #Utxos
 	options  []cardano.BuildOptions
 }
 
@@ -23,6 +29,14 @@ func (m *Mock) Build(opts ...cardano.BuildOption) ([]byte, error) {
 	return []byte("{\"CborHex\": \"86a60081825820e13395515a10257b5bd279eccd45caa2c9f1a0305c77233010cd4cef86626336010d80018182583900f9aebd07330abcac10bb3b6e8e60961de12d6c5d3b6d464759ce79bb71ee23999a36cbf64a533b8051970109d38b0760278876cd187ce49b1a009896800200048182008200581c71ee23999a36cbf64a533b8051970109d38b0760278876cd187ce49b0e809fff8080f5f6\"}"), nil
 }
 
+func (m Mock) CreatePolicy(ctx context.Context, wallet string, before int32) (cardano.Policy, error) {
+	return cardano.Policy{ID: "PolicyID", Wallet: wallet, Before: before}, nil
+}
+
+func (m Mock) FindPolicy(policyID string) (cardano.Policy, error) {
+	return cardano.Policy{ID: policyID, Wallet: m.owner}, nil
+}
+
 func (m Mock) DataDir() string {
 	_ = os.MkdirAll("/tmp/data", 0755)
 	_ = os.MkdirAll("/tmp/tmp", 0755)
@@ -41,6 +55,9 @@ func (m Mock) MinFee(ctx context.Context, filename string, txIn, txOut, witnesse
 }
 
 func (m Mock) NormalizeAddress(address string) (string, error) {
+	if strings.HasPrefix(address, "invalid") {
+		return "", fmt.Errorf("invalid address, %v", address)
+	}
 	return strings.ToLower(address), nil
 }
 
@@ -62,6 +79,7 @@ func (m Mock) Utxos(address string, excludes ...func(cardano.Utxo) bool) (utxos
 		{
 			Address: txHash,
 			Index:   0,
+			Tokens:  m.tokens,
 			Value:   m.quantity,
 		},
 		{
@@ -85,8 +103,8 @@ func TestResolver_Mint(t *testing.T) {
 		)
 
 		args := MintArgs{
-			AssetName: "BLAH",
-			Quantity:  "100",
+			AssetName: String("BLAH"),
+			Quantity:  String("100"),
 			Wallet:    "Test",
 		}
 		_, err := resolver.Mint(ctx, args)
@@ -109,8 +127,8 @@ func TestResolver_Mint(t *testing.T) {
 		)
 
 		args := MintArgs{
-			AssetName: "BLAH",
-			Quantity:  "100",
+			AssetName: String("BLAH"),
+			Quantity:  String("100"),
 			Wallet:    "Test",
 		}
 		_, err := resolver.Mint(ctx, args)
@@ -120,4 +138,231 @@ func TestResolver_Mint(t *testing.T) {
 		option := mock.options[1]
 		assert.Equal(t, option.TxOut[0].Quantity, "10000000")
 	})
+	t.Run("policy owned by other wallet", func(t *testing.T) {
+		var (
+			ctx      = context.Background()
+			mock     = &Mock{owner: "Other", quantity: "10000000"}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+			policyID = "PolicyID"
+		)
+
+		args := MintArgs{
+			AssetName: String("BLAH"),
+			PolicyId:  &policyID,
+			Quantity:  String("100"),
+			Wallet:    "Test",
+		}
+		_, err := resolver.Mint(ctx, args)
+		assert.NotNil(t, err)
+		assert.Len(t, mock.options, 0)
+	})
+
+	t.Run("time locked policy", func(t *testing.T) {
+		var (
+			ctx      = context.Background()
+			mock     = &Mock{quantity: "10000000"}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+			before   = int32(1234)
+		)
+
+		args := MintArgs{
+			AssetName: String("BLAH"),
+			Before:    &before,
+			Quantity:  String("100"),
+			Wallet:    "Test",
+		}
+		_, err := resolver.Mint(ctx, args)
+		assert.Nil(t, err)
+		assert.Len(t, mock.options, 2)
+		assert.Equal(t, before, mock.options[1].InvalidHereafter)
+	})
+	t.Run("batch with recipients", func(t *testing.T) {
+		var (
+			ctx  = context.Background()
+			mock = &Mock{
+				fee:      "1000",
+				quantity: "10000000",
+			}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		assets := []MintAsset{
+			{AssetName: "ONE", Quantity: "100"},
+			{AssetName: "TWO", Quantity: "200"},
+		}
+		recipients := []MintRecipient{
+			{
+				Address: "Alice",
+				Assets:  &[]MintAsset{{AssetName: "ONE", Quantity: "40"}},
+			},
+			{
+				Address:  "Bob",
+				Assets:   &[]MintAsset{{AssetName: "ONE", Quantity: "10"}, {AssetName: "TWO", Quantity: "200"}},
+				Lovelace: String("3000000"),
+			},
+		}
+		args := MintArgs{
+			Assets:     &assets,
+			Recipients: &recipients,
+			Wallet:     "Test",
+		}
+		_, err := resolver.Mint(ctx, args)
+		assert.Nil(t, err)
+		assert.Len(t, mock.options, 2)
+
+		option := mock.options[1]
+		assert.Len(t, option.TxIn, 1)
+		assert.Equal(t, "100 PolicyID.4f4e45+200 PolicyID.54574f", option.Mint)
+		assert.Len(t, option.TxOut, 3)
+		assert.Equal(t, "4999000", option.TxOut[0].Quantity)
+		assert.Equal(t, []string{"50 PolicyID.4f4e45"}, option.TxOut[0].Tokens)
+		assert.Equal(t, "alice", option.TxOut[1].Address)
+		assert.Equal(t, defaultRecipientLovelace, option.TxOut[1].Quantity)
+		assert.Equal(t, []string{"40 PolicyID.4f4e45"}, option.TxOut[1].Tokens)
+		assert.Equal(t, "3000000", option.TxOut[2].Quantity)
+		assert.Equal(t, []string{"10 PolicyID.4f4e45", "200 PolicyID.54574f"}, option.TxOut[2].Tokens)
+	})
+
+	t.Run("recipients exceed minted", func(t *testing.T) {
+		var (
+			ctx      = context.Background()
+			mock     = &Mock{quantity: "10000000"}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		recipients := []MintRecipient{
+			{Address: "Alice", Assets: &[]MintAsset{{AssetName: "BLAH", Quantity: "101"}}},
+		}
+		args := MintArgs{
+			AssetName:  String("BLAH"),
+			Quantity:   String("100"),
+			Recipients: &recipients,
+			Wallet:     "Test",
+		}
+		_, err := resolver.Mint(ctx, args)
+		assert.NotNil(t, err)
+	})
+	t.Run("nft metadata", func(t *testing.T) {
+		var (
+			ctx      = context.Background()
+			mock     = &Mock{quantity: "10000000"}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		args := MintArgs{
+			AssetName: String("Sundae01"),
+			Nft: &NftMetadata{
+				Image:     "ipfs://abc",
+				MediaType: String("image/png"),
+				Name:      "Sundae #1",
+			},
+			Quantity: String("1"),
+			Wallet:   "Test",
+		}
+		_, err := resolver.Mint(ctx, args)
+		assert.Nil(t, err)
+		assert.Len(t, mock.options, 2)
+		assert.Equal(t, `{"721":{"PolicyID":{"Sundae01":{"name":"Sundae #1","image":"ipfs://abc","mediaType":"image/png"}},"version":"1.0"}}`, string(mock.options[1].Metadata))
+	})
+
+	t.Run("recipient lovelace exceeds maximum", func(t *testing.T) {
+		var (
+			ctx      = context.Background()
+			mock     = &Mock{quantity: "1000000"}
+			config   = Config{CLI: mock, MaxRecipientLovelace: big.NewInt(5000000)}
+			resolver = &Resolver{config: config}
+		)
+
+		args := MintArgs{
+			AssetName:  String("BLAH"),
+			Quantity:   String("100"),
+			Recipients: &[]MintRecipient{{Address: "Alice", Lovelace: String("5000001")}},
+			Wallet:     "Test",
+		}
+		_, err := resolver.Mint(ctx, args)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "exceeds maximum")
+		assert.Len(t, mock.options, 0)
+
+		args.Recipients = &[]MintRecipient{{Address: "Alice", Lovelace: String("9000000000000000")}}
+		config.MaxRecipientLovelace = nil
+		_, err = (&Resolver{config: config}).Mint(ctx, args)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "exceeds maximum")
+	})
+
+	t.Run("invalid recipient address", func(t *testing.T) {
+		var (
+			ctx      = context.Background()
+			mock     = &Mock{quantity: "1000000"}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		args := MintArgs{
+			AssetName:  String("BLAH"),
+			Quantity:   String("100"),
+			Recipients: &[]MintRecipient{{Address: "invalid\n--tx-out"}},
+			Wallet:     "Test",
+		}
+		_, err := resolver.Mint(ctx, args)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "invalid recipient")
+		assert.Len(t, mock.options, 0)
+	})
+
+	t.Run("treasury top-up counts against faucet quotas", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "limiter")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+
+		limiter, err := faucet.NewLimiter(dir, faucet.Limits{DailyBudget: big.NewInt(20000000)})
+		assert.Nil(t, err)
+
+		var (
+			ctx      = context.Background()
+			mock     = &Mock{quantity: "1000000"}
+			config   = Config{CLI: mock, Limiter: limiter}
+			resolver = &Resolver{config: config}
+		)
+
+		args := MintArgs{
+			AssetName:  String("BLAH"),
+			Quantity:   String("100"),
+			Recipients: &[]MintRecipient{{Address: "Alice", Lovelace: String("50000000")}},
+			Wallet:     "Test",
+		}
+		_, err = resolver.Mint(ctx, args)
+		assert.NotNil(t, err)
+		_, ok := err.(*faucet.LimitError)
+		assert.True(t, ok)
+		assert.Len(t, mock.options, 0)
+	})
+
+	t.Run("invalid nft metadata", func(t *testing.T) {
+		var (
+			ctx      = context.Background()
+			mock     = &Mock{quantity: "10000000"}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		args := MintArgs{
+			AssetName: String("Sundae01"),
+			Nft: &NftMetadata{
+				Image: "ipfs://abc",
+				Name:  strings.Repeat("x", 65),
+			},
+			Quantity: String("1"),
+			Wallet:   "Test",
+		}
+		_, err := resolver.Mint(ctx, args)
+		assert.NotNil(t, err)
+		assert.Len(t, mock.options, 0)
+	})
 }
@@ -25,52 +25,37 @@ package gql
 import (
 	"context"
 	"fmt"
-	"math/big"
 
 	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
 )
 
 type SendFundArgs struct {
-	Source string
-	Target *string
-	TxIn   []TxIn
+	Metadata       *string
+	MetadataSchema string
+	Source         string
+	Target         *string
+	TxIn           []TxIn
 }
 
-func (r *Resolver) SendFunds(ctx context.Context, args SendFundArgs) (*Resolver, error) {
+func (r *Resolver) SendFunds(ctx context.Context, args SendFundArgs) (*TxResultResolver, error) {
 	utxos, err := r.config.CLI.Utxos("", cardano.ExcludeScripts(true))
 	if err != nil {
 		return nil, err
 	}
 
-	var (
-		sum     = big.NewInt(0)
-		tokens  = map[string]*big.Int{}
-		options []cardano.BuildOption
-	)
+	options, err := metadataOptions(args.Metadata, args.MetadataSchema)
+	if err != nil {
+		return nil, fmt.Errorf("sendFunds failed: %w", err)
+	}
+
+	value := cardano.NewValue()
 	for _, txIn := range args.TxIn {
 		utxo, err := utxos.Find(txIn.Address, txIn.Index)
 		if err != nil {
 			return nil, fmt.Errorf("sendFunds failed: %w", err)
 		}
-
-		v, ok := big.NewInt(0).SetString(utxo.Value, 10)
-		if !ok {
-			return nil, fmt.Errorf("failed to parse utxo, %v", utxo.Value)
-		}
-
-		sum = big.NewInt(0).Add(sum, v)
-		for _, token := range utxo.Tokens {
-			v, ok := big.NewInt(0).SetString(token.Quantity, 10)
-			if !ok {
-				return nil, fmt.Errorf("failed to parse token quantity, %v", token.Quantity)
-			}
-
-			assetID := token.Asset.ID()
-			total := tokens[assetID]
-			if total == nil {
-				total = big.NewInt(0)
-			}
-			tokens[assetID] = big.NewInt(0).Add(total, v)
+		if err := value.Add(utxo); err != nil {
+			return nil, fmt.Errorf("sendFunds failed: %w", err)
 		}
 
 		options = append(options, cardano.TxIn(txIn.Address, txIn.Index))
@@ -84,11 +69,7 @@ func (r *Resolver) SendFunds(ctx context.Context, args SendFundArgs) (*Resolver,
 			}
 		}
 
-		var tt []string
-		for assetID, amount := range tokens {
-			tt = append(tt, amount.String()+" "+assetID)
-		}
-		options = append(options, cardano.TxOut(address, sum.String(), tt...))
+		options = append(options, cardano.TxOut(address, value.Lovelace.String(), value.TokenArgs()...))
 	}
 	options = append(options, cardano.Fee("0"))
 
@@ -102,9 +83,14 @@ func (r *Resolver) SendFunds(ctx context.Context, args SendFundArgs) (*Resolver,
 		return nil, err
 	}
 
-	if err := r.config.CLI.Submit(ctx, signed); err != nil {
+	tx, err := cardano.ParseTx(signed)
+	if err != nil {
+		return nil, fmt.Errorf("sendFunds failed: %w", err)
+	}
+
+	if err := r.config.CLI.Submit(cardano.WithTxKind(ctx, cardano.TxKindSend), signed); err != nil {
 		return nil, err
 	}
 
-	return r, nil
+	return r.txResult(tx), nil
 }
@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/registry"
+)
+
+type TokenRegisterArgs struct {
+	AssetName   string
+	Decimals    *int32
+	Description *string
+	Logo        *string
+	Name        string
+	PolicyId    string
+	Ticker      *string
+	Url         *string
+}
+
+func (r *Resolver) TokenRegister(args TokenRegisterArgs) (*AssetResolver, error) {
+	if r.config.Registry == nil {
+		return nil, fmt.Errorf("unable to register token: token registry not configured")
+	}
+
+	assetNameHex, err := cardano.ParseAssetName(args.AssetName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to register token: %w", err)
+	}
+
+	entry := registry.Entry{
+		Subject:     registry.Subject(args.PolicyId, assetNameHex),
+		Name:        args.Name,
+		Ticker:      StringValue(args.Ticker),
+		Logo:        StringValue(args.Logo),
+		Url:         StringValue(args.Url),
+		Decimals:    args.Decimals,
+		Description: StringValue(args.Description),
+	}
+	if err := r.config.Registry.Register(entry); err != nil {
+		return nil, err
+	}
+
+	asset := &cardano.Asset{
+		AssetName: assetNameHex,
+		PolicyId:  args.PolicyId,
+	}
+	return &AssetResolver{asset: r.config.Registry.Apply(asset)}, nil
+}
+
+type TokenImportArgs struct {
+	Mappings string
+}
+
+func (r *Resolver) TokenImport(args TokenImportArgs) (int32, error) {
+	if r.config.Registry == nil {
+		return 0, fmt.Errorf("unable to import tokens: token registry not configured")
+	}
+
+	n, err := r.config.Registry.Import(strings.NewReader(args.Mappings))
+	return int32(n), err
+}
@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+)
+
+type TreasuryMaintainArgs struct {
+	SweepGenesis bool
+	Consolidate  bool
+	MaxInputs    *int32
+}
+
+// TreasuryMaintain optionally sweeps the genesis utxo keys into the treasury
+// and consolidates treasury dust before reporting the treasury status
+func (r *Resolver) TreasuryMaintain(ctx context.Context, args TreasuryMaintainArgs) (*TreasuryMaintenanceResolver, error) {
+	if err := r.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	resolver := &TreasuryMaintenanceResolver{}
+	if r.config.GenesisDir != "" {
+		keys, err := r.config.CLI.GenesisKeys(r.config.GenesisDir)
+		if err != nil {
+			return nil, err
+		}
+		resolver.keys = keys
+	}
+
+	if args.SweepGenesis {
+		if r.config.GenesisDir == "" {
+			return nil, fmt.Errorf("unable to sweep genesis funds: set GENESIS_DIR to enable")
+		}
+		tx, err := r.config.CLI.SweepGenesis(ctx, r.config.GenesisDir)
+		if err != nil {
+			return nil, err
+		}
+		if tx.ID != "" {
+			resolver.sweep = r.txResult(tx)
+		}
+	}
+
+	if args.Consolidate {
+		var max int
+		if args.MaxInputs != nil {
+			max = int(*args.MaxInputs)
+		}
+		tx, _, err := r.config.CLI.ConsolidateTreasury(ctx, max)
+		if err != nil {
+			return nil, err
+		}
+		if tx.ID != "" {
+			resolver.consolidate = r.txResult(tx)
+		}
+	}
+
+	status, err := r.config.CLI.TreasuryStatus()
+	if err != nil {
+		return nil, err
+	}
+	resolver.status = &TreasuryStatusResolver{status: status}
+
+	return resolver, nil
+}
+
+type TreasuryMaintenanceResolver struct {
+	keys        []cardano.GenesisKey
+	sweep       *TxResultResolver
+	consolidate *TxResultResolver
+	status      *TreasuryStatusResolver
+}
+
+func (t *TreasuryMaintenanceResolver) GenesisKeys() []*GenesisKeyResolver {
+	resolvers := []*GenesisKeyResolver{}
+	for _, key := range t.keys {
+		resolvers = append(resolvers, &GenesisKeyResolver{key: key})
+	}
+	return resolvers
+}
+
+func (t *TreasuryMaintenanceResolver) Sweep() *TxResultResolver        { return t.sweep }
+func (t *TreasuryMaintenanceResolver) Consolidate() *TxResultResolver  { return t.consolidate }
+func (t *TreasuryMaintenanceResolver) Status() *TreasuryStatusResolver { return t.status }
+
+type GenesisKeyResolver struct {
+	key cardano.GenesisKey
+}
+
+func (g *GenesisKeyResolver) SigningKeyFile() string { return g.key.SigningKeyFile }
+func (g *GenesisKeyResolver) Address() string        { return g.key.Address }
+func (g *GenesisKeyResolver) Lovelace() string       { return g.key.Lovelace }
+func (g *GenesisKeyResolver) Utxos() int32           { return int32(len(g.key.Utxos)) }
+
+type TreasuryStatusResolver struct {
+	status cardano.TreasuryStatus
+}
+
+func (t *TreasuryStatusResolver) Address() string      { return t.status.Address }
+func (t *TreasuryStatusResolver) Lovelace() string     { return t.status.Lovelace }
+func (t *TreasuryStatusResolver) Utxos() int32         { return int32(t.status.Utxos) }
+func (t *TreasuryStatusResolver) InFlight() int32      { return int32(t.status.InFlight) }
+func (t *TreasuryStatusResolver) Fundable() int32      { return int32(t.status.Fundable) }
+func (t *TreasuryStatusResolver) TokenUtxos() int32    { return int32(t.status.TokenUtxos) }
+func (t *TreasuryStatusResolver) Dust() int32          { return int32(t.status.Dust) }
+func (t *TreasuryStatusResolver) DustLovelace() string { return t.status.DustLovelace }
+func (t *TreasuryStatusResolver) Largest() string      { return t.status.Largest }
@@ -0,0 +1,51 @@
+package gql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/tj/assert"
+)
+
+type TreasuryMock struct {
+	Mock
+	consolidated int
+}
+
+func (m *TreasuryMock) ConsolidateTreasury(ctx context.Context, max int) (cardano.Tx, int, error) {
+	m.consolidated = max
+	return cardano.Tx{ID: "abc"}, 2, nil
+}
+
+func (m *TreasuryMock) TreasuryStatus() (cardano.TreasuryStatus, error) {
+	return cardano.TreasuryStatus{Lovelace: "100", Utxos: 3, Fundable: 2, Dust: 1}, nil
+}
+
+func TestResolver_TreasuryMaintain(t *testing.T) {
+	var (
+		maxInputs = int32(10)
+		mock      = &TreasuryMock{}
+		resolver  = &Resolver{config: Config{CLI: mock, AdminToken: "secret"}}
+		args      = TreasuryMaintainArgs{Consolidate: true, MaxInputs: &maxInputs}
+	)
+
+	_, err := resolver.TreasuryMaintain(context.Background(), args)
+	assert.NotNil(t, err)
+
+	_, err = resolver.TreasuryMaintain(withAuthorization(context.Background(), "Bearer wrong"), args)
+	assert.NotNil(t, err)
+
+	got, err := resolver.TreasuryMaintain(withAuthorization(context.Background(), "Bearer secret"), args)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, mock.consolidated)
+	assert.Equal(t, "abc", got.Consolidate().Id())
+	assert.Nil(t, got.Sweep())
+	assert.Equal(t, "100", got.Status().Lovelace())
+	assert.Len(t, got.GenesisKeys(), 0)
+
+	// disabled without an admin token
+	resolver = &Resolver{config: Config{CLI: mock}}
+	_, err = resolver.TreasuryMaintain(withAuthorization(context.Background(), "Bearer "), args)
+	assert.NotNil(t, err)
+}
@@ -69,13 +69,18 @@ type TxOut struct {
 }
 
 type TxBuildArgs struct {
-	Fee   string
-	TxIn  []TxIn
-	TxOut []TxOut
+	Fee            string
+	Metadata       *string
+	MetadataSchema string
+	TxIn           []TxIn
+	TxOut          []TxOut
 }
 
 func (r *Resolver) TxBuild(args TxBuildArgs) (*TxResolver, error) {
-	var opts []cardano.BuildOption
+	opts, err := metadataOptions(args.Metadata, args.MetadataSchema)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build tx: %w", err)
+	}
 	opts = append(opts, cardano.Fee(args.Fee))
 	for _, txIn := range args.TxIn {
 		opts = append(opts, cardano.TxIn(txIn.Address, txIn.Index))
@@ -98,6 +103,7 @@ func (r *Resolver) TxBuild(args TxBuildArgs) (*TxResolver, error) {
 		body: base64.StdEncoding.EncodeToString(data),
 		id:   tx.ID,
 		raw:  data,
+		tx:   tx,
 	}, nil
 }
 
@@ -125,6 +131,7 @@ func (r *Resolver) TxSign(ctx context.Context, args TxSignArgs) (*TxResolver, er
 	return &TxResolver{
 		body: base64.StdEncoding.EncodeToString(data),
 		id:   tx.ID,
+		tx:   tx,
 	}, nil
 }
 
@@ -132,15 +139,20 @@ type TxSubmitArgs struct {
 	Signed string
 }
 
-func (r *Resolver) TxSubmit(ctx context.Context, args TxSubmitArgs) (*Resolver, error) {
+func (r *Resolver) TxSubmit(ctx context.Context, args TxSubmitArgs) (*TxResultResolver, error) {
 	data, err := base64.StdEncoding.DecodeString(args.Signed)
 	if err != nil {
 		return nil, fmt.Errorf("unable to submit tx: unable to base64 decode string: %w", err)
 	}
 
+	tx, err := cardano.ParseTx(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to submit tx: %w", err)
+	}
+
 	if err := r.config.CLI.Submit(ctx, data); err != nil {
 		return nil, err
 	}
 
-	return r, nil
+	return r.txResult(tx), nil
 }
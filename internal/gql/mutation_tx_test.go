@@ -0,0 +1,109 @@
+package gql
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/tj/assert"
+)
+
+func TestResolver_TxBuild(t *testing.T) {
+	t.Run("metadata", func(t *testing.T) {
+		var (
+			mock     = &Mock{}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		args := TxBuildArgs{
+			Fee:            "0",
+			Metadata:       String(`{"674": {"string": "hello"}}`),
+			MetadataSchema: "DETAILED_SCHEMA",
+		}
+		tx, err := resolver.TxBuild(args)
+		assert.Nil(t, err)
+		assert.NotNil(t, tx)
+		assert.Len(t, mock.options, 1)
+		assert.Equal(t, cardano.MetadataDetailedSchema, mock.options[0].MetadataSchema)
+		assert.Equal(t, `{"674": {"string": "hello"}}`, string(mock.options[0].Metadata))
+
+		metadata, err := tx.Metadata(TxMetadataArgs{Schema: "NO_SCHEMA"})
+		assert.Nil(t, err)
+		assert.Nil(t, metadata)
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		var (
+			mock     = &Mock{}
+			config   = Config{CLI: mock}
+			resolver = &Resolver{config: config}
+		)
+
+		args := TxBuildArgs{
+			Fee:      "0",
+			Metadata: String(`{"label": "hello"}`),
+		}
+		_, err := resolver.TxBuild(args)
+		assert.NotNil(t, err)
+		assert.Len(t, mock.options, 0)
+	})
+}
+
+func TestResolver_TxSubmit(t *testing.T) {
+	handler, err := New(Config{CLI: &Mock{}})
+	assert.Nil(t, err)
+
+	raw, err := (&Mock{}).Build()
+	assert.Nil(t, err)
+
+	query := `mutation($signed: String!) {
+		txSubmit(signed: $signed) {
+			id
+			fee
+			inputs { txId index }
+			outputs { index value }
+			status
+			ok
+			query { ok }
+		}
+	}`
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": map[string]string{"signed": base64.StdEncoding.EncodeToString(raw)},
+	})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+
+	var resp struct {
+		Data struct {
+			TxSubmit struct {
+				Id      string
+				Fee     string
+				Inputs  []struct{ TxId string }
+				Outputs []struct{ Value string }
+				Status  string
+				Ok      string
+				Query   struct{ Ok string }
+			}
+		}
+		Errors []interface{}
+	}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Errors, 0)
+
+	got := resp.Data.TxSubmit
+	assert.Len(t, got.Id, 64)
+	assert.Equal(t, "0", got.Fee)
+	assert.Equal(t, []struct{ TxId string }{{TxId: "e13395515a10257b5bd279eccd45caa2c9f1a0305c77233010cd4cef86626336"}}, got.Inputs)
+	assert.Equal(t, []struct{ Value string }{{Value: "10000000"}}, got.Outputs)
+	assert.Equal(t, "SUBMITTED", got.Status)
+	assert.Equal(t, "ok", got.Ok)
+	assert.Equal(t, "ok", got.Query.Ok)
+}
@@ -24,11 +24,10 @@ package gql
 
 import (
 	"context"
-	"regexp"
-	"time"
-)
+	"fmt"
 
-var reValueNotConserved = regexp.MustCompile(`ValueNotConservedUTxO\s*\(Value\s+0`)
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+)
 
 type WalletCreateArgs struct {
 	InitialFunds *string
@@ -42,68 +41,135 @@ func (r *Resolver) WalletCreate(ctx context.Context, args WalletCreateArgs) (str
 		initialFunds = *args.InitialFunds
 	}
 
-	s, err := r.config.CLI.CreateWallet(ctx, initialFunds, StringValue(args.Name))
+	// the wallet address is not known until created so only the ip and daily quotas apply
+	cancel, err := r.allowFunding(ctx, "", initialFunds)
 	if err != nil {
-		return s, err
+		return "", err
 	}
+
 	if args.Delegation == "NONE" {
-		return s, nil
+		s, err := r.config.CLI.CreateWallet(ctx, initialFunds, StringValue(args.Name))
+		if err != nil {
+			cancel()
+		}
+		return s, err
+	}
+
+	// fund separately so registration can wait for the initial funds to confirm
+	s, err := r.config.CLI.CreateWallet(ctx, "", StringValue(args.Name))
+	if err != nil {
+		cancel()
+		return s, err
 	}
-	time.Sleep(3 * time.Second)
-	_, err = r.WalletRegister(ctx, WalletRegisterArgs{Address: StringValue(args.Name)})
+	tx, err := r.fundWallet(ctx, s, initialFunds)
+	if err != nil {
+		cancel()
+		return s, err
+	}
+	if err := r.awaitTx(ctx, tx.ID, s); err != nil {
+		return s, err
+	}
+
+	tx, err = r.config.CLI.RegisterStake(ctx, s)
 	if err != nil {
 		return s, err
 	}
 	if args.Delegation == "REGISTERED" {
 		return s, nil
 	}
-	time.Sleep(3 * time.Second)
-	_, err = r.WalletDelegate(ctx, WalletDelegateArgs{Address: StringValue(args.Name)})
-	if err != nil {
+	if err := r.awaitTx(ctx, tx.ID, s); err != nil {
+		return s, err
+	}
+
+	if _, err := r.config.CLI.Delegate(ctx, s); err != nil {
 		return s, err
 	}
 	return s, nil
 }
 
-type WalletFundArgs struct {
-	Address  string
+type FundAsset struct {
+	AssetId  string
 	Quantity string
 }
 
-func (r *Resolver) WalletFund(ctx context.Context, args WalletFundArgs) (*Resolver, error) {
-
-	for attempt := 1; attempt <= 5; attempt++ {
-		if err := r.fundWallet(ctx, args.Address, args.Quantity); err != nil {
-			if reValueNotConserved.MatchString(err.Error()) {
-				select {
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				case <-time.After(2 * time.Duration(attempt) * time.Second):
-					continue
-				}
-			}
+type WalletFundArgs struct {
+	Address        string
+	Assets         *[]FundAsset
+	Metadata       *string
+	MetadataSchema string
+	Quantity       string
+}
+
+// WalletFund returns the funding tx or nil when no funds were requested
+func (r *Resolver) WalletFund(ctx context.Context, args WalletFundArgs) (*TxResultResolver, error) {
+	opts, err := metadataOptions(args.Metadata, args.MetadataSchema)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fund wallet: %w", err)
+	}
+
+	if args.Assets != nil && len(*args.Assets) > 0 {
+		var assets []cardano.AssetQuantity
+		for _, asset := range *args.Assets {
+			assets = append(assets, cardano.AssetQuantity{
+				AssetID:  asset.AssetId,
+				Quantity: asset.Quantity,
+			})
+		}
+
+		// charge the quotas with the lovelace actually paid, raised to the min-ada
+		lovelace, err := r.config.CLI.FundAssetsLovelace(ctx, args.Address, args.Quantity, assets)
+		if err != nil {
+			return nil, err
+		}
+		cancel, err := r.allowFundingAssets(ctx, args.Address, lovelace, assets)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := r.config.CLI.FundWalletAssets(ctx, args.Address, lovelace, assets, opts...)
+		if err != nil {
+			cancel()
 			return nil, err
 		}
-		return r, nil
+		return r.txResult(tx), nil
 	}
 
-	return r, nil
+	cancel, err := r.allowFunding(ctx, args.Address, args.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.fundWallet(ctx, args.Address, args.Quantity, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if tx.ID == "" {
+		return nil, nil
+	}
+
+	return r.txResult(tx), nil
 }
 
 type WalletRegisterArgs struct {
 	Address string
 }
 
-func (r *Resolver) WalletRegister(ctx context.Context, args WalletRegisterArgs) (*Resolver, error) {
-	_, err := r.config.CLI.RegisterStake(ctx, args.Address)
-	return r, err
+func (r *Resolver) WalletRegister(ctx context.Context, args WalletRegisterArgs) (*TxResultResolver, error) {
+	tx, err := r.config.CLI.RegisterStake(ctx, args.Address)
+	if err != nil {
+		return nil, err
+	}
+	return r.txResult(tx), nil
 }
 
 type WalletDelegateArgs struct {
 	Address string
 }
 
-func (r *Resolver) WalletDelegate(ctx context.Context, args WalletDelegateArgs) (*Resolver, error) {
-	_, err := r.config.CLI.Delegate(ctx, args.Address)
-	return r, err
+func (r *Resolver) WalletDelegate(ctx context.Context, args WalletDelegateArgs) (*TxResultResolver, error) {
+	tx, err := r.config.CLI.Delegate(ctx, args.Address)
+	if err != nil {
+		return nil, err
+	}
+	return r.txResult(tx), nil
 }
@@ -0,0 +1,86 @@
+package gql
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/faucet"
+	"github.com/tj/assert"
+)
+
+type FundAssetsMock struct {
+	Mock
+	funded []string // funded holds the lovelace of each FundWalletAssets call
+}
+
+func (m *FundAssetsMock) FundAssetsLovelace(ctx context.Context, address, quantity string, assets []cardano.AssetQuantity) (string, error) {
+	return "1500000", nil
+}
+
+func (m *FundAssetsMock) FundWalletAssets(ctx context.Context, address, quantity string, assets []cardano.AssetQuantity, opts ...cardano.BuildOption) (cardano.Tx, error) {
+	m.funded = append(m.funded, quantity)
+	return cardano.Tx{ID: "abc"}, nil
+}
+
+func TestResolver_WalletFundAssets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "limiter")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	limiter, err := faucet.NewLimiter(dir, faucet.Limits{
+		PerAddress:       big.NewInt(2000000),
+		TokensPerRequest: big.NewInt(100),
+		TokenQuota:       big.NewInt(150),
+	})
+	assert.Nil(t, err)
+
+	var (
+		ctx      = faucet.WithClientIP(context.Background(), "1.2.3.4")
+		mock     = &FundAssetsMock{}
+		resolver = &Resolver{config: Config{CLI: mock, Limiter: limiter}}
+		args     = WalletFundArgs{
+			Address: "alice",
+			Assets:  &[]FundAsset{{AssetId: "TOK", Quantity: "100"}},
+		}
+	)
+
+	// quantity defaults to zero, but the quota is charged the min-ada paid
+	_, err = resolver.WalletFund(ctx, args)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1500000"}, mock.funded)
+
+	// the lovelace quota is exhausted by the min-ada of the first payout
+	_, err = resolver.WalletFund(ctx, args)
+	limitErr, ok := err.(*faucet.LimitError)
+	assert.True(t, ok)
+	assert.Equal(t, "address", limitErr.Limit)
+	assert.Len(t, mock.funded, 1)
+
+	t.Run("token quota", func(t *testing.T) {
+		resolver.config.Limiter, err = faucet.NewLimiter(dir+"/tokens", faucet.Limits{TokenQuota: big.NewInt(150)})
+		assert.Nil(t, err)
+
+		_, err := resolver.WalletFund(ctx, args)
+		assert.Nil(t, err)
+
+		_, err = resolver.WalletFund(ctx, WalletFundArgs{Address: "bob", Assets: args.Assets})
+		limitErr, ok := err.(*faucet.LimitError)
+		assert.True(t, ok)
+		assert.Equal(t, "ip-tokens", limitErr.Limit)
+	})
+
+	t.Run("per request", func(t *testing.T) {
+		resolver.config.Limiter = limiter
+		_, err := resolver.WalletFund(ctx, WalletFundArgs{
+			Address: "carol",
+			Assets:  &[]FundAsset{{AssetId: "TOK", Quantity: "101"}},
+		})
+		assert.NotNil(t, err)
+		_, ok := err.(*faucet.LimitError)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,137 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/savaki/zapctx"
+	"go.uber.org/zap"
+)
+
+// DefaultPollInterval is how often the tip is polled while subscriptions are active
+const DefaultPollInterval = time.Second
+
+// poller polls the tip on behalf of every subscription so the number of
+// subscribers does not change the load placed on cardano-cli.  The poller only
+// runs while at least one subscription is active.
+type poller struct {
+	cli      Cardano
+	interval time.Duration
+
+	mutex  sync.Mutex
+	subs   map[chan *cardano.Tip]struct{}
+	tip    *cardano.Tip // tip holds the last tip observed
+	cancel context.CancelFunc
+}
+
+func newPoller(cli Cardano, interval time.Duration) *poller {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &poller{
+		cli:      cli,
+		interval: interval,
+		subs:     map[chan *cardano.Tip]struct{}{},
+	}
+}
+
+// subscribe returns a channel that receives the tip each time a new block
+// lands, starting with the current tip if known.  Slow subscribers only ever
+// see the latest tip.  The returned func must be called to unsubscribe.
+func (p *poller) subscribe() (<-chan *cardano.Tip, func()) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ch := make(chan *cardano.Tip, 1)
+	if p.tip != nil {
+		ch <- p.tip
+	}
+	p.subs[ch] = struct{}{}
+
+	if p.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		p.cancel = cancel
+		go p.run(ctx)
+	}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+
+			delete(p.subs, ch)
+			if len(p.subs) == 0 && p.cancel != nil {
+				p.cancel()
+				p.cancel = nil
+				p.tip = nil
+			}
+		})
+	}
+}
+
+func (p *poller) run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		tip, err := p.cli.QueryTip()
+		if err != nil {
+			zapctx.FromContext(ctx).Info("failed to poll tip", zap.Error(err))
+		} else {
+			p.publish(ctx, tip)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish notifies subscribers when tip differs from the last tip observed
+func (p *poller) publish(ctx context.Context, tip *cardano.Tip) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if ctx.Err() != nil {
+		return // unsubscribed while querying the tip
+	}
+	if p.tip != nil && p.tip.Hash == tip.Hash {
+		return
+	}
+	p.tip = tip
+
+	for ch := range p.subs {
+		select {
+		case <-ch: // drop the stale tip
+		default:
+		}
+		ch <- tip
+	}
+}
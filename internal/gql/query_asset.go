@@ -0,0 +1,115 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+)
+
+type AssetArgs struct {
+	Id string
+}
+
+// Asset returns the supply and holders of an asset.  Holders and mints come
+// from db-sync when configured; otherwise holders are computed from the whole
+// utxo set and mints from the txs recorded by the toolkit.
+func (r *Resolver) Asset(ctx context.Context, args AssetArgs) (*AssetSupplyResolver, error) {
+	var (
+		utxos       cardano.Utxos
+		fingerprint = strings.HasPrefix(args.Id, "asset1")
+		err         error
+	)
+	if r.config.Chain == nil || fingerprint {
+		if utxos, err = r.config.CLI.UtxoSet(); err != nil {
+			return nil, fmt.Errorf("failed to find asset, %v: %w", args.Id, err)
+		}
+	}
+
+	asset, err := resolveAsset(args.Id, utxos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find asset, %v: %w", args.Id, err)
+	}
+	if asset == nil {
+		return nil, nil
+	}
+
+	var (
+		holders []chain.Holder
+		mints   []chain.Mint
+	)
+	switch {
+	case r.config.Chain != nil:
+		if holders, err = r.config.Chain.AssetHolders(ctx, asset.PolicyId, asset.AssetName); err != nil {
+			return nil, err
+		}
+		if mints, err = r.config.Chain.AssetMints(ctx, asset.PolicyId, asset.AssetName); err != nil {
+			return nil, err
+		}
+	default:
+		holders = chain.HoldersOf(utxos, asset.ID())
+		if r.config.History != nil {
+			if mints, err = r.config.History.AssetMints(ctx, asset.PolicyId, asset.AssetName); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &AssetSupplyResolver{
+		asset:    asset,
+		holders:  holders,
+		mints:    mints,
+		resolver: r,
+	}, nil
+}
+
+// resolveAsset returns the asset identified by either policyId.assetName, where
+// assetName is utf-8 unless prefixed with 0x, or by the CIP-14 fingerprint.
+// Fingerprints are resolved against utxos and nil is returned when none match.
+func resolveAsset(id string, utxos cardano.Utxos) (*cardano.Asset, error) {
+	if strings.HasPrefix(id, "asset1") {
+		match := cardano.MatchAsset(id)
+		for _, utxo := range utxos {
+			for _, token := range utxo.Tokens {
+				if match(token.Asset) {
+					return &cardano.Asset{PolicyId: token.Asset.PolicyId, AssetName: token.Asset.AssetName}, nil
+				}
+			}
+		}
+		return nil, nil
+	}
+
+	parts := strings.SplitN(id, ".", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, fmt.Errorf("expected policyId.assetName or fingerprint")
+	}
+	assetNameHex, err := cardano.ParseAssetName(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	return &cardano.Asset{PolicyId: strings.ToLower(parts[0]), AssetName: assetNameHex}, nil
+}
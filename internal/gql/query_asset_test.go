@@ -0,0 +1,63 @@
+package gql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/tj/assert"
+)
+
+type UtxoSetMock struct {
+	Mock
+	utxos cardano.Utxos
+}
+
+func (m UtxoSetMock) UtxoSet() (cardano.Utxos, error) {
+	return m.utxos, nil
+}
+
+func TestResolver_Asset(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		policyID = "35c0b7b5066d1738c97ca04e2a5ce30b02cbb8e6edd0363e3d94cf32"
+		asset    = &cardano.Asset{PolicyId: policyID, AssetName: "544f4b"}
+		token    = func(quantity string) []cardano.Token {
+			return []cardano.Token{{Asset: asset, Quantity: quantity}}
+		}
+	)
+	mock := &UtxoSetMock{
+		utxos: cardano.Utxos{
+			{Address: "a", Owner: "alice", Tokens: token("60")},
+			{Address: "b", Owner: "bob", Tokens: token("30")},
+			{Address: "c", Owner: "bob", Tokens: token("50")},
+			{Address: "d", Owner: "carol"},
+		},
+	}
+	resolver := &Resolver{config: Config{CLI: mock}}
+
+	supply, err := resolver.Asset(ctx, AssetArgs{Id: policyID + ".TOK"})
+	assert.Nil(t, err)
+	assert.Equal(t, "140", supply.Circulating())
+	assert.Equal(t, "0", supply.Minted())
+	assert.Equal(t, "TOK", *supply.Asset().AssetNameUtf8())
+
+	holders, err := supply.Holders(AssetHoldersArgs{First: 1})
+	assert.Nil(t, err)
+	assert.Len(t, holders, 1)
+	assert.Equal(t, "bob", holders[0].Address())
+	assert.Equal(t, "80", holders[0].Quantity())
+
+	holders, err = supply.Holders(AssetHoldersArgs{First: 1, After: String("bob")})
+	assert.Nil(t, err)
+	assert.Len(t, holders, 1)
+	assert.Equal(t, "alice", holders[0].Address())
+
+	supply, err = resolver.Asset(ctx, AssetArgs{Id: asset.Fingerprint()})
+	assert.Nil(t, err)
+	assert.Equal(t, policyID+".544f4b", supply.Asset().AssetId())
+
+	supply, err = resolver.Asset(ctx, AssetArgs{Id: (&cardano.Asset{PolicyId: policyID, AssetName: "00"}).Fingerprint()})
+	assert.Nil(t, err)
+	assert.Nil(t, supply)
+}
@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type AwaitTxArgs struct {
+	Id      string
+	Address *string
+	Timeout int32
+}
+
+// AwaitTx blocks until the outputs of the tx appear in the utxo set or the
+// timeout, in seconds, elapses
+func (r *Resolver) AwaitTx(ctx context.Context, args AwaitTxArgs) ([]*UtxoResolver, error) {
+	if args.Timeout <= 0 {
+		return nil, fmt.Errorf("unable to await tx: timeout must be positive, %v", args.Timeout)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(args.Timeout)*time.Second)
+	defer cancel()
+
+	var addresses []string
+	if args.Address != nil && *args.Address != "" {
+		addresses = append(addresses, *args.Address)
+	}
+
+	utxos, err := r.config.CLI.AwaitTx(ctx, args.Id, addresses...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolvers []*UtxoResolver
+	for _, utxo := range utxos {
+		resolvers = append(resolvers, &UtxoResolver{utxo: utxo, registry: r.config.Registry})
+	}
+
+	return resolvers, nil
+}
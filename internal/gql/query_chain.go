@@ -0,0 +1,167 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+)
+
+// requireChain returns an error naming the query when no chain provider has
+// been configured
+func (r *Resolver) requireChain(query string) error {
+	if r.config.Chain == nil {
+		return fmt.Errorf("%v requires db-sync or the indexer; set POSTGRES_HOST or INDEXER to enable", query)
+	}
+	return nil
+}
+
+type AddressTransactionsArgs struct {
+	Address string
+	First   int32
+	After   *string
+}
+
+func (r *Resolver) AddressTransactions(ctx context.Context, args AddressTransactionsArgs) ([]*ChainTxResolver, error) {
+	if err := r.requireChain("addressTransactions"); err != nil {
+		return nil, err
+	}
+
+	address, err := r.config.CLI.NormalizeAddress(args.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := r.config.Chain.AddressTransactions(ctx, address, int(args.First), StringValue(args.After))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := []*ChainTxResolver{}
+	for _, tx := range txs {
+		resolvers = append(resolvers, r.chainTx(tx))
+	}
+	return resolvers, nil
+}
+
+type BlockArgs struct {
+	Hash   *string
+	Number *int32
+}
+
+func (r *Resolver) Block(ctx context.Context, args BlockArgs) (*BlockResolver, error) {
+	if err := r.requireChain("block"); err != nil {
+		return nil, err
+	}
+
+	var (
+		block chain.Block
+		err   error
+	)
+	switch {
+	case args.Hash != nil && args.Number != nil:
+		return nil, fmt.Errorf("block accepts either hash or number, not both")
+	case args.Hash != nil:
+		block, err = r.config.Chain.Block(ctx, *args.Hash)
+	case args.Number != nil:
+		block, err = r.config.Chain.BlockByNumber(ctx, *args.Number)
+	default:
+		return nil, fmt.Errorf("block requires either hash or number")
+	}
+	if errors.Is(err, chain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockResolver{block: block, resolver: r}, nil
+}
+
+type BlocksArgs struct {
+	First int32
+	After *string
+}
+
+func (r *Resolver) Blocks(ctx context.Context, args BlocksArgs) ([]*BlockResolver, error) {
+	if err := r.requireChain("blocks"); err != nil {
+		return nil, err
+	}
+
+	blocks, err := r.config.Chain.Blocks(ctx, int(args.First), StringValue(args.After))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := []*BlockResolver{}
+	for _, block := range blocks {
+		resolvers = append(resolvers, &BlockResolver{block: block, resolver: r})
+	}
+	return resolvers, nil
+}
+
+type TransactionArgs struct {
+	Id string
+}
+
+func (r *Resolver) Transaction(ctx context.Context, args TransactionArgs) (*ChainTxResolver, error) {
+	if err := r.requireChain("transaction"); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.config.Chain.Transaction(ctx, args.Id)
+	if errors.Is(err, chain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := r.chainTx(tx)
+	resolver.detail = &tx
+	return resolver, nil
+}
+
+type StakeHistoryArgs struct {
+	StakeAddress string
+}
+
+func (r *Resolver) StakeHistory(ctx context.Context, args StakeHistoryArgs) ([]*StakeEventResolver, error) {
+	if err := r.requireChain("stakeHistory"); err != nil {
+		return nil, err
+	}
+
+	events, err := r.config.Chain.StakeHistory(ctx, args.StakeAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := []*StakeEventResolver{}
+	for _, event := range events {
+		resolvers = append(resolvers, &StakeEventResolver{event: event})
+	}
+	return resolvers, nil
+}
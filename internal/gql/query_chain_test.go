@@ -0,0 +1,114 @@
+package gql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+	"github.com/tj/assert"
+)
+
+type ChainMock struct {
+	chain.Provider
+	blocks []chain.Block
+	txs    []chain.Tx
+	loaded int
+}
+
+func (c *ChainMock) BlockByNumber(_ context.Context, number int32) (chain.Block, error) {
+	for _, block := range c.blocks {
+		if block.Number == number {
+			return block, nil
+		}
+	}
+	return chain.Block{}, chain.ErrNotFound
+}
+
+func (c *ChainMock) BlockTransactions(_ context.Context, hash string) ([]chain.Tx, error) {
+	var txs []chain.Tx
+	for _, tx := range c.txs {
+		if tx.BlockHash == hash {
+			txs = append(txs, chain.Tx{ID: tx.ID, BlockHash: tx.BlockHash})
+		}
+	}
+	return txs, nil
+}
+
+func (c *ChainMock) Transaction(_ context.Context, id string) (chain.Tx, error) {
+	for _, tx := range c.txs {
+		if tx.ID == id {
+			c.loaded++
+			return tx, nil
+		}
+	}
+	return chain.Tx{}, chain.ErrNotFound
+}
+
+func TestResolver_Block(t *testing.T) {
+	ctx := context.Background()
+	mock := &ChainMock{
+		blocks: []chain.Block{{Hash: "03", Number: 3, TxCount: 1}},
+		txs: []chain.Tx{
+			{
+				ID:           "bb",
+				BlockHash:    "03",
+				Inputs:       []chain.Input{{TxID: "aa", Index: 1}},
+				Outputs:      []cardano.TxOutput{{Address: "addr_test1bob", Value: "2000000"}},
+				Metadata:     []byte(`{"674":"hello"}`),
+				Certificates: []chain.Certificate{{Kind: chain.StakeDelegation, Pool: "pool1pool"}},
+			},
+		},
+	}
+	resolver := &Resolver{config: Config{CLI: &Mock{}, Chain: mock}}
+
+	number := int32(3)
+	block, err := resolver.Block(ctx, BlockArgs{Number: &number})
+	assert.Nil(t, err)
+	assert.Equal(t, "03", block.Hash())
+
+	txs, err := block.Transactions(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, txs, 1)
+
+	// details are loaded once on demand
+	inputs, err := txs[0].Inputs(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, inputs, 1)
+	assert.Nil(t, inputs[0].Address())
+	outputs, err := txs[0].Outputs(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "addr_test1bob", outputs[0].Address())
+	certificates, err := txs[0].Certificates(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "DELEGATION", certificates[0].Kind())
+	assert.Equal(t, 1, mock.loaded)
+
+	number = 4
+	block, err = resolver.Block(ctx, BlockArgs{Number: &number})
+	assert.Nil(t, err)
+	assert.Nil(t, block)
+
+	_, err = resolver.Block(ctx, BlockArgs{})
+	assert.NotNil(t, err)
+}
+
+func TestResolver_Transaction(t *testing.T) {
+	ctx := context.Background()
+	mock := &ChainMock{txs: []chain.Tx{{ID: "bb", Metadata: []byte(`{"674":"hello"}`)}}}
+	resolver := &Resolver{config: Config{CLI: &Mock{}, Chain: mock}}
+
+	tx, err := resolver.Transaction(ctx, TransactionArgs{Id: "bb"})
+	assert.Nil(t, err)
+	metadata, err := tx.Metadata(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"674":"hello"}`, *metadata)
+	assert.Equal(t, 1, mock.loaded)
+
+	tx, err = resolver.Transaction(ctx, TransactionArgs{Id: "ff"})
+	assert.Nil(t, err)
+	assert.Nil(t, tx)
+
+	_, err = (&Resolver{}).Transaction(ctx, TransactionArgs{Id: "bb"})
+	assert.NotNil(t, err)
+}
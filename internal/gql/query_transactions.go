@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/history"
+)
+
+type TransactionsArgs struct {
+	Wallet *string
+	Kind   *string
+	First  int32
+	After  *string
+}
+
+func (r *Resolver) Transactions(args TransactionsArgs) (*TransactionConnectionResolver, error) {
+	if r.config.History == nil {
+		return nil, fmt.Errorf("failed to find transactions: transaction history not enabled")
+	}
+
+	query := history.Query{
+		First: int(args.First),
+		After: StringValue(args.After),
+	}
+	if args.Kind != nil {
+		query.Kind = cardano.TxKind(strings.ToLower(*args.Kind))
+	}
+	if args.Wallet != nil {
+		address, err := r.config.CLI.NormalizeAddress(*args.Wallet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find transactions: %w", err)
+		}
+		query.Wallet = address
+	}
+
+	records, hasNext, err := r.config.History.Find(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionConnectionResolver{records: records, hasNext: hasNext}, nil
+}
@@ -0,0 +1,48 @@
+package gql
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/history"
+	"github.com/tj/assert"
+)
+
+func TestResolver_Transactions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := history.Open(dir)
+	assert.Nil(t, err)
+	defer store.Close()
+
+	for _, id := range []string{"a", "b", "c"} {
+		err := store.RecordTx(context.Background(), cardano.SubmittedTx{
+			Tx:        cardano.Tx{ID: id, Outputs: []cardano.TxOutput{{Address: "wallet"}}},
+			Kind:      cardano.TxKindMint,
+			Submitted: time.Now(),
+		})
+		assert.Nil(t, err)
+	}
+
+	resolver := Resolver{config: Config{CLI: &Mock{}, History: store}}
+	connection, err := resolver.Transactions(TransactionsArgs{
+		Wallet: String("WALLET"),
+		Kind:   String("MINT"),
+		First:  2,
+	})
+	assert.Nil(t, err)
+
+	edges := connection.Edges()
+	assert.Len(t, edges, 2)
+	assert.Equal(t, "c", edges[0].Node().Id())
+	assert.Equal(t, "MINT", edges[0].Node().Kind())
+	assert.Nil(t, edges[0].Node().Slot())
+	assert.True(t, connection.PageInfo().HasNextPage())
+	assert.Equal(t, "b", *connection.PageInfo().EndCursor())
+}
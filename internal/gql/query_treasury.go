@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/faucet"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/history"
+)
+
+// Treasury checks the treasury, updating the health reported by the monitor
+func (r *Resolver) Treasury(ctx context.Context) (*TreasuryResolver, error) {
+	monitor := r.config.Monitor
+	if monitor == nil {
+		m, err := faucet.NewMonitor(r.config.CLI, "")
+		if err != nil {
+			return nil, err
+		}
+		monitor = m
+	}
+
+	health, err := monitor.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &TreasuryResolver{health: health, history: r.config.History}, nil
+}
+
+type TreasuryResolver struct {
+	health  faucet.Health
+	history *history.Store
+}
+
+func (t *TreasuryResolver) Status() *TreasuryStatusResolver {
+	return &TreasuryStatusResolver{status: t.health.Status}
+}
+func (t *TreasuryResolver) LowWater() *string { return optionalString(t.health.LowWater) }
+func (t *TreasuryResolver) Low() bool         { return t.health.Low }
+
+type TreasuryRecentPayoutsArgs struct {
+	First int32
+}
+
+func (t *TreasuryResolver) RecentPayouts(args TreasuryRecentPayoutsArgs) ([]*TransactionResolver, error) {
+	resolvers := []*TransactionResolver{}
+	if t.history == nil {
+		return resolvers, nil
+	}
+
+	records, _, err := t.history.Find(history.Query{Kind: cardano.TxKindFund, First: int(args.First)})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		resolvers = append(resolvers, &TransactionResolver{record: record})
+	}
+	return resolvers, nil
+}
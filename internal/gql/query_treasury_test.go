@@ -0,0 +1,61 @@
+package gql
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/faucet"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/history"
+	"github.com/tj/assert"
+)
+
+func TestResolver_Treasury(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := history.Open(dir)
+	assert.Nil(t, err)
+	defer store.Close()
+
+	for _, kind := range []cardano.TxKind{cardano.TxKindFund, cardano.TxKindMint, cardano.TxKindFund} {
+		err := store.RecordTx(context.Background(), cardano.SubmittedTx{
+			Tx:        cardano.Tx{ID: string(kind) + time.Now().String()},
+			Kind:      kind,
+			Submitted: time.Now(),
+		})
+		assert.Nil(t, err)
+	}
+
+	mock := &TreasuryMock{}
+	monitor, err := faucet.NewMonitor(mock, "1000")
+	assert.Nil(t, err)
+
+	resolver := Resolver{config: Config{CLI: mock, History: store, Monitor: monitor}}
+	treasury, err := resolver.Treasury(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "100", treasury.Status().Lovelace())
+	assert.Equal(t, "1000", *treasury.LowWater())
+	assert.True(t, treasury.Low())
+	assert.False(t, monitor.Health().Healthy())
+
+	payouts, err := treasury.RecentPayouts(TreasuryRecentPayoutsArgs{First: 10})
+	assert.Nil(t, err)
+	assert.Len(t, payouts, 2)
+	assert.Equal(t, "FUND", payouts[0].Kind())
+
+	t.Run("without monitor", func(t *testing.T) {
+		resolver := Resolver{config: Config{CLI: mock}}
+		treasury, err := resolver.Treasury(context.Background())
+		assert.Nil(t, err)
+		assert.Nil(t, treasury.LowWater())
+
+		payouts, err := treasury.RecentPayouts(TreasuryRecentPayoutsArgs{First: 10})
+		assert.Nil(t, err)
+		assert.Len(t, payouts, 0)
+	})
+}
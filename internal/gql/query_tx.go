@@ -29,10 +29,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"time"
-)
 
-const delay = time.Millisecond * 1500
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+)
 
 type TxFeeArgs struct {
 	Raw       string
@@ -60,3 +59,27 @@ func (r *Resolver) TxFee(ctx context.Context, args TxFeeArgs) (string, error) {
 
 	return r.config.CLI.MinFee(ctx, f.Name(), args.TxIn, args.TxOut, args.Witnesses)
 }
+
+type TxInspectArgs struct {
+	Body string
+}
+
+// TxInspect decodes a base64 encoded raw or signed transaction
+func (r *Resolver) TxInspect(args TxInspectArgs) (*TxResolver, error) {
+	data, err := base64.StdEncoding.DecodeString(args.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect tx: unable to base64 decode string: %w", err)
+	}
+
+	tx, err := cardano.ParseTx(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect tx: %w", err)
+	}
+
+	return &TxResolver{
+		body: args.Body,
+		id:   tx.ID,
+		raw:  data,
+		tx:   tx,
+	}, nil
+}
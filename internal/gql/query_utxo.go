@@ -23,8 +23,6 @@
 package gql
 
 import (
-	"strings"
-
 	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
 )
 
@@ -52,7 +50,7 @@ func (r *Resolver) Utxos(args UtxosArgs) ([]*UtxoResolver, error) {
 
 	var resolvers []*UtxoResolver
 	for _, utxo := range utxos {
-		resolvers = append(resolvers, &UtxoResolver{utxo: utxo})
+		resolvers = append(resolvers, &UtxoResolver{utxo: utxo, registry: r.config.Registry})
 	}
 
 	return resolvers, nil
@@ -62,23 +60,18 @@ func boolValue(b *bool) bool {
 	return b != nil && *b
 }
 
+// onlyAssetId excludes utxos that do not hold the asset identified by either
+// policyId.assetName (hex or utf-8 asset name) or the CIP-14 fingerprint, asset1...
 func onlyAssetId(s *string) func(utxo cardano.Utxo) bool {
 	if s == nil {
 		return func(utxo cardano.Utxo) bool { return false }
 	}
 
-	parts := strings.SplitN(*s, ".", 2)
-	if len(parts) != 2 {
-		return func(utxo cardano.Utxo) bool { return false }
-	}
-
-	policyId, assetName := parts[0], parts[1]
+	match := cardano.MatchAsset(*s)
 	return func(utxo cardano.Utxo) bool {
 		for _, token := range utxo.Tokens {
-			if asset := token.Asset; asset != nil {
-				if asset.AssetName == assetName && asset.PolicyId == policyId {
-					return false
-				}
+			if match(token.Asset) {
+				return false
 			}
 		}
 		return true
@@ -26,9 +26,15 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"math/big"
 	"net/http"
+	"time"
 
 	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/faucet"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/history"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/registry"
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 )
@@ -37,13 +43,19 @@ import (
 var textSchema string
 
 type Cardano interface {
+	AwaitTx(ctx context.Context, txID string, addresses ...string) (utxos cardano.Utxos, err error)
 	Build(opts ...cardano.BuildOption) ([]byte, error)
+	CreatePolicy(ctx context.Context, wallet string, before int32) (policy cardano.Policy, err error)
 	CreateWallet(ctx context.Context, initialFunds, name string) (wallet string, err error)
 	RegisterStake(ctx context.Context, address string) (tx cardano.Tx, err error)
 	Delegate(ctx context.Context, address string) (tx cardano.Tx, err error)
 	DataDir() string
+	FindAllPolicies(wallet string) ([]cardano.Policy, error)
 	FindAllWallets(query string) ([]string, error)
-	FundWallet(ctx context.Context, address, quantity string) (tx cardano.Tx, err error)
+	FindPolicy(policyID string) (cardano.Policy, error)
+	FundWallet(ctx context.Context, address, quantity string, opts ...cardano.BuildOption) (tx cardano.Tx, err error)
+	FundAssetsLovelace(ctx context.Context, address, quantity string, assets []cardano.AssetQuantity) (lovelace string, err error)
+	FundWalletAssets(ctx context.Context, address, quantity string, assets []cardano.AssetQuantity, opts ...cardano.BuildOption) (tx cardano.Tx, err error)
 	KeyHash(ctx context.Context, wallet string) (keyHash string, err error)
 	MinFee(ctx context.Context, filename string, txIn, txOut, witnesses int32) (fee string, err error)
 	NormalizeAddress(address string) (string, error)
@@ -52,22 +64,43 @@ type Cardano interface {
 	Sign(ctx context.Context, raw []byte, wallets ...string) (data []byte, err error)
 	Submit(ctx context.Context, signed []byte) (err error)
 	Utxos(address string, excludes ...func(cardano.Utxo) bool) (utxos cardano.Utxos, err error)
+	UtxoSet() (utxos cardano.Utxos, err error)
+	GenesisKeys(dir string) ([]cardano.GenesisKey, error)
+	SweepGenesis(ctx context.Context, dir string) (tx cardano.Tx, err error)
+	ConsolidateTreasury(ctx context.Context, max int) (tx cardano.Tx, n int, err error)
+	TreasuryStatus() (cardano.TreasuryStatus, error)
 	Version() (version cardano.Version, err error)
 }
 
 type Config struct {
-	Built   string
-	CLI     Cardano
-	Version string
+	AdminToken           string // AdminToken optionally enables admin mutations for requests bearing it
+	Built                string
+	Chain                chain.Provider // Chain optionally serves historical queries e.g. from db-sync
+	CLI                  Cardano
+	Faucet               *faucet.Faucet     // Faucet optionally batches treasury payouts
+	GenesisDir           string             // GenesisDir optionally holds the genesis utxo keys swept into the treasury
+	History              *history.Store     // History optionally records submitted txs
+	Limiter              *faucet.Limiter    // Limiter optionally enforces faucet quotas
+	MaxRecipientLovelace *big.Int           // MaxRecipientLovelace optionally overrides the maximum lovelace per mint recipient
+	Monitor              *faucet.Monitor    // Monitor optionally tracks the treasury low-water threshold
+	Network              cardano.Network    // Network describes the network the server targets
+	Networks             []string           // Networks optionally names every network served, the default first
+	PollInterval         time.Duration      // PollInterval is how often the tip is polled for subscriptions
+	Registry             *registry.Registry // Registry holds optional off-chain token metadata
+	Version              string
 }
 
 type Resolver struct {
 	config Config
+	poller *poller
 }
 
+// New returns a handler that serves graphql requests over http and
+// subscriptions over websockets (graphql-ws)
 func New(config Config) (http.Handler, error) {
 	resolver := &Resolver{
 		config: config,
+		poller: newPoller(config.CLI, config.PollInterval),
 	}
 
 	schema, err := graphql.ParseSchema(textSchema, resolver)
@@ -75,5 +108,8 @@ func New(config Config) (http.Handler, error) {
 		return nil, fmt.Errorf("unable to parse schema: %w", err)
 	}
 
-	return &relay.Handler{Schema: schema}, nil
+	return &handler{
+		schema: schema,
+		http:   &relay.Handler{Schema: schema},
+	}, nil
 }
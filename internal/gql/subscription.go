@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/savaki/zapctx"
+	"go.uber.org/zap"
+)
+
+// TipChanged emits the current tip and then the tip each time a block lands
+func (r *Resolver) TipChanged(ctx context.Context) (<-chan *TipResolver, error) {
+	tips, unsubscribe := r.poller.subscribe()
+
+	ch := make(chan *TipResolver)
+	go func() {
+		defer close(ch)
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tip := <-tips:
+				select {
+				case <-ctx.Done():
+					return
+				case ch <- &TipResolver{tip: tip}:
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+type UtxosChangedArgs struct {
+	Address string
+}
+
+// UtxosChanged emits the utxos held by the address and then the utxos each
+// time a block lands that changes them.  The address is only queried when the
+// tip changes.
+func (r *Resolver) UtxosChanged(ctx context.Context, args UtxosChangedArgs) (<-chan []*UtxoResolver, error) {
+	address := strings.TrimSpace(args.Address)
+	if address == "" {
+		return nil, fmt.Errorf("unable to subscribe to utxos: address required")
+	}
+	address, err := r.config.CLI.NormalizeAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to subscribe to utxos: %w", err)
+	}
+
+	tips, unsubscribe := r.poller.subscribe()
+
+	ch := make(chan []*UtxoResolver)
+	go func() {
+		defer close(ch)
+		defer unsubscribe()
+
+		var last string
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tips:
+			}
+
+			utxos, err := r.config.CLI.Utxos(address)
+			if err != nil {
+				zapctx.FromContext(ctx).Info("failed to query utxos", zap.String("address", address), zap.Error(err))
+				continue
+			}
+			if key := utxosKey(utxos); key == last {
+				continue
+			} else {
+				last = key
+			}
+
+			resolvers := []*UtxoResolver{}
+			for _, utxo := range utxos {
+				resolvers = append(resolvers, &UtxoResolver{utxo: utxo, registry: r.config.Registry})
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- resolvers:
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// utxosKey returns a key that identifies the set of utxos
+func utxosKey(utxos cardano.Utxos) string {
+	var keys []string
+	for _, utxo := range utxos {
+		keys = append(keys, utxo.TxIn())
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
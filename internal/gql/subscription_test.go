@@ -0,0 +1,120 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/gorilla/websocket"
+	"github.com/tj/assert"
+)
+
+type TipMock struct {
+	Cardano
+	mutex sync.Mutex
+	block int32 // block returned by #QueryTip
+	calls int   // calls to #QueryTip
+}
+
+func (m *TipMock) QueryTip() (*cardano.Tip, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls++
+	return &cardano.Tip{Block: m.block, Hash: strings.Repeat("a", int(m.block))}, nil
+}
+
+func (m *TipMock) setBlock(block int32) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.block = block
+}
+
+func TestPoller(t *testing.T) {
+	mock := &TipMock{block: 1}
+	p := newPoller(mock, 10*time.Millisecond)
+
+	a, unsubscribeA := p.subscribe()
+	b, unsubscribeB := p.subscribe()
+
+	assert.EqualValues(t, 1, (<-a).Block)
+	assert.EqualValues(t, 1, (<-b).Block)
+
+	mock.setBlock(2)
+	assert.EqualValues(t, 2, (<-a).Block)
+	assert.EqualValues(t, 2, (<-b).Block)
+
+	unsubscribeA()
+	unsubscribeB()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	assert.Nil(t, p.cancel)
+	assert.Len(t, p.subs, 0)
+}
+
+func TestTipChanged(t *testing.T) {
+	mock := &TipMock{block: 1}
+	handler, err := New(Config{CLI: mock, PollInterval: 10 * time.Millisecond})
+	assert.Nil(t, err)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-ws"}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	assert.Nil(t, conn.WriteJSON(wsMessage{Type: gqlConnectionInit}))
+	assert.Nil(t, conn.WriteJSON(wsMessage{
+		ID:      "1",
+		Type:    gqlStart,
+		Payload: json.RawMessage(`{"query":"subscription { tipChanged { block } }"}`),
+	}))
+
+	// next returns the block of the next data message
+	next := func() int32 {
+		for {
+			var msg wsMessage
+			assert.Nil(t, conn.ReadJSON(&msg))
+			if msg.Type != gqlData {
+				continue
+			}
+			assert.Equal(t, "1", msg.ID)
+
+			var payload struct {
+				Data struct {
+					TipChanged struct {
+						Block int32
+					}
+				}
+			}
+			assert.Nil(t, json.Unmarshal(msg.Payload, &payload))
+			return payload.Data.TipChanged.Block
+		}
+	}
+
+	assert.EqualValues(t, 1, next())
+	mock.setBlock(2)
+	assert.EqualValues(t, 2, next())
+}
+
+func TestUtxosChanged_InvalidAddress(t *testing.T) {
+	var (
+		mock     = &Mock{}
+		resolver = &Resolver{config: Config{CLI: mock}, poller: newPoller(mock, time.Second)}
+	)
+
+	_, err := resolver.UtxosChanged(context.Background(), UtxosChangedArgs{Address: "invalid"})
+	assert.NotNil(t, err)
+
+	resolver.poller.mutex.Lock()
+	defer resolver.poller.mutex.Unlock()
+	assert.Len(t, resolver.poller.subs, 0)
+}
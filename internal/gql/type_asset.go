@@ -28,9 +28,22 @@ type AssetResolver struct {
 	asset *cardano.Asset
 }
 
-func (a *AssetResolver) AssetId() string   { return a.asset.ID() }
-func (a *AssetResolver) AssetName() string { return a.asset.AssetName }
-func (a *AssetResolver) Name() *string     { return String(a.asset.Name) }
-func (a *AssetResolver) PolicyId() string  { return a.asset.PolicyId }
-func (a *AssetResolver) Ticker() *string   { return String(a.asset.Ticker) }
-func (a *AssetResolver) Url() *string      { return String(a.asset.Url) }
+func (a *AssetResolver) AssetId() string      { return a.asset.ID() }
+func (a *AssetResolver) AssetName() string    { return a.asset.DisplayName() }
+func (a *AssetResolver) AssetNameHex() string { return a.asset.AssetName }
+func (a *AssetResolver) Fingerprint() string  { return a.asset.Fingerprint() }
+func (a *AssetResolver) Description() *string { return String(a.asset.Description) }
+func (a *AssetResolver) Logo() *string        { return String(a.asset.Logo) }
+func (a *AssetResolver) Name() *string        { return String(a.asset.Name) }
+func (a *AssetResolver) PolicyId() string     { return a.asset.PolicyId }
+func (a *AssetResolver) Ticker() *string      { return String(a.asset.Ticker) }
+func (a *AssetResolver) Url() *string         { return String(a.asset.Url) }
+
+func (a *AssetResolver) Decimals() *int32 { return a.asset.Decimals }
+
+func (a *AssetResolver) AssetNameUtf8() *string {
+	if s, ok := a.asset.AssetNameUTF8(); ok {
+		return &s
+	}
+	return nil
+}
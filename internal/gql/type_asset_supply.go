@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+)
+
+// defaultHoldersFirst is the default number of holders returned per page
+const defaultHoldersFirst = 20
+
+type AssetSupplyResolver struct {
+	asset    *cardano.Asset
+	holders  []chain.Holder
+	mints    []chain.Mint
+	resolver *Resolver
+}
+
+func (a *AssetSupplyResolver) Asset() *AssetResolver {
+	return &AssetResolver{asset: a.resolver.config.Registry.Apply(a.asset)}
+}
+
+// Minted returns the total quantity minted
+func (a *AssetSupplyResolver) Minted() string {
+	total := big.NewInt(0)
+	for _, mint := range a.mints {
+		if v, ok := big.NewInt(0).SetString(mint.Quantity, 10); ok && v.Sign() > 0 {
+			total.Add(total, v)
+		}
+	}
+	return total.String()
+}
+
+// Burned returns the total quantity burned
+func (a *AssetSupplyResolver) Burned() string {
+	total := big.NewInt(0)
+	for _, mint := range a.mints {
+		if v, ok := big.NewInt(0).SetString(mint.Quantity, 10); ok && v.Sign() < 0 {
+			total.Sub(total, v)
+		}
+	}
+	return total.String()
+}
+
+// Circulating returns the quantity held in the utxo set
+func (a *AssetSupplyResolver) Circulating() string {
+	total := big.NewInt(0)
+	for _, holder := range a.holders {
+		if v, ok := big.NewInt(0).SetString(holder.Quantity, 10); ok {
+			total.Add(total, v)
+		}
+	}
+	return total.String()
+}
+
+func (a *AssetSupplyResolver) Mints() []*AssetMintResolver {
+	resolvers := []*AssetMintResolver{}
+	for _, mint := range a.mints {
+		resolvers = append(resolvers, &AssetMintResolver{mint: mint})
+	}
+	return resolvers
+}
+
+type AssetHoldersArgs struct {
+	First int32
+	After *string
+}
+
+// Holders returns a page of holders, largest first.  After holds the address of
+// the last holder of the previous page.
+func (a *AssetSupplyResolver) Holders(args AssetHoldersArgs) ([]*AssetHolderResolver, error) {
+	holders := a.holders
+	if after := StringValue(args.After); after != "" {
+		found := false
+		for i, holder := range holders {
+			if holder.Address == after {
+				holders, found = holders[i+1:], true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown cursor, %v", after)
+		}
+	}
+
+	first := int(args.First)
+	if first <= 0 {
+		first = defaultHoldersFirst
+	}
+
+	resolvers := []*AssetHolderResolver{}
+	for _, holder := range holders {
+		if len(resolvers) == first {
+			break
+		}
+		resolvers = append(resolvers, &AssetHolderResolver{holder: holder})
+	}
+	return resolvers, nil
+}
+
+type AssetHolderResolver struct {
+	holder chain.Holder
+}
+
+func (a *AssetHolderResolver) Address() string  { return a.holder.Address }
+func (a *AssetHolderResolver) Quantity() string { return a.holder.Quantity }
+
+type AssetMintResolver struct {
+	mint chain.Mint
+}
+
+func (a *AssetMintResolver) TxId() string     { return a.mint.TxID }
+func (a *AssetMintResolver) Quantity() string { return a.mint.Quantity }
+func (a *AssetMintResolver) Time() string     { return a.mint.Time.UTC().Format(time.RFC3339) }
+
+func (a *AssetMintResolver) Slot() *int32 {
+	if a.mint.Slot == 0 {
+		return nil
+	}
+	return &a.mint.Slot
+}
@@ -0,0 +1,206 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+)
+
+type BlockResolver struct {
+	block    chain.Block
+	resolver *Resolver
+}
+
+func (b *BlockResolver) Hash() string   { return b.block.Hash }
+func (b *BlockResolver) Number() int32  { return b.block.Number }
+func (b *BlockResolver) Slot() int32    { return b.block.Slot }
+func (b *BlockResolver) Epoch() int32   { return b.block.Epoch }
+func (b *BlockResolver) Time() string   { return b.block.Time.UTC().Format(time.RFC3339) }
+func (b *BlockResolver) Size() int32    { return b.block.Size }
+func (b *BlockResolver) TxCount() int32 { return b.block.TxCount }
+
+func (b *BlockResolver) Transactions(ctx context.Context) ([]*ChainTxResolver, error) {
+	txs, err := b.resolver.config.Chain.BlockTransactions(ctx, b.block.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := []*ChainTxResolver{}
+	for _, tx := range txs {
+		resolvers = append(resolvers, b.resolver.chainTx(tx))
+	}
+	return resolvers, nil
+}
+
+// ChainTxResolver resolves a tx summary, loading the inputs, outputs,
+// metadata, and certificates from the chain provider on first use
+type ChainTxResolver struct {
+	tx       chain.Tx
+	resolver *Resolver
+
+	mutex  sync.Mutex
+	detail *chain.Tx
+}
+
+func (r *Resolver) chainTx(tx chain.Tx) *ChainTxResolver {
+	return &ChainTxResolver{tx: tx, resolver: r}
+}
+
+func (c *ChainTxResolver) Id() string        { return c.tx.ID }
+func (c *ChainTxResolver) BlockHash() string { return c.tx.BlockHash }
+func (c *ChainTxResolver) Block() int32      { return c.tx.Block }
+func (c *ChainTxResolver) Slot() int32       { return c.tx.Slot }
+func (c *ChainTxResolver) Epoch() int32      { return c.tx.Epoch }
+func (c *ChainTxResolver) Time() string      { return c.tx.Time.UTC().Format(time.RFC3339) }
+func (c *ChainTxResolver) Fee() *string      { return String(c.tx.Fee) }
+func (c *ChainTxResolver) Deposit() *string  { return String(c.tx.Deposit) }
+func (c *ChainTxResolver) Size() int32       { return c.tx.Size }
+
+func (c *ChainTxResolver) load(ctx context.Context) (*chain.Tx, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.detail == nil {
+		tx, err := c.resolver.config.Chain.Transaction(ctx, c.tx.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.detail = &tx
+	}
+	return c.detail, nil
+}
+
+func (c *ChainTxResolver) Inputs(ctx context.Context) ([]*ChainTxInputResolver, error) {
+	tx, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := []*ChainTxInputResolver{}
+	for _, input := range tx.Inputs {
+		resolvers = append(resolvers, &ChainTxInputResolver{input: input})
+	}
+	return resolvers, nil
+}
+
+func (c *ChainTxResolver) Outputs(ctx context.Context) ([]*TxOutputResolver, error) {
+	tx, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := []*TxOutputResolver{}
+	for i, output := range tx.Outputs {
+		resolvers = append(resolvers, &TxOutputResolver{
+			output:   output,
+			index:    int32(i),
+			registry: c.resolver.config.Registry,
+		})
+	}
+	return resolvers, nil
+}
+
+func (c *ChainTxResolver) Metadata(ctx context.Context) (*string, error) {
+	tx, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.Metadata) == 0 {
+		return nil, nil
+	}
+	return String(string(tx.Metadata)), nil
+}
+
+func (c *ChainTxResolver) Certificates(ctx context.Context) ([]*CertificateResolver, error) {
+	tx, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := []*CertificateResolver{}
+	for _, certificate := range tx.Certificates {
+		resolvers = append(resolvers, &CertificateResolver{certificate: certificate})
+	}
+	return resolvers, nil
+}
+
+type ChainTxInputResolver struct {
+	input chain.Input
+}
+
+func (c *ChainTxInputResolver) TxId() string { return c.input.TxID }
+func (c *ChainTxInputResolver) Index() int32 { return c.input.Index }
+func (c *ChainTxInputResolver) Address() *string {
+	if c.input.Address == "" {
+		return nil
+	}
+	return &c.input.Address
+}
+func (c *ChainTxInputResolver) Value() *string {
+	if c.input.Value == "" {
+		return nil
+	}
+	return &c.input.Value
+}
+
+type CertificateResolver struct {
+	certificate chain.Certificate
+}
+
+func (c *CertificateResolver) Kind() string { return strings.ToUpper(c.certificate.Kind) }
+func (c *CertificateResolver) Epoch() int32 { return c.certificate.Epoch }
+
+func (c *CertificateResolver) StakeAddress() *string {
+	if c.certificate.StakeAddress == "" {
+		return nil
+	}
+	return &c.certificate.StakeAddress
+}
+
+func (c *CertificateResolver) Pool() *string {
+	if c.certificate.Pool == "" {
+		return nil
+	}
+	return &c.certificate.Pool
+}
+
+type StakeEventResolver struct {
+	event chain.StakeEvent
+}
+
+func (s *StakeEventResolver) Kind() string { return strings.ToUpper(s.event.Kind) }
+func (s *StakeEventResolver) Epoch() int32 { return s.event.Epoch }
+func (s *StakeEventResolver) TxId() string { return s.event.TxID }
+func (s *StakeEventResolver) Slot() int32  { return s.event.Slot }
+
+func (s *StakeEventResolver) Pool() *string {
+	if s.event.Pool == "" {
+		return nil
+	}
+	return &s.event.Pool
+}
@@ -0,0 +1,54 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+)
+
+type PolicyResolver struct {
+	policy cardano.Policy
+}
+
+func (p *PolicyResolver) Before() *int32 {
+	if p.policy.Before <= 0 {
+		return nil
+	}
+	return &p.policy.Before
+}
+
+func (p *PolicyResolver) KeyHash() string  { return p.policy.KeyHash }
+func (p *PolicyResolver) PolicyId() string { return p.policy.ID }
+func (p *PolicyResolver) Wallet() string   { return p.policy.Wallet }
+
+func (p *PolicyResolver) Script() (string, error) {
+	data, err := ioutil.ReadFile(p.policy.ScriptFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to read policy script, %v: %w", p.policy.ID, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
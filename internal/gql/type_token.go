@@ -22,11 +22,18 @@
 
 package gql
 
-import "github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+import (
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/registry"
+)
 
 type TokenResolver struct {
-	token cardano.Token
+	token    cardano.Token
+	registry *registry.Registry
 }
 
-func (t *TokenResolver) Asset() *AssetResolver { return &AssetResolver{asset: t.token.Asset} }
-func (t *TokenResolver) Quantity() string      { return t.token.Quantity }
+func (t *TokenResolver) Asset() *AssetResolver {
+	return &AssetResolver{asset: t.registry.Apply(t.token.Asset)}
+}
+
+func (t *TokenResolver) Quantity() string { return t.token.Quantity }
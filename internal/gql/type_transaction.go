@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/history"
+)
+
+type TransactionConnectionResolver struct {
+	records []history.Record
+	hasNext bool
+}
+
+func (t *TransactionConnectionResolver) Edges() []*TransactionEdgeResolver {
+	edges := []*TransactionEdgeResolver{}
+	for _, record := range t.records {
+		edges = append(edges, &TransactionEdgeResolver{record: record})
+	}
+	return edges
+}
+
+func (t *TransactionConnectionResolver) PageInfo() *PageInfoResolver {
+	info := &PageInfoResolver{hasNext: t.hasNext}
+	if n := len(t.records); n > 0 {
+		info.endCursor = &t.records[n-1].ID
+	}
+	return info
+}
+
+type TransactionEdgeResolver struct {
+	record history.Record
+}
+
+func (t *TransactionEdgeResolver) Cursor() string { return t.record.ID }
+func (t *TransactionEdgeResolver) Node() *TransactionResolver {
+	return &TransactionResolver{record: t.record}
+}
+
+type PageInfoResolver struct {
+	endCursor *string
+	hasNext   bool
+}
+
+func (p *PageInfoResolver) EndCursor() *string { return p.endCursor }
+func (p *PageInfoResolver) HasNextPage() bool  { return p.hasNext }
+
+type TransactionResolver struct {
+	record history.Record
+}
+
+func (t *TransactionResolver) Body() string {
+	return base64.StdEncoding.EncodeToString(t.record.Body)
+}
+func (t *TransactionResolver) Id() string        { return t.record.ID }
+func (t *TransactionResolver) Kind() string      { return strings.ToUpper(string(t.record.Kind)) }
+func (t *TransactionResolver) Wallets() []string { return t.record.Wallets }
+func (t *TransactionResolver) Submitted() string { return t.record.Submitted.Format(time.RFC3339) }
+
+func (t *TransactionResolver) Slot() *int32 {
+	if t.record.Pending() {
+		return nil
+	}
+	return &t.record.Slot
+}
@@ -22,11 +22,35 @@
 
 package gql
 
+import "github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+
 type TxResolver struct {
 	body string
 	id   string
 	raw  []byte
+	tx   cardano.Tx
 }
 
 func (t *TxResolver) Body() string { return t.body }
 func (t *TxResolver) Id() string   { return t.id }
+
+type TxMetadataArgs struct {
+	Schema string
+}
+
+func (t *TxResolver) Metadata(args TxMetadataArgs) (*string, error) {
+	if len(t.tx.Metadata) == 0 {
+		return nil, nil
+	}
+
+	schema, err := metadataSchema(args.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := t.tx.Metadata.JSON(schema)
+	if err != nil {
+		return nil, err
+	}
+	return String(string(data)), nil
+}
@@ -0,0 +1,120 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/registry"
+)
+
+// tx statuses
+const (
+	txStatusSubmitted = "SUBMITTED"
+	txStatusConfirmed = "CONFIRMED"
+)
+
+// TxResultResolver describes a tx submitted by a mutation.  Resolver is
+// embedded so clients that select Query fields from mutations keep working.
+type TxResultResolver struct {
+	*Resolver
+	tx cardano.Tx
+}
+
+func (r *Resolver) txResult(tx cardano.Tx) *TxResultResolver {
+	return &TxResultResolver{Resolver: r, tx: tx}
+}
+
+func (t *TxResultResolver) Id() string  { return t.tx.ID }
+func (t *TxResultResolver) Fee() string { return t.tx.Fee }
+
+func (t *TxResultResolver) Inputs() []*TxInputResolver {
+	resolvers := []*TxInputResolver{}
+	for _, input := range t.tx.Inputs {
+		parts := strings.SplitN(input, "#", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		index, _ := strconv.Atoi(parts[1])
+		resolvers = append(resolvers, &TxInputResolver{txID: parts[0], index: int32(index)})
+	}
+	return resolvers
+}
+
+func (t *TxResultResolver) Outputs() []*TxOutputResolver {
+	resolvers := []*TxOutputResolver{}
+	for i, output := range t.tx.Outputs {
+		resolvers = append(resolvers, &TxOutputResolver{
+			output:   output,
+			index:    int32(i),
+			registry: t.config.Registry,
+		})
+	}
+	return resolvers
+}
+
+// Status reports CONFIRMED once the tx history has observed the tx on chain
+func (t *TxResultResolver) Status() (string, error) {
+	if t.config.History == nil {
+		return txStatusSubmitted, nil
+	}
+
+	record, ok, err := t.config.History.Get(t.tx.ID)
+	if err != nil {
+		return "", err
+	}
+	if ok && !record.Pending() {
+		return txStatusConfirmed, nil
+	}
+	return txStatusSubmitted, nil
+}
+
+func (t *TxResultResolver) Query() *Resolver { return t.Resolver }
+
+type TxInputResolver struct {
+	txID  string
+	index int32
+}
+
+func (t *TxInputResolver) TxId() string { return t.txID }
+func (t *TxInputResolver) Index() int32 { return t.index }
+
+type TxOutputResolver struct {
+	output   cardano.TxOutput
+	index    int32
+	registry *registry.Registry
+}
+
+func (t *TxOutputResolver) Address() string { return t.output.Address }
+func (t *TxOutputResolver) Index() int32    { return t.index }
+func (t *TxOutputResolver) Value() string   { return t.output.Value }
+
+func (t *TxOutputResolver) Tokens() []*TokenResolver {
+	resolvers := []*TokenResolver{}
+	for _, token := range t.output.Tokens {
+		resolvers = append(resolvers, &TokenResolver{token: token, registry: t.registry})
+	}
+	return resolvers
+}
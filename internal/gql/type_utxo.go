@@ -22,10 +22,14 @@
 
 package gql
 
-import "github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+import (
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/registry"
+)
 
 type UtxoResolver struct {
-	utxo cardano.Utxo
+	utxo     cardano.Utxo
+	registry *registry.Registry
 }
 
 func (u *UtxoResolver) Address() string { return u.utxo.Address }
@@ -44,7 +48,7 @@ func (u *UtxoResolver) Value() string { return u.utxo.Value }
 func (u *UtxoResolver) Tokens() []*TokenResolver {
 	var resolvers []*TokenResolver
 	for _, token := range u.utxo.Tokens {
-		resolvers = append(resolvers, &TokenResolver{token: token})
+		resolvers = append(resolvers, &TokenResolver{token: token, registry: u.registry})
 	}
 	return resolvers
 }
@@ -42,3 +42,28 @@ func (v *VersionResolver) Built() string    { return v.config.Built }
 func (v *VersionResolver) Git() string      { return v.version.Git }
 func (v *VersionResolver) Revision() string { return v.version.Revision }
 func (v *VersionResolver) Version() string  { return v.config.Version }
+func (v *VersionResolver) Network() *NetworkResolver {
+	return &NetworkResolver{network: v.config.Network}
+}
+
+type NetworkResolver struct {
+	network cardano.Network
+}
+
+func (n *NetworkResolver) Name() string               { return n.network.Name }
+func (n *NetworkResolver) Mainnet() bool              { return n.network.Mainnet }
+func (n *NetworkResolver) Magic() string              { return n.network.Magic }
+func (n *NetworkResolver) Era() *string               { return optionalString(n.network.Era) }
+func (n *NetworkResolver) AddressPrefix() string      { return n.network.AddressPrefix() }
+func (n *NetworkResolver) StakeAddressPrefix() string { return n.network.StakeAddressPrefix() }
+func (n *NetworkResolver) ByronGenesis() *string      { return optionalString(n.network.ByronGenesis) }
+func (n *NetworkResolver) ShelleyGenesis() *string    { return optionalString(n.network.ShelleyGenesis) }
+func (n *NetworkResolver) AlonzoGenesis() *string     { return optionalString(n.network.AlonzoGenesis) }
+
+// optionalString returns nil for the empty string
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
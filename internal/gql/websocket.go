@@ -0,0 +1,241 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/savaki/zapctx"
+	"go.uber.org/zap"
+)
+
+// graphql-ws (subscriptions-transport-ws) message types
+const (
+	gqlConnectionInit      = "connection_init"
+	gqlConnectionAck       = "connection_ack"
+	gqlConnectionError     = "connection_error"
+	gqlConnectionKeepAlive = "ka"
+	gqlConnectionTerminate = "connection_terminate"
+	gqlStart               = "start"
+	gqlStop                = "stop"
+	gqlData                = "data"
+	gqlError               = "error"
+	gqlComplete            = "complete"
+)
+
+// keepAliveInterval is how often keep alive messages are sent to clients
+const keepAliveInterval = 15 * time.Second
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin:  func(r *http.Request) bool { return true }, // match the permissive cors policy
+	Subprotocols: []string{"graphql-ws"},
+}
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type wsStartPayload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// IsWebsocket returns true if the request asks to upgrade to a websocket
+func IsWebsocket(req *http.Request) bool {
+	return websocket.IsWebSocketUpgrade(req)
+}
+
+// handler serves graphql over http and, for upgrade requests, over websockets
+// using the graphql-ws protocol
+type handler struct {
+	schema *graphql.Schema
+	http   http.Handler
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !IsWebsocket(req) {
+		ctx := withAuthorization(req.Context(), req.Header.Get("Authorization"))
+		h.http.ServeHTTP(w, req.WithContext(ctx))
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return // upgrader has already replied to the client
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	c := &wsConn{
+		conn:       conn,
+		operations: map[string]context.CancelFunc{},
+	}
+	defer c.stopAll()
+
+	go c.keepAlive(ctx)
+
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				zapctx.FromContext(ctx).Info("websocket closed", zap.Error(err))
+			}
+			return
+		}
+
+		switch msg.Type {
+		case gqlConnectionInit:
+			c.write(wsMessage{Type: gqlConnectionAck})
+			c.write(wsMessage{Type: gqlConnectionKeepAlive})
+		case gqlStart:
+			var payload wsStartPayload
+			if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+				c.writeError(msg.ID, err)
+				continue
+			}
+			c.start(ctx, h.schema, msg.ID, payload)
+		case gqlStop:
+			c.stop(msg.ID)
+		case gqlConnectionTerminate:
+			return
+		default:
+			c.write(wsMessage{Type: gqlConnectionError, Payload: errorPayload("unknown message type, " + msg.Type)})
+		}
+	}
+}
+
+// wsConn holds the state of a single graphql-ws connection
+type wsConn struct {
+	conn *websocket.Conn
+
+	writeMutex sync.Mutex // writeMutex serializes writes to conn
+
+	mutex      sync.Mutex
+	operations map[string]context.CancelFunc // operations holds the active operations by id
+}
+
+// start executes the operation, streaming results to the client until the
+// operation completes or is stopped
+func (c *wsConn) start(ctx context.Context, schema *graphql.Schema, id string, payload wsStartPayload) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	c.mutex.Lock()
+	if prev, ok := c.operations[id]; ok {
+		prev()
+	}
+	c.operations[id] = cancel
+	c.mutex.Unlock()
+
+	responses, err := schema.Subscribe(ctx, payload.Query, payload.OperationName, payload.Variables)
+	if err != nil {
+		cancel()
+		c.writeError(id, err)
+		return
+	}
+
+	go func() {
+		defer cancel()
+
+		// drain responses until closed; graphql-go closes the channel once ctx is done
+		for resp := range responses {
+			if ctx.Err() != nil {
+				continue
+			}
+			data, err := json.Marshal(resp)
+			if err != nil {
+				c.writeError(id, err)
+				continue
+			}
+			c.write(wsMessage{ID: id, Type: gqlData, Payload: data})
+		}
+
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		if ctx.Err() != nil {
+			return // stopped by the client or the connection closed
+		}
+		delete(c.operations, id)
+		c.write(wsMessage{ID: id, Type: gqlComplete})
+	}()
+}
+
+func (c *wsConn) stop(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if cancel, ok := c.operations[id]; ok {
+		cancel()
+		delete(c.operations, id)
+	}
+}
+
+func (c *wsConn) stopAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for id, cancel := range c.operations {
+		cancel()
+		delete(c.operations, id)
+	}
+}
+
+func (c *wsConn) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.write(wsMessage{Type: gqlConnectionKeepAlive})
+		}
+	}
+}
+
+func (c *wsConn) write(msg wsMessage) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	_ = c.conn.WriteJSON(msg) // read loop notices broken connections
+}
+
+func (c *wsConn) writeError(id string, err error) {
+	c.write(wsMessage{ID: id, Type: gqlError, Payload: errorPayload(err.Error())})
+}
+
+func errorPayload(message string) json.RawMessage {
+	data, _ := json.Marshal(map[string]string{"message": message})
+	return data
+}
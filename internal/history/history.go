@@ -0,0 +1,463 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package history records the txs submitted by the toolkit in an embedded
+// bbolt database so they may be listed per wallet.  Txs are confirmed by
+// watching for their outputs to appear in the utxo set.
+package history
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+	"github.com/savaki/zapctx"
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+const (
+	filename = "history.db" // filename of the bbolt database within the history dir
+
+	// DefaultFirst is the default number of records returned by Find
+	DefaultFirst = 20
+
+	// DefaultWatchInterval is how often pending txs are checked for confirmation
+	DefaultWatchInterval = 5 * time.Second
+
+	// pendingTTL bounds how long a tx is checked for confirmation
+	pendingTTL = time.Hour
+)
+
+var (
+	bucketTxs = []byte("txs") // bucketTxs holds records keyed by sequence
+	bucketIDs = []byte("ids") // bucketIDs holds the sequence of each tx id
+)
+
+// Record describes a tx submitted by the toolkit
+type Record struct {
+	ID        string          `json:"id"`
+	Kind      cardano.TxKind  `json:"kind"`
+	Wallets   []string        `json:"wallets"`        // Wallets holds the addresses spent from or paid to by the tx
+	Inputs    []string        `json:"inputs"`         // Inputs holds the inputs consumed as hash#index
+	Outputs   []string        `json:"outputs"`        // Outputs holds the address of each output
+	Mint      []cardano.Token `json:"mint,omitempty"` // Mint holds the tokens minted, negative when burned
+	Body      []byte          `json:"body"`           // Body holds the signed tx envelope
+	Submitted time.Time       `json:"submitted"`
+	Slot      int32           `json:"slot,omitempty"` // Slot the tx was confirmed in; 0 while pending
+}
+
+// Pending returns true if the tx has not been confirmed
+func (r Record) Pending() bool {
+	return r.Slot == 0
+}
+
+// Query filters the records returned by Find
+type Query struct {
+	Wallet string         // Wallet optionally limits records to those involving the address
+	Kind   cardano.TxKind // Kind optionally limits records to the kind
+	First  int            // First is the maximum number of records to return
+	After  string         // After optionally holds the id of the last record of the previous page
+}
+
+// Chain provides the views of the chain needed to confirm txs
+type Chain interface {
+	QueryTip() (*cardano.Tip, error)
+	Utxos(address string, excludes ...func(cardano.Utxo) bool) (cardano.Utxos, error)
+}
+
+// Store persists tx records
+type Store struct {
+	db *bbolt.DB
+}
+
+// Open returns a store backed by a database within dir
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+
+	db, err := bbolt.Open(filepath.Join(dir, filename), 0644, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{bucketTxs, bucketIDs} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close the underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// RecordTx saves the submitted tx.  The wallets involved are the addresses paid
+// by the tx along with the addresses of any inputs created by recorded txs.
+func (s *Store) RecordTx(_ context.Context, submitted cardano.SubmittedTx) error {
+	record := Record{
+		ID:        submitted.ID,
+		Kind:      submitted.Kind,
+		Inputs:    submitted.Inputs,
+		Mint:      submitted.Mint,
+		Body:      submitted.Signed,
+		Submitted: submitted.Submitted.UTC(),
+	}
+	for _, output := range submitted.Outputs {
+		record.Outputs = append(record.Outputs, output.Address)
+	}
+
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		wallets := map[string]struct{}{}
+		for _, address := range record.Outputs {
+			wallets[address] = struct{}{}
+		}
+		for _, input := range record.Inputs {
+			if address, ok := inputAddress(tx, input); ok {
+				wallets[address] = struct{}{}
+			}
+		}
+		for address := range wallets {
+			record.Wallets = append(record.Wallets, address)
+		}
+		sort.Strings(record.Wallets)
+
+		ids, txs := tx.Bucket(bucketIDs), tx.Bucket(bucketTxs)
+		key := ids.Get([]byte(record.ID))
+		if key == nil {
+			seq, err := txs.NextSequence()
+			if err != nil {
+				return err
+			}
+			key = make([]byte, 8)
+			binary.BigEndian.PutUint64(key, seq)
+			if err := ids.Put([]byte(record.ID), key); err != nil {
+				return err
+			}
+		}
+		return put(txs, key, record)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record tx, %v: %w", record.ID, err)
+	}
+	return nil
+}
+
+// Get returns the record for the tx id, if any
+func (s *Store) Get(id string) (record Record, ok bool, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		key := tx.Bucket(bucketIDs).Get([]byte(id))
+		if key == nil {
+			return nil
+		}
+		ok = true
+		return json.Unmarshal(tx.Bucket(bucketTxs).Get(key), &record)
+	})
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to get tx, %v: %w", id, err)
+	}
+	return record, ok, nil
+}
+
+// Find returns the records matching the query, newest first, along with
+// whether more records follow
+func (s *Store) Find(query Query) (records []Record, hasNext bool, err error) {
+	first := query.First
+	if first <= 0 {
+		first = DefaultFirst
+	}
+
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket(bucketTxs).Cursor()
+
+		k, v := cursor.Last()
+		if query.After != "" {
+			key := tx.Bucket(bucketIDs).Get([]byte(query.After))
+			if key == nil {
+				return fmt.Errorf("unknown cursor, %v", query.After)
+			}
+			cursor.Seek(key)
+			k, v = cursor.Prev()
+		}
+
+		for ; k != nil; k, v = cursor.Prev() {
+			var record Record
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+			if !record.matches(query) {
+				continue
+			}
+			if len(records) == first {
+				hasNext = true
+				return nil
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find txs: %w", err)
+	}
+	return records, hasNext, nil
+}
+
+// AssetMints returns the recorded txs that minted or burned the asset, newest
+// first.  Only txs submitted by the toolkit are known to the store.
+func (s *Store) AssetMints(_ context.Context, policyID, assetNameHex string) ([]chain.Mint, error) {
+	var mints []chain.Mint
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket(bucketTxs).Cursor()
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			var record Record
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+			for _, token := range record.Mint {
+				if token.Asset == nil || token.Asset.PolicyId != policyID || token.Asset.AssetName != assetNameHex {
+					continue
+				}
+				mints = append(mints, chain.Mint{
+					TxID:     record.ID,
+					Slot:     record.Slot,
+					Time:     record.Submitted,
+					Quantity: token.Quantity,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find asset mints: %w", err)
+	}
+	return mints, nil
+}
+
+// Pending returns the unconfirmed records submitted after since
+func (s *Store) Pending(since time.Time) ([]Record, error) {
+	var records []Record
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket(bucketTxs).Cursor()
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			var record Record
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+			if record.Submitted.Before(since) {
+				return nil
+			}
+			if record.Pending() {
+				records = append(records, record)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending txs: %w", err)
+	}
+	return records, nil
+}
+
+// Confirm marks the tx as confirmed in slot.  Pending txs whose outputs are
+// spent by the tx must have been confirmed too and are marked with the same slot.
+func (s *Store) Confirm(id string, slot int32) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		ids, txs := tx.Bucket(bucketIDs), tx.Bucket(bucketTxs)
+
+		pending := []string{id}
+		for len(pending) > 0 {
+			id, pending = pending[0], pending[1:]
+
+			key := ids.Get([]byte(id))
+			if key == nil {
+				continue
+			}
+			var record Record
+			if err := json.Unmarshal(txs.Get(key), &record); err != nil {
+				return err
+			}
+			if !record.Pending() {
+				continue
+			}
+
+			record.Slot = slot
+			if err := put(txs, key, record); err != nil {
+				return err
+			}
+			for _, input := range record.Inputs {
+				pending = append(pending, strings.SplitN(input, "#", 2)[0])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to confirm tx, %v: %w", id, err)
+	}
+	return nil
+}
+
+// Watch confirms pending txs each interval until ctx is done
+func (s *Store) Watch(ctx context.Context, chain Chain, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var slot int32 // slot holds the tip as of the last check
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		tip, err := s.confirmPending(chain, slot)
+		if err != nil {
+			zapctx.FromContext(ctx).Info("failed to confirm txs", zap.Error(err))
+			continue
+		}
+		slot = tip
+	}
+}
+
+// confirmPending confirms pending txs whose outputs appear in the utxo set.
+// The chain is only queried when there are pending txs and the tip has
+// advanced past slot.  Returns the slot checked.
+func (s *Store) confirmPending(chain Chain, slot int32) (int32, error) {
+	records, err := s.Pending(time.Now().Add(-pendingTTL))
+	if err != nil || len(records) == 0 {
+		return slot, err
+	}
+
+	tip, err := chain.QueryTip()
+	if err != nil {
+		return slot, err
+	}
+	if tip.Slot == slot {
+		return slot, nil
+	}
+
+	for _, record := range records {
+		confirmed, err := outputsFound(chain, record)
+		if err != nil {
+			return slot, err
+		}
+		if !confirmed {
+			continue
+		}
+		if err := s.Confirm(record.ID, tip.Slot); err != nil {
+			return slot, err
+		}
+	}
+	return tip.Slot, nil
+}
+
+// outputsFound returns true if any output of the record is in the utxo set
+func outputsFound(chain Chain, record Record) (bool, error) {
+	seen := map[string]struct{}{}
+	for _, address := range record.Outputs {
+		if _, ok := seen[address]; ok {
+			continue
+		}
+		seen[address] = struct{}{}
+
+		utxos, err := chain.Utxos(address, cardano.ExcludeTx(record.ID))
+		if err != nil {
+			return false, err
+		}
+		if len(utxos) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r Record) matches(query Query) bool {
+	if query.Kind != "" && r.Kind != query.Kind {
+		return false
+	}
+	if query.Wallet == "" {
+		return true
+	}
+	for _, wallet := range r.Wallets {
+		if wallet == query.Wallet {
+			return true
+		}
+	}
+	return false
+}
+
+// inputAddress returns the address of the output spent by input, hash#index,
+// when the output was created by a recorded tx
+func inputAddress(tx *bbolt.Tx, input string) (string, bool) {
+	parts := strings.SplitN(input, "#", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+	index, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", false
+	}
+
+	key := tx.Bucket(bucketIDs).Get([]byte(parts[0]))
+	if key == nil {
+		return "", false
+	}
+	var record Record
+	if err := json.Unmarshal(tx.Bucket(bucketTxs).Get(key), &record); err != nil {
+		return "", false
+	}
+	if index < 0 || index >= len(record.Outputs) {
+		return "", false
+	}
+	return record.Outputs[index], true
+}
+
+func put(bucket *bbolt.Bucket, key []byte, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
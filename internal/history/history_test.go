@@ -0,0 +1,169 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/tj/assert"
+)
+
+func tempStore(t *testing.T) *Store {
+	dir, err := ioutil.TempDir("", "history")
+	assert.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	store, err := Open(dir)
+	assert.Nil(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+func submitted(id string, kind cardano.TxKind, inputs []string, outputs ...string) cardano.SubmittedTx {
+	tx := cardano.SubmittedTx{
+		Tx:        cardano.Tx{ID: id, Inputs: inputs},
+		Kind:      kind,
+		Signed:    []byte("{}"),
+		Submitted: time.Now(),
+	}
+	for _, address := range outputs {
+		tx.Outputs = append(tx.Outputs, cardano.TxOutput{Address: address})
+	}
+	return tx
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := tempStore(t)
+
+	assert.Nil(t, store.RecordTx(ctx, submitted("a", cardano.TxKindFund, []string{"genesis#0"}, "treasury", "alice")))
+	assert.Nil(t, store.RecordTx(ctx, submitted("b", cardano.TxKindSend, []string{"a#1"}, "bob")))
+	assert.Nil(t, store.RecordTx(ctx, submitted("c", cardano.TxKindFund, []string{"a#0"}, "treasury", "bob")))
+
+	t.Run("inputs", func(t *testing.T) {
+		records, _, err := store.Find(Query{Wallet: "alice"})
+		assert.Nil(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, "b", records[0].ID) // spends alice's output
+		assert.Equal(t, "a", records[1].ID)
+	})
+
+	t.Run("kind", func(t *testing.T) {
+		records, _, err := store.Find(Query{Kind: cardano.TxKindFund})
+		assert.Nil(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, "c", records[0].ID)
+		assert.Equal(t, "a", records[1].ID)
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		var ids []string
+		var after string
+		for {
+			records, hasNext, err := store.Find(Query{First: 2, After: after})
+			assert.Nil(t, err)
+			for _, record := range records {
+				ids = append(ids, record.ID)
+			}
+			if !hasNext {
+				break
+			}
+			after = records[len(records)-1].ID
+		}
+		assert.Equal(t, []string{"c", "b", "a"}, ids)
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		assert.Nil(t, store.Confirm("b", 42))
+
+		record, ok, err := store.Get("b")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.EqualValues(t, 42, record.Slot)
+
+		pending, err := store.Pending(time.Now().Add(-time.Minute))
+		assert.Nil(t, err)
+		assert.Len(t, pending, 1)
+		assert.Equal(t, "c", pending[0].ID) // a confirmed as b spends its output
+	})
+}
+
+func TestStore_AssetMints(t *testing.T) {
+	ctx := context.Background()
+	store := tempStore(t)
+
+	asset := &cardano.Asset{PolicyId: "cafe", AssetName: "544f4b"}
+	mint := submitted("a", cardano.TxKindMint, nil, "alice")
+	mint.Mint = []cardano.Token{{Asset: asset, Quantity: "100"}}
+	burn := submitted("b", cardano.TxKindBurn, []string{"a#0"}, "alice")
+	burn.Mint = []cardano.Token{{Asset: asset, Quantity: "-40"}}
+
+	assert.Nil(t, store.RecordTx(ctx, mint))
+	assert.Nil(t, store.RecordTx(ctx, submitted("c", cardano.TxKindSend, nil, "bob")))
+	assert.Nil(t, store.RecordTx(ctx, burn))
+
+	mints, err := store.AssetMints(ctx, "cafe", "544f4b")
+	assert.Nil(t, err)
+	assert.Len(t, mints, 2)
+	assert.Equal(t, "b", mints[0].TxID)
+	assert.Equal(t, "-40", mints[0].Quantity)
+	assert.Equal(t, "a", mints[1].TxID)
+
+	mints, err = store.AssetMints(ctx, "cafe", "00")
+	assert.Nil(t, err)
+	assert.Len(t, mints, 0)
+}
+
+type MockChain struct {
+	slot  int32
+	utxos map[string]cardano.Utxos
+}
+
+func (c MockChain) QueryTip() (*cardano.Tip, error) {
+	return &cardano.Tip{Slot: c.slot}, nil
+}
+
+func (c MockChain) Utxos(address string, excludes ...func(cardano.Utxo) bool) (cardano.Utxos, error) {
+	var utxos cardano.Utxos
+loop:
+	for _, utxo := range c.utxos[address] {
+		for _, exclude := range excludes {
+			if exclude(utxo) {
+				continue loop
+			}
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
+func TestConfirmPending(t *testing.T) {
+	ctx := context.Background()
+	store := tempStore(t)
+
+	assert.Nil(t, store.RecordTx(ctx, submitted("a", cardano.TxKindFund, nil, "treasury", "alice")))
+	assert.Nil(t, store.RecordTx(ctx, submitted("b", cardano.TxKindFund, nil, "treasury", "bob")))
+
+	chain := MockChain{
+		slot: 100,
+		utxos: map[string]cardano.Utxos{
+			"alice": {{Address: "a", Index: 1}},
+		},
+	}
+	slot, err := store.confirmPending(chain, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 100, slot)
+
+	records, _, err := store.Find(Query{})
+	assert.Nil(t, err)
+	got := map[string]int32{}
+	for _, record := range records {
+		got[record.ID] = record.Slot
+	}
+	assert.Equal(t, map[string]int32{"a": 100, "b": 0}, got, fmt.Sprint(records))
+}
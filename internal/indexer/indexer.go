@@ -0,0 +1,736 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package indexer maintains an on-disk index of the chain so address history,
+// blocks, and asset queries can be served without db-sync.  Rather than speak
+// the node's chain-sync protocol, the indexer polls the tip and, each time it
+// changes, diffs the whole utxo set against the previous one.  Txs are found
+// via the outputs they create and are attributed to the block at the tip when
+// first observed.  Inputs spent, fees, mints, and metadata are exact for txs
+// submitted by the toolkit and inferred, where possible, for all others.
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/history"
+	"github.com/savaki/zapctx"
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+const (
+	filename = "index.db" // filename of the bbolt database within the index dir
+
+	// DefaultInterval is how often the tip is checked for new blocks
+	DefaultInterval = 5 * time.Second
+
+	// DefaultFirst is the default page size
+	DefaultFirst = 20
+)
+
+var (
+	bucketMeta      = []byte("meta")      // bucketMeta holds the tip the index was last synced to
+	bucketUtxos     = []byte("utxos")     // bucketUtxos holds the utxo set keyed by hash#index
+	bucketTxs       = []byte("txs")       // bucketTxs holds entries keyed by tx id
+	bucketBlocks    = []byte("blocks")    // bucketBlocks holds blocks keyed by number
+	bucketHashes    = []byte("hashes")    // bucketHashes holds the number of each block hash
+	bucketBlockTxs  = []byte("block_txs") // bucketBlockTxs holds tx ids keyed by block number and seq
+	bucketAddresses = []byte("addresses") // bucketAddresses holds tx ids keyed by address and seq
+	bucketMints     = []byte("mints")     // bucketMints holds mints keyed by asset id and seq
+	keyTip          = []byte("tip")
+)
+
+// Chain provides the views of the chain the indexer diffs
+type Chain interface {
+	QueryTip() (*cardano.Tip, error)
+	UtxoSet() (cardano.Utxos, error)
+}
+
+// Known optionally provides the txs submitted by the toolkit e.g. *history.Store
+type Known interface {
+	Get(id string) (history.Record, bool, error)
+}
+
+// entry holds an indexed tx along with its sequence, which orders txs
+type entry struct {
+	Seq uint64   `json:"seq"`
+	Tx  chain.Tx `json:"tx"`
+}
+
+// Indexer indexes the chain
+type Indexer struct {
+	db       *bbolt.DB
+	chain    Chain
+	known    Known
+	interval time.Duration
+}
+
+var _ chain.Provider = (*Indexer)(nil)
+
+// Option customizes the indexer
+type Option func(*Indexer)
+
+// Interval sets how often the tip is checked for new blocks
+func Interval(d time.Duration) Option {
+	return func(i *Indexer) {
+		if d > 0 {
+			i.interval = d
+		}
+	}
+}
+
+// History allows the indexer to use the bodies of txs submitted by the toolkit
+func History(known Known) Option {
+	return func(i *Indexer) {
+		i.known = known
+	}
+}
+
+// Open returns an indexer backed by a database within dir
+func Open(dir string, c Chain, opts ...Option) (*Indexer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to open index: %w", err)
+	}
+
+	db, err := bbolt.Open(filepath.Join(dir, filename), 0644, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{bucketMeta, bucketUtxos, bucketTxs, bucketBlocks, bucketHashes, bucketBlockTxs, bucketAddresses, bucketMints} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open index: %w", err)
+	}
+
+	indexer := &Indexer{
+		db:       db,
+		chain:    c,
+		interval: DefaultInterval,
+	}
+	for _, opt := range opts {
+		opt(indexer)
+	}
+	return indexer, nil
+}
+
+// Close the underlying database
+func (i *Indexer) Close() error {
+	return i.db.Close()
+}
+
+// Run syncs the index each interval until ctx is done
+func (i *Indexer) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := i.Sync(ctx); err != nil {
+			zapctx.FromContext(ctx).Info("failed to sync index", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync indexes the changes to the utxo set since the last sync.  The first sync
+// records the utxo set as a baseline; history starts from that point.
+func (i *Indexer) Sync(ctx context.Context) (err error) {
+	var tip *cardano.Tip
+	defer func(begin time.Time) {
+		if tip == nil || err != nil {
+			return
+		}
+		zapctx.FromContext(ctx).Debug("synced index",
+			zap.Int32("block", tip.Block),
+			zap.Duration("elapsed", time.Now().Sub(begin).Round(time.Millisecond)),
+		)
+	}(time.Now())
+
+	tip, err = i.chain.QueryTip()
+	if err != nil {
+		return fmt.Errorf("failed to sync index: %w", err)
+	}
+
+	var synced bool
+	err = i.db.View(func(tx *bbolt.Tx) error {
+		synced = bytes.Equal(tx.Bucket(bucketMeta).Get(keyTip), []byte(tip.Hash))
+		return nil
+	})
+	if err != nil || synced {
+		return err
+	}
+
+	utxos, err := i.chain.UtxoSet()
+	if err != nil {
+		return fmt.Errorf("failed to sync index: %w", err)
+	}
+
+	if err := i.db.Update(func(tx *bbolt.Tx) error { return i.apply(tx, tip, utxos, time.Now().UTC()) }); err != nil {
+		return fmt.Errorf("failed to sync index to block, %v: %w", tip.Block, err)
+	}
+	return nil
+}
+
+// apply diffs utxos against the indexed utxo set, recording the txs that
+// created new utxos in the block at tip
+func (i *Indexer) apply(tx *bbolt.Tx, tip *cardano.Tip, utxos cardano.Utxos, now time.Time) error {
+	var (
+		bucket   = tx.Bucket(bucketUtxos)
+		baseline = tx.Bucket(bucketMeta).Get(keyTip) == nil
+		created  = map[string]cardano.Utxo{}
+		spent    = map[string]cardano.Utxo{}
+	)
+	for _, utxo := range utxos {
+		created[utxo.TxIn()] = utxo
+	}
+	err := bucket.ForEach(func(k, v []byte) error {
+		if _, ok := created[string(k)]; ok {
+			delete(created, string(k))
+			return nil
+		}
+		var utxo cardano.Utxo
+		if err := json.Unmarshal(v, &utxo); err != nil {
+			return err
+		}
+		spent[string(k)] = utxo
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !baseline {
+		if err := i.record(tx, tip, created, spent, now); err != nil {
+			return err
+		}
+	}
+
+	for key := range spent {
+		if err := bucket.Delete([]byte(key)); err != nil {
+			return err
+		}
+	}
+	for key, utxo := range created {
+		if err := putJSON(bucket, []byte(key), utxo); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(bucketMeta).Put(keyTip, []byte(tip.Hash))
+}
+
+// record indexes the block at tip along with the txs that created utxos
+func (i *Indexer) record(tx *bbolt.Tx, tip *cardano.Tip, created, spent map[string]cardano.Utxo, now time.Time) error {
+	txs := map[string]*chain.Tx{}
+	for _, utxo := range created {
+		if _, ok := txs[utxo.Address]; ok {
+			continue
+		}
+		if tx.Bucket(bucketTxs).Get([]byte(utxo.Address)) != nil {
+			continue // already indexed e.g. an output restored by a rollback
+		}
+		txs[utxo.Address] = &chain.Tx{
+			ID:        utxo.Address,
+			BlockHash: tip.Hash,
+			Block:     tip.Block,
+			Slot:      tip.Slot,
+			Epoch:     tip.Epoch,
+			Time:      now,
+		}
+	}
+
+	var ids []string
+	for id := range txs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// complete txs submitted by the toolkit from their bodies; otherwise only
+	// the outputs still unspent are known
+	bodies := map[string]cardano.Tx{}
+	for _, id := range ids {
+		item := txs[id]
+		if body, ok := i.knownTx(id); ok {
+			bodies[id] = body
+			item.Fee = body.Fee
+			item.Outputs = body.Outputs
+			if len(body.Metadata) > 0 {
+				if data, err := body.Metadata.JSON(cardano.MetadataNoSchema); err == nil {
+					item.Metadata = data
+				}
+			}
+			for _, input := range body.Inputs {
+				item.Inputs = append(item.Inputs, makeInput(input, spent[input]))
+			}
+			continue
+		}
+
+		for _, utxo := range created {
+			if utxo.Address != id {
+				continue
+			}
+			for len(item.Outputs) <= int(utxo.Index) {
+				item.Outputs = append(item.Outputs, cardano.TxOutput{})
+			}
+			item.Outputs[utxo.Index] = cardano.TxOutput{
+				Address: utxo.Owner,
+				Value:   utxo.Value,
+				Tokens:  utxo.Tokens,
+			}
+		}
+	}
+
+	// when a single unknown tx landed, the spent inputs must be its
+	if len(ids) == 1 && len(bodies) == 0 {
+		item := txs[ids[0]]
+		var keys []string
+		for key := range spent {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			item.Inputs = append(item.Inputs, makeInput(key, spent[key]))
+		}
+	}
+
+	for _, id := range ids {
+		if err := i.putTx(tx, txs[id]); err != nil {
+			return err
+		}
+	}
+	if err := i.recordMints(tx, tip, ids, bodies, created, spent, now); err != nil {
+		return err
+	}
+
+	block := chain.Block{
+		Hash:    tip.Hash,
+		Number:  tip.Block,
+		Slot:    tip.Slot,
+		Epoch:   tip.Epoch,
+		Time:    now,
+		TxCount: int32(len(ids)),
+	}
+	if err := putJSON(tx.Bucket(bucketBlocks), blockKey(tip.Block), block); err != nil {
+		return err
+	}
+	return tx.Bucket(bucketHashes).Put([]byte(tip.Hash), blockKey(tip.Block))
+}
+
+// recordMints records the change in the supply of each asset.  Mints of txs
+// submitted by the toolkit are exact; any remaining change is attributed to
+// the tx that landed when only one did and is otherwise recorded without a tx.
+func (i *Indexer) recordMints(tx *bbolt.Tx, tip *cardano.Tip, ids []string, bodies map[string]cardano.Tx, created, spent map[string]cardano.Utxo, now time.Time) error {
+	deltas := map[string]*big.Int{}
+	for _, utxo := range created {
+		for _, token := range utxo.Tokens {
+			if quantity, ok := big.NewInt(0).SetString(token.Quantity, 10); ok {
+				addTo(deltas, token.Asset.ID(), quantity)
+			}
+		}
+	}
+	for _, utxo := range spent {
+		for _, token := range utxo.Tokens {
+			if quantity, ok := big.NewInt(0).SetString(token.Quantity, 10); ok {
+				addTo(deltas, token.Asset.ID(), quantity.Neg(quantity))
+			}
+		}
+	}
+
+	put := func(assetID, txID string, quantity *big.Int) error {
+		mints := tx.Bucket(bucketMints)
+		seq, err := mints.NextSequence()
+		if err != nil {
+			return err
+		}
+		mint := chain.Mint{TxID: txID, Slot: tip.Slot, Time: now, Quantity: quantity.String()}
+		return putJSON(mints, prefixKey(assetID, seq), mint)
+	}
+
+	minted := map[string]*big.Int{}
+	for _, id := range ids {
+		body, ok := bodies[id]
+		if !ok {
+			continue
+		}
+		for _, token := range body.Mint {
+			quantity, ok := big.NewInt(0).SetString(token.Quantity, 10)
+			if !ok {
+				continue
+			}
+			addTo(minted, token.Asset.ID(), quantity)
+			if err := put(token.Asset.ID(), id, quantity); err != nil {
+				return err
+			}
+		}
+	}
+
+	var assetIDs []string
+	for assetID := range deltas {
+		assetIDs = append(assetIDs, assetID)
+	}
+	sort.Strings(assetIDs)
+
+	for _, assetID := range assetIDs {
+		remain := deltas[assetID]
+		if v, ok := minted[assetID]; ok {
+			remain.Sub(remain, v)
+		}
+		if remain.Sign() == 0 {
+			continue
+		}
+
+		var txID string
+		if len(ids) == 1 && len(bodies) == 0 {
+			txID = ids[0]
+		}
+		if err := put(assetID, txID, remain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putTx saves the tx and indexes it by block and by the addresses involved
+func (i *Indexer) putTx(tx *bbolt.Tx, item *chain.Tx) error {
+	txs := tx.Bucket(bucketTxs)
+	seq, err := txs.NextSequence()
+	if err != nil {
+		return err
+	}
+	if err := putJSON(txs, []byte(item.ID), entry{Seq: seq, Tx: *item}); err != nil {
+		return err
+	}
+
+	key := append(blockKey(item.Block), seqKey(seq)...)
+	if err := tx.Bucket(bucketBlockTxs).Put(key, []byte(item.ID)); err != nil {
+		return err
+	}
+
+	addresses := map[string]struct{}{}
+	for _, output := range item.Outputs {
+		addresses[output.Address] = struct{}{}
+	}
+	for _, input := range item.Inputs {
+		addresses[input.Address] = struct{}{}
+	}
+	delete(addresses, "")
+	for address := range addresses {
+		if err := tx.Bucket(bucketAddresses).Put(prefixKey(address, seq), []byte(item.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// knownTx returns the parsed body of a tx submitted by the toolkit
+func (i *Indexer) knownTx(id string) (cardano.Tx, bool) {
+	if i.known == nil {
+		return cardano.Tx{}, false
+	}
+	record, ok, err := i.known.Get(id)
+	if err != nil || !ok || len(record.Body) == 0 {
+		return cardano.Tx{}, false
+	}
+	tx, err := cardano.ParseTx(record.Body)
+	if err != nil {
+		return cardano.Tx{}, false
+	}
+	return tx, true
+}
+
+// Block returns the indexed block with the hash
+func (i *Indexer) Block(_ context.Context, hash string) (block chain.Block, err error) {
+	err = i.db.View(func(tx *bbolt.Tx) error {
+		key := tx.Bucket(bucketHashes).Get([]byte(hash))
+		if key == nil {
+			return chain.ErrNotFound
+		}
+		return getJSON(tx.Bucket(bucketBlocks), key, &block)
+	})
+	if err != nil {
+		return chain.Block{}, fmt.Errorf("failed to find block, %v: %w", hash, err)
+	}
+	return block, nil
+}
+
+// BlockByNumber returns the indexed block with the number
+func (i *Indexer) BlockByNumber(_ context.Context, number int32) (block chain.Block, err error) {
+	err = i.db.View(func(tx *bbolt.Tx) error {
+		return getJSON(tx.Bucket(bucketBlocks), blockKey(number), &block)
+	})
+	if err != nil {
+		return chain.Block{}, fmt.Errorf("failed to find block, %v: %w", number, err)
+	}
+	return block, nil
+}
+
+// Blocks returns indexed blocks newest first starting after the block with hash after
+func (i *Indexer) Blocks(_ context.Context, first int, after string) (blocks []chain.Block, err error) {
+	err = i.db.View(func(tx *bbolt.Tx) error {
+		var before []byte
+		if after != "" {
+			if before = tx.Bucket(bucketHashes).Get([]byte(after)); before == nil {
+				return fmt.Errorf("unknown cursor, %v", after)
+			}
+		}
+		return reverse(tx.Bucket(bucketBlocks), nil, before, func(_, v []byte) (bool, error) {
+			var block chain.Block
+			if err := json.Unmarshal(v, &block); err != nil {
+				return false, err
+			}
+			blocks = append(blocks, block)
+			return len(blocks) < pageSize(first), nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+// BlockTransactions returns the txs attributed to the block
+func (i *Indexer) BlockTransactions(_ context.Context, hash string) (txs []chain.Tx, err error) {
+	err = i.db.View(func(tx *bbolt.Tx) error {
+		prefix := tx.Bucket(bucketHashes).Get([]byte(hash))
+		if prefix == nil {
+			return nil
+		}
+		cursor := tx.Bucket(bucketBlockTxs).Cursor()
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			var e entry
+			if err := getJSON(tx.Bucket(bucketTxs), v, &e); err != nil {
+				return err
+			}
+			if e.Tx.BlockHash == hash {
+				txs = append(txs, e.Tx)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find txs for block, %v: %w", hash, err)
+	}
+	return txs, nil
+}
+
+// Transaction returns the indexed tx with the id
+func (i *Indexer) Transaction(_ context.Context, id string) (chain.Tx, error) {
+	var e entry
+	err := i.db.View(func(tx *bbolt.Tx) error {
+		return getJSON(tx.Bucket(bucketTxs), []byte(id), &e)
+	})
+	if err != nil {
+		return chain.Tx{}, fmt.Errorf("failed to find tx, %v: %w", id, err)
+	}
+	return e.Tx, nil
+}
+
+// AddressTransactions returns the indexed txs that paid to or spent from the address
+func (i *Indexer) AddressTransactions(_ context.Context, address string, first int, after string) (txs []chain.Tx, err error) {
+	err = i.db.View(func(tx *bbolt.Tx) error {
+		var before []byte
+		if after != "" {
+			var e entry
+			if err := getJSON(tx.Bucket(bucketTxs), []byte(after), &e); err != nil {
+				return fmt.Errorf("unknown cursor, %v: %w", after, err)
+			}
+			before = prefixKey(address, e.Seq)
+		}
+		return reverse(tx.Bucket(bucketAddresses), prefixKey(address), before, func(_, v []byte) (bool, error) {
+			var e entry
+			if err := getJSON(tx.Bucket(bucketTxs), v, &e); err != nil {
+				return false, err
+			}
+			txs = append(txs, e.Tx)
+			return len(txs) < pageSize(first), nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find txs for address, %v: %w", address, err)
+	}
+	return txs, nil
+}
+
+// AssetHolders returns the addresses holding the asset, largest holders first
+func (i *Indexer) AssetHolders(_ context.Context, policyID, assetNameHex string) ([]chain.Holder, error) {
+	var utxos cardano.Utxos
+	err := i.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketUtxos).ForEach(func(_, v []byte) error {
+			var utxo cardano.Utxo
+			if err := json.Unmarshal(v, &utxo); err != nil {
+				return err
+			}
+			if len(utxo.Tokens) > 0 {
+				utxos = append(utxos, utxo)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find asset holders: %w", err)
+	}
+
+	asset := cardano.Asset{PolicyId: policyID, AssetName: assetNameHex}
+	return chain.HoldersOf(utxos, asset.ID()), nil
+}
+
+// AssetMints returns the indexed changes in the supply of the asset, newest first
+func (i *Indexer) AssetMints(_ context.Context, policyID, assetNameHex string) (mints []chain.Mint, err error) {
+	asset := cardano.Asset{PolicyId: policyID, AssetName: assetNameHex}
+	err = i.db.View(func(tx *bbolt.Tx) error {
+		return reverse(tx.Bucket(bucketMints), prefixKey(asset.ID()), nil, func(_, v []byte) (bool, error) {
+			var mint chain.Mint
+			if err := json.Unmarshal(v, &mint); err != nil {
+				return false, err
+			}
+			mints = append(mints, mint)
+			return true, nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find asset mints: %w", err)
+	}
+	return mints, nil
+}
+
+// StakeHistory is not supported as certificates can not be observed in the utxo set
+func (i *Indexer) StakeHistory(_ context.Context, stakeAddress string) ([]chain.StakeEvent, error) {
+	return nil, fmt.Errorf("failed to find stake history, %v: stake history requires db-sync: %w", stakeAddress, chain.ErrUnsupported)
+}
+
+// reverse calls fn for each key with prefix, last first, starting before the
+// key before when set, until fn returns false
+func reverse(bucket *bbolt.Bucket, prefix, before []byte, fn func(k, v []byte) (bool, error)) error {
+	var (
+		cursor = bucket.Cursor()
+		k, v   []byte
+	)
+	seek := before
+	if seek == nil && prefix != nil {
+		seek = append(append([]byte(nil), prefix[:len(prefix)-1]...), prefix[len(prefix)-1]+1)
+	}
+	if seek == nil {
+		k, v = cursor.Last()
+	} else if k, _ = cursor.Seek(seek); k == nil {
+		k, v = cursor.Last()
+	} else {
+		k, v = cursor.Prev()
+	}
+
+	for ; k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Prev() {
+		ok, err := fn(k, v)
+		if err != nil || !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// makeInput returns the input for txIn, hash#index, along with the address and
+// value of the spent utxo, when known
+func makeInput(txIn string, utxo cardano.Utxo) chain.Input {
+	input := chain.Input{Address: utxo.Owner, Value: utxo.Value}
+	if parts := strings.SplitN(txIn, "#", 2); len(parts) == 2 {
+		index, _ := strconv.ParseInt(parts[1], 10, 32)
+		input.TxID, input.Index = parts[0], int32(index)
+	}
+	return input
+}
+
+func addTo(totals map[string]*big.Int, key string, quantity *big.Int) {
+	if total, ok := totals[key]; ok {
+		total.Add(total, quantity)
+		return
+	}
+	totals[key] = big.NewInt(0).Set(quantity)
+}
+
+// blockKey encodes block numbers so keys sort numerically
+func blockKey(number int32) []byte {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, uint32(number))
+	return key
+}
+
+func seqKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+// prefixKey returns the key for seq within the group, or the group prefix when
+// no seq is provided
+func prefixKey(group string, seq ...uint64) []byte {
+	key := append([]byte(group), 0)
+	for _, s := range seq {
+		key = append(key, seqKey(s)...)
+	}
+	return key
+}
+
+func pageSize(first int) int {
+	if first <= 0 {
+		return DefaultFirst
+	}
+	return first
+}
+
+func getJSON(bucket *bbolt.Bucket, key []byte, v interface{}) error {
+	data := bucket.Get(key)
+	if data == nil {
+		return chain.ErrNotFound
+	}
+	return json.Unmarshal(data, v)
+}
+
+func putJSON(bucket *bbolt.Bucket, key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
@@ -0,0 +1,196 @@
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/history"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/tj/assert"
+)
+
+type MockChain struct {
+	tip   cardano.Tip
+	utxos cardano.Utxos
+}
+
+func (c *MockChain) QueryTip() (*cardano.Tip, error) {
+	tip := c.tip
+	return &tip, nil
+}
+
+func (c *MockChain) UtxoSet() (cardano.Utxos, error) {
+	return c.utxos, nil
+}
+
+// advance moves the tip forward one block with the utxo set
+func (c *MockChain) advance(utxos ...cardano.Utxo) {
+	c.tip.Block++
+	c.tip.Slot += 10
+	c.tip.Hash = hex.EncodeToString([]byte{byte(c.tip.Block)})
+	c.utxos = utxos
+}
+
+type MockKnown map[string]history.Record
+
+func (m MockKnown) Get(id string) (history.Record, bool, error) {
+	record, ok := m[id]
+	return record, ok, nil
+}
+
+func tempIndexer(t *testing.T, c Chain, opts ...Option) *Indexer {
+	dir, err := ioutil.TempDir("", "indexer")
+	assert.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	indexer, err := Open(dir, c, opts...)
+	assert.Nil(t, err)
+	t.Cleanup(func() { indexer.Close() })
+
+	return indexer
+}
+
+func TestIndexer(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		asset = &cardano.Asset{PolicyId: "cafe", AssetName: "544f4b"}
+		mock  = &MockChain{}
+	)
+	indexer := tempIndexer(t, mock)
+
+	// baseline
+	genesis := cardano.Utxo{Address: "g", Owner: "treasury", Value: "100"}
+	mock.advance(genesis)
+	assert.Nil(t, indexer.Sync(ctx))
+
+	// a single tx spends the treasury minting tokens to alice
+	mock.advance(
+		cardano.Utxo{Address: "a", Index: 0, Owner: "treasury", Value: "60"},
+		cardano.Utxo{Address: "a", Index: 1, Owner: "alice", Value: "39", Tokens: []cardano.Token{{Asset: asset, Quantity: "100"}}},
+	)
+	assert.Nil(t, indexer.Sync(ctx))
+
+	// alice sends tokens to bob
+	mock.advance(
+		cardano.Utxo{Address: "a", Index: 0, Owner: "treasury", Value: "60"},
+		cardano.Utxo{Address: "b", Index: 0, Owner: "alice", Value: "19", Tokens: []cardano.Token{{Asset: asset, Quantity: "60"}}},
+		cardano.Utxo{Address: "b", Index: 1, Owner: "bob", Value: "19", Tokens: []cardano.Token{{Asset: asset, Quantity: "40"}}},
+	)
+	assert.Nil(t, indexer.Sync(ctx))
+	assert.Nil(t, indexer.Sync(ctx)) // unchanged tip
+
+	t.Run("transaction", func(t *testing.T) {
+		tx, err := indexer.Transaction(ctx, "a")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, tx.Block)
+		assert.Equal(t, []chain.Input{{TxID: "g", Index: 0, Address: "treasury", Value: "100"}}, tx.Inputs)
+		assert.Len(t, tx.Outputs, 2)
+		assert.Equal(t, "alice", tx.Outputs[1].Address)
+
+		_, err = indexer.Transaction(ctx, "g")
+		assert.True(t, errors.Is(err, chain.ErrNotFound))
+	})
+
+	t.Run("address transactions", func(t *testing.T) {
+		txs, err := indexer.AddressTransactions(ctx, "alice", 10, "")
+		assert.Nil(t, err)
+		assert.Len(t, txs, 2)
+		assert.Equal(t, "b", txs[0].ID)
+		assert.Equal(t, "a", txs[1].ID)
+
+		txs, err = indexer.AddressTransactions(ctx, "alice", 10, "b")
+		assert.Nil(t, err)
+		assert.Len(t, txs, 1)
+		assert.Equal(t, "a", txs[0].ID)
+
+		txs, err = indexer.AddressTransactions(ctx, "bob", 10, "")
+		assert.Nil(t, err)
+		assert.Len(t, txs, 1)
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		blocks, err := indexer.Blocks(ctx, 2, "")
+		assert.Nil(t, err)
+		assert.Len(t, blocks, 2)
+		assert.EqualValues(t, 3, blocks[0].Number)
+		assert.EqualValues(t, 1, blocks[0].TxCount)
+
+		blocks, err = indexer.Blocks(ctx, 2, blocks[0].Hash)
+		assert.Nil(t, err)
+		assert.Len(t, blocks, 1)
+		assert.EqualValues(t, 2, blocks[0].Number)
+
+		txs, err := indexer.BlockTransactions(ctx, blocks[0].Hash)
+		assert.Nil(t, err)
+		assert.Len(t, txs, 1)
+		assert.Equal(t, "a", txs[0].ID)
+	})
+
+	t.Run("assets", func(t *testing.T) {
+		holders, err := indexer.AssetHolders(ctx, "cafe", "544f4b")
+		assert.Nil(t, err)
+		assert.Equal(t, []chain.Holder{
+			{Address: "alice", Quantity: "60"},
+			{Address: "bob", Quantity: "40"},
+		}, holders)
+
+		mints, err := indexer.AssetMints(ctx, "cafe", "544f4b")
+		assert.Nil(t, err)
+		assert.Len(t, mints, 1)
+		assert.Equal(t, "a", mints[0].TxID)
+		assert.Equal(t, "100", mints[0].Quantity)
+	})
+}
+
+func TestIndexer_Known(t *testing.T) {
+	ctx := context.Background()
+
+	// a signed tx spending g#0 with a fee of 170000
+	address := append([]byte{0x60}, bytes.Repeat([]byte{0x01}, 28)...)
+	body := map[uint64]interface{}{
+		0: []interface{}{[]interface{}{bytes.Repeat([]byte{0xab}, 32), uint64(0)}},
+		1: []interface{}{[]interface{}{address, uint64(2e6)}},
+		2: uint64(170000),
+	}
+	data, err := cbor.Marshal([]interface{}{body, map[uint64]interface{}{}, nil})
+	assert.Nil(t, err)
+	signed, err := json.Marshal(map[string]string{"cborHex": hex.EncodeToString(data)})
+	assert.Nil(t, err)
+	tx, err := cardano.ParseTx(signed)
+	assert.Nil(t, err)
+
+	genesis := hex.EncodeToString(bytes.Repeat([]byte{0xab}, 32))
+	mock := &MockChain{}
+	indexer := tempIndexer(t, mock, History(MockKnown{tx.ID: {ID: tx.ID, Body: signed}}))
+
+	mock.advance(cardano.Utxo{Address: genesis, Owner: "treasury", Value: "2170000"})
+	assert.Nil(t, indexer.Sync(ctx))
+	mock.advance(
+		cardano.Utxo{Address: tx.ID, Owner: tx.Outputs[0].Address, Value: "2000000"},
+		cardano.Utxo{Address: "other", Owner: "carol", Value: "1"},
+	)
+	assert.Nil(t, indexer.Sync(ctx))
+
+	got, err := indexer.Transaction(ctx, tx.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "170000", got.Fee)
+	assert.Equal(t, []chain.Input{{TxID: genesis, Index: 0, Address: "treasury", Value: "2170000"}}, got.Inputs)
+
+	other, err := indexer.Transaction(ctx, "other")
+	assert.Nil(t, err)
+	assert.Len(t, other.Inputs, 0) // two txs landed; only the known inputs can be attributed
+}
+
+func TestIndexer_StakeHistory(t *testing.T) {
+	indexer := tempIndexer(t, &MockChain{})
+	_, err := indexer.StakeHistory(context.Background(), "stake_test1")
+	assert.True(t, errors.Is(err, chain.ErrUnsupported))
+}
@@ -0,0 +1,310 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package registry provides a local, off-chain token registry modeled after
+// CIP-26.  Entries are stored one file per subject using the same mapping
+// format as the cardano-token-registry so registry files may be imported as is.
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+)
+
+const suffixMapping = ".json" // suffixMapping contains suffix for mapping files
+
+var reSubject = regexp.MustCompile(`^[a-f0-9]{56}([a-f0-9]{2}){0,32}$`)
+
+// Entry holds the off-chain metadata for a single asset
+type Entry struct {
+	Subject     string
+	Name        string
+	Ticker      string
+	Decimals    *int32 // Decimals is nil when not registered
+	Logo        string
+	Url         string
+	Description string
+}
+
+// Subject returns the CIP-26 subject, policy id + hex encoded asset name, for the asset
+func Subject(policyID, assetNameHex string) string {
+	return policyID + strings.ToLower(assetNameHex)
+}
+
+// Registry stores token registry entries under a directory
+type Registry struct {
+	dir     string
+	mutex   sync.RWMutex
+	entries map[string]Entry
+}
+
+// New returns a registry backed by dir loading any existing entries
+func New(dir string) (*Registry, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create registry: %w", err)
+	}
+
+	r := &Registry{
+		dir:     dir,
+		entries: map[string]Entry{},
+	}
+	if _, err := r.load(dir, false); err != nil {
+		return nil, fmt.Errorf("failed to create registry: %w", err)
+	}
+	return r, nil
+}
+
+// Apply returns a copy of the asset with registry metadata, if any, populated
+func (r *Registry) Apply(asset *cardano.Asset) *cardano.Asset {
+	if asset == nil {
+		return nil
+	}
+	entry, ok := r.Lookup(asset.PolicyId, asset.AssetName)
+	if !ok {
+		return asset
+	}
+
+	a := *asset
+	a.Name = entry.Name
+	a.Ticker = entry.Ticker
+	a.Decimals = entry.Decimals
+	a.Logo = entry.Logo
+	a.Url = entry.Url
+	a.Description = entry.Description
+	return &a
+}
+
+// Lookup returns the entry for the specified asset; assetNameHex is hex encoded
+func (r *Registry) Lookup(policyID, assetNameHex string) (Entry, bool) {
+	if r == nil {
+		return Entry{}, false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, ok := r.entries[Subject(policyID, assetNameHex)]
+	return entry, ok
+}
+
+// Register saves the entry replacing any existing entry for the same subject
+func (r *Registry) Register(entry Entry) error {
+	if !reSubject.MatchString(entry.Subject) {
+		return fmt.Errorf("failed to register token: invalid subject, %v", entry.Subject)
+	}
+	if entry.Name == "" {
+		return fmt.Errorf("failed to register token: name required")
+	}
+	if d := entry.Decimals; d != nil && (*d < 0 || *d > 255) {
+		return fmt.Errorf("failed to register token: decimals must be between 0 and 255, %v", *d)
+	}
+
+	data, err := json.MarshalIndent(toMapping(entry), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to register token: %w", err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	filename := filepath.Join(r.dir, entry.Subject+suffixMapping)
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to register token: %w", err)
+	}
+	r.entries[entry.Subject] = entry
+
+	return nil
+}
+
+// Import registers the mappings contained in r.  r may contain either a single
+// registry mapping or an array of mappings.  Returns the number of entries imported.
+func (r *Registry) Import(reader io.Reader) (int, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to import mappings: %w", err)
+	}
+
+	var mappings []mapping
+	if data = bytes.TrimSpace(data); bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &mappings); err != nil {
+			return 0, fmt.Errorf("failed to import mappings: %w", err)
+		}
+	} else {
+		var m mapping
+		if err := json.Unmarshal(data, &m); err != nil {
+			return 0, fmt.Errorf("failed to import mappings: %w", err)
+		}
+		mappings = append(mappings, m)
+	}
+
+	for i, m := range mappings {
+		if err := r.Register(m.entry()); err != nil {
+			return i, fmt.Errorf("failed to import mapping, %v: %w", m.Subject, err)
+		}
+	}
+	return len(mappings), nil
+}
+
+// ImportPath imports a single mapping file or every mapping file within a
+// directory e.g. the mappings directory of the cardano-token-registry
+func (r *Registry) ImportPath(path string) (int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to import mappings: %w", err)
+	}
+	if !info.IsDir() {
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to import mappings: %w", err)
+		}
+		defer f.Close()
+
+		return r.Import(f)
+	}
+
+	return r.load(path, true)
+}
+
+// load reads the mapping files contained in dir.  When persist is true, the
+// entries are registered and saved; otherwise they are only held in memory.
+func (r *Registry) load(dir string, persist bool) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read registry dir, %v: %w", dir, err)
+	}
+
+	var count int
+	for _, item := range entries {
+		if item.IsDir() || !strings.HasSuffix(item.Name(), suffixMapping) {
+			continue
+		}
+
+		filename := filepath.Join(dir, item.Name())
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return count, fmt.Errorf("unable to read mapping, %v: %w", filename, err)
+		}
+
+		var m mapping
+		if err := json.Unmarshal(data, &m); err != nil {
+			return count, fmt.Errorf("unable to decode mapping, %v: %w", filename, err)
+		}
+
+		if persist {
+			if err := r.Register(m.entry()); err != nil {
+				return count, fmt.Errorf("unable to import mapping, %v: %w", filename, err)
+			}
+		} else {
+			r.entries[m.Subject] = m.entry()
+		}
+		count++
+	}
+	return count, nil
+}
+
+// property holds a single CIP-26 property; signatures are preserved on import
+// but never verified
+type property struct {
+	Value          json.RawMessage   `json:"value"`
+	SequenceNumber int               `json:"sequenceNumber"`
+	Signatures     []json.RawMessage `json:"signatures"`
+}
+
+// mapping holds a CIP-26 mapping as found in the cardano-token-registry
+type mapping struct {
+	Subject     string    `json:"subject"`
+	Policy      string    `json:"policy,omitempty"`
+	Name        *property `json:"name,omitempty"`
+	Ticker      *property `json:"ticker,omitempty"`
+	Decimals    *property `json:"decimals,omitempty"`
+	Logo        *property `json:"logo,omitempty"`
+	Url         *property `json:"url,omitempty"`
+	Description *property `json:"description,omitempty"`
+}
+
+func (m mapping) entry() Entry {
+	entry := Entry{
+		Subject:     m.Subject,
+		Name:        m.Name.string(),
+		Ticker:      m.Ticker.string(),
+		Logo:        m.Logo.string(),
+		Url:         m.Url.string(),
+		Description: m.Description.string(),
+	}
+	if m.Decimals != nil {
+		var decimals int32
+		if err := json.Unmarshal(m.Decimals.Value, &decimals); err == nil {
+			entry.Decimals = &decimals
+		}
+	}
+	return entry
+}
+
+func (p *property) string() string {
+	if p == nil {
+		return ""
+	}
+	var s string
+	_ = json.Unmarshal(p.Value, &s)
+	return s
+}
+
+func newProperty(v interface{}) *property {
+	data, _ := json.Marshal(v)
+	return &property{
+		Value:      data,
+		Signatures: []json.RawMessage{},
+	}
+}
+
+func toMapping(entry Entry) mapping {
+	m := mapping{
+		Subject: entry.Subject,
+		Name:    newProperty(entry.Name),
+	}
+	if entry.Ticker != "" {
+		m.Ticker = newProperty(entry.Ticker)
+	}
+	if entry.Decimals != nil {
+		m.Decimals = newProperty(*entry.Decimals)
+	}
+	if entry.Logo != "" {
+		m.Logo = newProperty(entry.Logo)
+	}
+	if entry.Url != "" {
+		m.Url = newProperty(entry.Url)
+	}
+	if entry.Description != "" {
+		m.Description = newProperty(entry.Description)
+	}
+	return m
+}
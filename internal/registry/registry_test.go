@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2021 SundaeSwap Finance
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/tj/assert"
+)
+
+const policyID = "5a3932c9cbe8b7ac58eefde2de45da2091b6df15052042656114c83c"
+
+func TestRegistry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	registry, err := New(dir)
+	assert.Nil(t, err)
+
+	n, err := registry.ImportPath("testdata")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	entry, ok := registry.Lookup(policyID, "74657374")
+	assert.True(t, ok)
+	assert.Equal(t, "Test Token", entry.Name)
+	assert.Equal(t, "TEST", entry.Ticker)
+	assert.EqualValues(t, 6, *entry.Decimals)
+
+	zero := int32(0)
+	err = registry.Register(Entry{
+		Subject:  Subject(policyID, "6f74686572"),
+		Name:     "Other Token",
+		Ticker:   "OTHER",
+		Decimals: &zero,
+	})
+	assert.Nil(t, err)
+
+	// entries survive a restart
+	registry, err = New(dir)
+	assert.Nil(t, err)
+
+	asset := registry.Apply(&cardano.Asset{PolicyId: policyID, AssetName: "6f74686572"})
+	assert.Equal(t, "Other Token", asset.Name)
+	assert.Equal(t, "OTHER", asset.Ticker)
+	assert.NotNil(t, asset.Decimals)
+	assert.EqualValues(t, 0, *asset.Decimals)
+
+	asset = registry.Apply(&cardano.Asset{PolicyId: policyID, AssetName: "756e6b6e6f776e"})
+	assert.Equal(t, "", asset.Name)
+	assert.Nil(t, asset.Decimals)
+
+	err = registry.Register(Entry{Subject: "junk", Name: "Junk"})
+	assert.NotNil(t, err)
+}
+
+func TestRegistry_Import(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	registry, err := New(dir)
+	assert.Nil(t, err)
+
+	mappings := `[
+		{"subject": "` + Subject(policyID, "6f6e65") + `", "name": {"value": "One"}},
+		{"subject": "` + Subject(policyID, "74776f") + `", "name": {"value": "Two"}, "decimals": {"value": 2}}
+	]`
+	n, err := registry.Import(strings.NewReader(mappings))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	entry, ok := registry.Lookup(policyID, "74776f")
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, *entry.Decimals)
+
+	entry, ok = registry.Lookup(policyID, "6f6e65")
+	assert.True(t, ok)
+	assert.Nil(t, entry.Decimals)
+
+	var nilRegistry *Registry
+	_, ok = nilRegistry.Lookup(policyID, "74776f")
+	assert.False(t, ok)
+}
@@ -1,19 +1,32 @@
 package main
 
 import (
+	"context"
 	"embed"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math/big"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/cardano"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/chain"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/dbsync"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/devnet"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/faucet"
 	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/gql"
 	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/gql/graphiql"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/history"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/indexer"
+	"github.com/SundaeSwap-finance/toolkit-for-cardano/internal/registry"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/cors"
 	"github.com/savaki/zapctx"
@@ -32,18 +45,64 @@ var version string
 var dist embed.FS
 
 var opts struct {
-	Assets  string // Assets contains optional directory for static assets
-	Debug   bool   // Debug mode for additional logging
-	Dir     string // Dir to store data in
-	PoolDir string // Dir where the pool keys are found
-	Port    int    // Port to listen on
-	Cardano struct {
+	AdminToken    string        // AdminToken optionally enables admin mutations for requests bearing it
+	Assets        string        // Assets contains optional directory for static assets
+	Debug         bool          // Debug mode for additional logging
+	Dir           string        // Dir to store data in
+	GenesisDir    string        // GenesisDir optionally holds the genesis utxo keys swept into the treasury
+	Indexer       bool          // Indexer enables the embedded chain indexer when db-sync is not configured
+	MintMax       string        // MintMax is the maximum lovelace sent to a single mint recipient
+	Networks      string        // Networks optionally names a json file listing additional backends to serve
+	PoolDir       string        // Dir where the pool keys are found
+	PollInterval  time.Duration // PollInterval is how often the tip is polled for subscriptions
+	Port          int           // Port to listen on
+	TokenRegistry string        // TokenRegistry holds optional token registry mappings to import on start
+	Faucet        struct {
+		Window       time.Duration // Window to collect faucet requests before paying out
+		MaxBatch     int           // MaxBatch is the maximum number of payouts per tx
+		QuotaWindow  time.Duration // QuotaWindow is the rolling window for address and ip quotas
+		AddressQuota string        // AddressQuota is the lovelace an address may receive per QuotaWindow
+		IPQuota      string        // IPQuota is the lovelace a client ip may request per QuotaWindow
+		DailyBudget  string        // DailyBudget is the lovelace the faucet may pay out per UTC day
+		TokenMax     string        // TokenMax is the quantity of any one asset a request may receive
+		TokenQuota   string        // TokenQuota is the quantity of tokens an address or ip may receive per QuotaWindow
+		Wallet       string        // Wallet optionally names the wallet native tokens are dispensed from
+
+		TrustedProxies cli.StringSlice // TrustedProxies lists the proxies whose X-Forwarded-For header is trusted
+	}
+	Devnet struct {
+		AlonzoTemplate string        // AlonzoTemplate optionally holds the alonzo genesis spec
+		BasePort       int           // BasePort is the port of the first node
+		BFTNodes       int           // BFTNodes is the number of BFT nodes
+		EpochLength    int           // EpochLength is the number of slots per epoch
+		Pools          int           // Pools is the number of stake pools
+		SecurityParam  int           // SecurityParam is the security parameter, k
+		SlotLength     time.Duration // SlotLength is the slot length
+		StartDelay     time.Duration // StartDelay is the delay before the chain starts
+		Supply         string        // Supply is the lovelace held by the genesis utxo keys
+		TestnetMagic   string        // TestnetMagic is the network magic of the devnet
+		TreasuryFunds  string        // TreasuryFunds is the lovelace paid to the treasury at genesis
+	}
+	Treasury struct {
+		MaxInputs     int           // MaxInputs is the maximum number of dust utxos consolidated per tx
+		LowWater      string        // LowWater optionally holds the lovelace below which the treasury is low
+		CheckInterval time.Duration // CheckInterval is how often the treasury is checked against LowWater
+	}
+	Postgres dbsync.Config // Postgres optionally holds the db-sync database settings
+	Cardano  struct {
 		CLI              cli.StringSlice // Cardano cli invocation e.g. cardano-cli or ssh hostname cardano-cli
+		HexAssetNames    bool            // HexAssetNames indicates cardano-cli renders and accepts hex asset names
 		SocketPath       string          // SocketPath holds ${CARDANO_NODE_SOCKET_PATH}
-		TestnetMagic     string          // TestnetMagic
+		Network          string          // Network names the network profile e.g. mainnet, preprod, preview, or testnet
+		TestnetMagic     string          // TestnetMagic is the magic of a private testnet
+		Era              string          // Era optionally selects the era commands are built for
+		ByronGenesis     string          // ByronGenesis optionally holds the path to the byron genesis file
+		ShelleyGenesis   string          // ShelleyGenesis optionally holds the path to the shelley genesis file
+		AlonzoGenesis    string          // AlonzoGenesis optionally holds the path to the alonzo genesis file
 		TreasuryAddr     string          // TreasuryAddr is the address of the treasury wallet
 		TreasuryAddrFile string          // TreasuryAddrFile is a file that holds the address of the treasury wallet
 		TreasurySkeyFile string          // TreasurySkeyFile is a pointer to the skey file for the treasury wallet
+		TreasuryFanOut   int             // TreasuryFanOut is the number of utxos the treasury is pre-split into
 	}
 }
 
@@ -52,6 +111,12 @@ func main() {
 	app.Usage = "launch toolkit-for-cardano server"
 	app.Version = strings.TrimSpace(version)
 	app.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "admin-token",
+			Usage:       "optional bearer token that enables admin mutations e.g. treasuryMaintain",
+			EnvVars:     []string{"ADMIN_TOKEN"},
+			Destination: &opts.AdminToken,
+		},
 		&cli.StringFlag{
 			Name:        "assets",
 			Usage:       "optional path to static assets",
@@ -77,13 +142,175 @@ func main() {
 			EnvVars:     []string{"DATA_DIR"},
 			Destination: &opts.Dir,
 		},
+		&cli.DurationFlag{
+			Name:        "faucet-window",
+			Usage:       "time to collect faucet requests before paying them out in a single tx",
+			Value:       faucet.DefaultWindow,
+			EnvVars:     []string{"FAUCET_WINDOW"},
+			Destination: &opts.Faucet.Window,
+		},
+		&cli.IntFlag{
+			Name:        "faucet-max-batch",
+			Usage:       "maximum number of faucet payouts in a single tx",
+			Value:       faucet.DefaultMaxBatch,
+			EnvVars:     []string{"FAUCET_MAX_BATCH"},
+			Destination: &opts.Faucet.MaxBatch,
+		},
+		&cli.DurationFlag{
+			Name:        "faucet-quota-window",
+			Usage:       "rolling window for the per-address and per-ip faucet quotas",
+			Value:       faucet.DefaultQuotaWindow,
+			EnvVars:     []string{"FAUCET_QUOTA_WINDOW"},
+			Destination: &opts.Faucet.QuotaWindow,
+		},
+		&cli.StringFlag{
+			Name:        "faucet-address-quota",
+			Usage:       "lovelace an address may receive per quota window; unlimited if not set",
+			EnvVars:     []string{"FAUCET_ADDRESS_QUOTA"},
+			Destination: &opts.Faucet.AddressQuota,
+		},
+		&cli.StringFlag{
+			Name:        "faucet-ip-quota",
+			Usage:       "lovelace a client ip may request per quota window; unlimited if not set",
+			EnvVars:     []string{"FAUCET_IP_QUOTA"},
+			Destination: &opts.Faucet.IPQuota,
+		},
+		&cli.StringFlag{
+			Name:        "faucet-daily-budget",
+			Usage:       "lovelace the faucet may pay out per UTC day; unlimited if not set",
+			EnvVars:     []string{"FAUCET_DAILY_BUDGET"},
+			Destination: &opts.Faucet.DailyBudget,
+		},
+		&cli.StringFlag{
+			Name:        "faucet-token-max",
+			Usage:       "quantity of any one asset a single walletFund request may receive; unlimited if not set",
+			EnvVars:     []string{"FAUCET_TOKEN_MAX"},
+			Destination: &opts.Faucet.TokenMax,
+		},
+		&cli.StringFlag{
+			Name:        "faucet-token-quota",
+			Usage:       "quantity of tokens an address or client ip may receive per quota window; unlimited if not set",
+			EnvVars:     []string{"FAUCET_TOKEN_QUOTA"},
+			Destination: &opts.Faucet.TokenQuota,
+		},
+		&cli.StringSliceFlag{
+			Name:        "faucet-trusted-proxies",
+			Usage:       "optional ips or cidrs of reverse proxies whose X-Forwarded-For header identifies the client for the faucet ip quota",
+			EnvVars:     []string{"FAUCET_TRUSTED_PROXIES"},
+			Destination: &opts.Faucet.TrustedProxies,
+		},
+		&cli.StringFlag{
+			Name:        "faucet-wallet",
+			Usage:       "wallet holding native tokens to dispense via walletFund; defaults to the treasury",
+			EnvVars:     []string{"FAUCET_WALLET"},
+			Destination: &opts.Faucet.Wallet,
+		},
+		&cli.StringFlag{
+			Name:        "genesis-dir",
+			Usage:       "optional dir holding the genesis utxo-keys to sweep into the treasury",
+			EnvVars:     []string{"GENESIS_DIR"},
+			Destination: &opts.GenesisDir,
+		},
+		&cli.BoolFlag{
+			Name:        "hex-asset-names",
+			Usage:       "cardano-cli renders and accepts hex encoded asset names (1.32+)",
+			EnvVars:     []string{"HEX_ASSET_NAMES"},
+			Destination: &opts.Cardano.HexAssetNames,
+		},
+		&cli.StringFlag{
+			Name:        "mint-max-recipient-lovelace",
+			Usage:       "maximum lovelace a mint recipient may receive",
+			EnvVars:     []string{"MINT_MAX_RECIPIENT_LOVELACE"},
+			Value:       strconv.Itoa(gql.DefaultMaxRecipientLovelace),
+			Destination: &opts.MintMax,
+		},
+		&cli.StringFlag{
+			Name:        "networks",
+			Usage:       "optional json file listing additional networks to serve at /graphql/{name}",
+			EnvVars:     []string{"NETWORKS"},
+			Destination: &opts.Networks,
+		},
+		&cli.StringFlag{
+			Name:        "network",
+			Usage:       "network to target; one of mainnet, preprod, preview, or testnet (uses --testnet-magic)",
+			Value:       cardano.NetworkTestnet,
+			EnvVars:     []string{"NETWORK"},
+			Destination: &opts.Cardano.Network,
+		},
+		&cli.StringFlag{
+			Name:        "era",
+			Usage:       "optional era commands are built for e.g. alonzo; detected from the tip when unset",
+			EnvVars:     []string{"CARDANO_ERA"},
+			Destination: &opts.Cardano.Era,
+		},
+		&cli.StringFlag{
+			Name:        "byron-genesis",
+			Usage:       "optional path to the byron genesis file",
+			EnvVars:     []string{"BYRON_GENESIS"},
+			Destination: &opts.Cardano.ByronGenesis,
+		},
+		&cli.StringFlag{
+			Name:        "shelley-genesis",
+			Usage:       "optional path to the shelley genesis file",
+			EnvVars:     []string{"SHELLEY_GENESIS"},
+			Destination: &opts.Cardano.ShelleyGenesis,
+		},
+		&cli.StringFlag{
+			Name:        "alonzo-genesis",
+			Usage:       "optional path to the alonzo genesis file",
+			EnvVars:     []string{"ALONZO_GENESIS"},
+			Destination: &opts.Cardano.AlonzoGenesis,
+		},
 		&cli.StringFlag{
 			Name:        "pool-dir",
 			Usage:       "path to the node-pool1 directory",
 			EnvVars:     []string{"POOL_DIR"},
-			Required:    true,
 			Destination: &opts.PoolDir,
 		},
+		&cli.StringFlag{
+			Name:        "postgres-host",
+			Usage:       "host of the cardano-db-sync postgres database; enables historical queries",
+			EnvVars:     []string{"POSTGRES_HOST"},
+			Destination: &opts.Postgres.Host,
+		},
+		&cli.StringFlag{
+			Name:        "postgres-port",
+			Usage:       "port of the cardano-db-sync postgres database",
+			Value:       "5432",
+			EnvVars:     []string{"POSTGRES_PORT"},
+			Destination: &opts.Postgres.Port,
+		},
+		&cli.StringFlag{
+			Name:        "postgres-db",
+			Usage:       "name of the cardano-db-sync postgres database",
+			EnvVars:     []string{"POSTGRES_DB"},
+			Destination: &opts.Postgres.DB,
+		},
+		&cli.StringFlag{
+			Name:        "postgres-user",
+			Usage:       "cardano-db-sync postgres user",
+			EnvVars:     []string{"POSTGRES_USER"},
+			Destination: &opts.Postgres.User,
+		},
+		&cli.StringFlag{
+			Name:        "postgres-password",
+			Usage:       "cardano-db-sync postgres password",
+			EnvVars:     []string{"POSTGRES_PASSWORD"},
+			Destination: &opts.Postgres.Password,
+		},
+		&cli.BoolFlag{
+			Name:        "indexer",
+			Usage:       "index the chain into the data dir to serve history queries without db-sync",
+			EnvVars:     []string{"INDEXER"},
+			Destination: &opts.Indexer,
+		},
+		&cli.DurationFlag{
+			Name:        "poll-interval",
+			Usage:       "how often the tip is polled while graphql subscriptions are active",
+			Value:       gql.DefaultPollInterval,
+			EnvVars:     []string{"POLL_INTERVAL"},
+			Destination: &opts.PollInterval,
+		},
 		&cli.IntFlag{
 			Name:        "port",
 			Usage:       "port to listen on",
@@ -95,16 +322,28 @@ func main() {
 			Name:        "socket-path",
 			Usage:       "socket path for cardano node e.g. node.sock",
 			EnvVars:     []string{"CARDANO_NODE_SOCKET_PATH"},
-			Required:    true,
 			Destination: &opts.Cardano.SocketPath,
 		},
 		&cli.StringFlag{
 			Name:        "testnet-magic",
-			Usage:       "testnet-magic value",
+			Usage:       "testnet-magic value when --network is testnet",
 			Value:       "8",
 			EnvVars:     []string{"TESTNET_MAGIC"},
 			Destination: &opts.Cardano.TestnetMagic,
 		},
+		&cli.StringFlag{
+			Name:        "token-registry",
+			Usage:       "optional token registry mapping file or directory to import on start",
+			EnvVars:     []string{"TOKEN_REGISTRY"},
+			Destination: &opts.TokenRegistry,
+		},
+		&cli.IntFlag{
+			Name:        "treasury-fan-out",
+			Usage:       "number of utxos the treasury is pre-split into to support concurrent funding",
+			Value:       50,
+			EnvVars:     []string{"TREASURY_FAN_OUT"},
+			Destination: &opts.Cardano.TreasuryFanOut,
+		},
 		&cli.StringFlag{
 			Name:        "treasury-addr",
 			Usage:       "address with lovelace to fund other addresses from",
@@ -117,14 +356,136 @@ func main() {
 			EnvVars:     []string{"TREASURY_ADDR_FILE"},
 			Destination: &opts.Cardano.TreasuryAddrFile,
 		},
+		&cli.DurationFlag{
+			Name:        "treasury-check-interval",
+			Usage:       "how often the treasury balance is checked against the low-water threshold",
+			EnvVars:     []string{"TREASURY_CHECK_INTERVAL"},
+			Value:       faucet.DefaultCheckInterval,
+			Destination: &opts.Treasury.CheckInterval,
+		},
+		&cli.StringFlag{
+			Name:        "treasury-low-water",
+			Usage:       "optional lovelace below which the treasury is reported low and /health fails",
+			EnvVars:     []string{"TREASURY_LOW_WATER"},
+			Destination: &opts.Treasury.LowWater,
+		},
 		&cli.StringFlag{
 			Name:        "treasury-skey-file",
 			Usage:       "file containing treasury signing key",
 			EnvVars:     []string{"TREASURY_SIGNING_KEY_FILE"},
-			Required:    true,
 			Destination: &opts.Cardano.TreasurySkeyFile,
 		},
 	}
+	app.Commands = []*cli.Command{
+		{
+			Name:  "devnet",
+			Usage: "manage a local devnet",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "init",
+					Usage:     "generate genesis files, keys, node config, topology, and a funded treasury for a fresh local devnet",
+					ArgsUsage: "dir",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:        "alonzo-genesis-template",
+							Usage:       "optional alonzo genesis spec e.g. to provide plutus cost models",
+							Destination: &opts.Devnet.AlonzoTemplate,
+						},
+						&cli.IntFlag{
+							Name:        "base-port",
+							Usage:       "port of the first node; each node listens on the next port",
+							Value:       devnet.DefaultBasePort,
+							Destination: &opts.Devnet.BasePort,
+						},
+						&cli.IntFlag{
+							Name:        "bft-nodes",
+							Usage:       "number of BFT nodes",
+							Value:       devnet.DefaultBFTNodes,
+							Destination: &opts.Devnet.BFTNodes,
+						},
+						&cli.IntFlag{
+							Name:        "epoch-length",
+							Usage:       "number of slots per epoch",
+							Value:       devnet.DefaultEpochLength,
+							Destination: &opts.Devnet.EpochLength,
+						},
+						&cli.StringFlag{
+							Name:        "magic",
+							Usage:       "network magic of the devnet",
+							Value:       devnet.DefaultTestnetMagic,
+							Destination: &opts.Devnet.TestnetMagic,
+						},
+						&cli.IntFlag{
+							Name:        "pools",
+							Usage:       "number of stake pools",
+							Value:       devnet.DefaultPools,
+							Destination: &opts.Devnet.Pools,
+						},
+						&cli.IntFlag{
+							Name:        "security-param",
+							Usage:       "security parameter, k",
+							Value:       devnet.DefaultSecurityParam,
+							Destination: &opts.Devnet.SecurityParam,
+						},
+						&cli.DurationFlag{
+							Name:        "slot-length",
+							Usage:       "slot length",
+							Value:       devnet.DefaultSlotLength,
+							Destination: &opts.Devnet.SlotLength,
+						},
+						&cli.DurationFlag{
+							Name:        "start-delay",
+							Usage:       "delay before the chain starts",
+							Value:       devnet.DefaultStartDelay,
+							Destination: &opts.Devnet.StartDelay,
+						},
+						&cli.StringFlag{
+							Name:        "supply",
+							Usage:       "lovelace held by the genesis utxo keys",
+							Value:       devnet.DefaultSupply,
+							Destination: &opts.Devnet.Supply,
+						},
+						&cli.StringFlag{
+							Name:        "treasury-funds",
+							Usage:       "lovelace paid to the treasury at genesis",
+							Value:       devnet.DefaultTreasuryFunds,
+							Destination: &opts.Devnet.TreasuryFunds,
+						},
+					},
+					Action: devnetInit,
+				},
+			},
+		},
+		{
+			Name:  "treasury",
+			Usage: "maintain the treasury wallet",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "status",
+					Usage:  "report the treasury balance and utxo fragmentation",
+					Action: treasuryStatus,
+				},
+				{
+					Name:      "sweep-genesis",
+					Usage:     "transfer the funds held by genesis utxo keys into the treasury",
+					ArgsUsage: "[genesis-dir]",
+					Action:    treasurySweepGenesis,
+				},
+				{
+					Name:  "consolidate",
+					Usage: "merge dust treasury utxos into a single utxo",
+					Flags: []cli.Flag{
+						&cli.IntFlag{
+							Name:        "max-inputs",
+							Usage:       "maximum number of utxos consolidated per tx; defaults to the treasury fan-out",
+							Destination: &opts.Treasury.MaxInputs,
+						},
+					},
+					Action: treasuryConsolidate,
+				},
+			},
+		},
+	}
 	app.Action = action
 	err := app.Run(os.Args)
 	if err != nil {
@@ -132,48 +493,279 @@ func main() {
 	}
 }
 
-func action(_ *cli.Context) error {
-	dir, err := filepath.Abs(opts.Dir)
+// backend holds the settings of a named cardano backend, served at /graphql/{name}
+type backend struct {
+	Name             string        `json:"name"`
+	CLI              []string      `json:"cardanoCli"`
+	Dir              string        `json:"dir"`
+	PoolDir          string        `json:"poolDir"`
+	SocketPath       string        `json:"socketPath"`
+	Network          string        `json:"network"`
+	TestnetMagic     string        `json:"testnetMagic"`
+	Era              string        `json:"era"`
+	ByronGenesis     string        `json:"byronGenesis"`
+	ShelleyGenesis   string        `json:"shelleyGenesis"`
+	AlonzoGenesis    string        `json:"alonzoGenesis"`
+	HexAssetNames    bool          `json:"hexAssetNames"`
+	TreasuryAddr     string        `json:"treasuryAddr"`
+	TreasuryAddrFile string        `json:"treasuryAddrFile"`
+	TreasurySkeyFile string        `json:"treasurySkeyFile"`
+	TreasuryFanOut   int           `json:"treasuryFanOut"`
+	TreasuryLowWater string        `json:"treasuryLowWater"`
+	FaucetWallet     string        `json:"faucetWallet"`
+	GenesisDir       string        `json:"genesisDir"`
+	TokenRegistry    string        `json:"tokenRegistry"`
+	Indexer          bool          `json:"indexer"`
+	Postgres         dbsync.Config `json:"postgres"`
+}
+
+// defaultBackend returns the backend configured via flags
+func defaultBackend() backend {
+	return backend{
+		Name:             opts.Cardano.Network,
+		CLI:              opts.Cardano.CLI.Value(),
+		Dir:              opts.Dir,
+		PoolDir:          opts.PoolDir,
+		SocketPath:       opts.Cardano.SocketPath,
+		Network:          opts.Cardano.Network,
+		TestnetMagic:     opts.Cardano.TestnetMagic,
+		Era:              opts.Cardano.Era,
+		ByronGenesis:     opts.Cardano.ByronGenesis,
+		ShelleyGenesis:   opts.Cardano.ShelleyGenesis,
+		AlonzoGenesis:    opts.Cardano.AlonzoGenesis,
+		HexAssetNames:    opts.Cardano.HexAssetNames,
+		TreasuryAddr:     opts.Cardano.TreasuryAddr,
+		TreasuryAddrFile: opts.Cardano.TreasuryAddrFile,
+		TreasurySkeyFile: opts.Cardano.TreasurySkeyFile,
+		TreasuryFanOut:   opts.Cardano.TreasuryFanOut,
+		TreasuryLowWater: opts.Treasury.LowWater,
+		FaucetWallet:     opts.Faucet.Wallet,
+		GenesisDir:       opts.GenesisDir,
+		TokenRegistry:    opts.TokenRegistry,
+		Indexer:          opts.Indexer,
+		Postgres:         opts.Postgres,
+	}
+}
+
+// loadBackends reads the additional backends listed in a json networks file
+func loadBackends(filename string) ([]backend, error) {
+	data, err := ioutil.ReadFile(filename)
 	if err != nil {
-		return fmt.Errorf("failed to start toolkit-for-cardano: %w", err)
+		return nil, fmt.Errorf("unable to read networks file: %w", err)
+	}
+
+	var backends []backend
+	if err := json.Unmarshal(data, &backends); err != nil {
+		return nil, fmt.Errorf("unable to parse networks file, %v: %w", filename, err)
+	}
+	for i, b := range backends {
+		if b.Name == "" {
+			return nil, fmt.Errorf("invalid networks file, %v: network %v has no name", filename, i)
+		}
+		if b.Dir == "" {
+			return nil, fmt.Errorf("invalid networks file, %v: network, %v, has no dir", filename, b.Name)
+		}
+		if len(b.CLI) == 0 {
+			backends[i].CLI = []string{"cardano-cli"}
+		}
+		if b.Network == "" {
+			backends[i].Network = cardano.NetworkTestnet
+		}
+		if b.TreasuryFanOut == 0 {
+			backends[i].TreasuryFanOut = opts.Cardano.TreasuryFanOut
+		}
 	}
-	poolDir, err := filepath.Abs(opts.PoolDir)
+	return backends, nil
+}
+
+// newCardanoCLI returns the cardano cli of a backend without opening its stores
+func newCardanoCLI(b backend) (cardano.CLI, error) {
+	dir, err := filepath.Abs(b.Dir)
 	if err != nil {
-		return fmt.Errorf("failed to start toolkit-for-cardano: %w", err)
+		return cardano.CLI{}, err
+	}
+	poolDir, err := filepath.Abs(b.PoolDir)
+	if err != nil {
+		return cardano.CLI{}, err
 	}
 
 	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0755); err != nil {
-		return fmt.Errorf("failed to start toolkit-for-cardano: failed to create tmp dir: %w", err)
+		return cardano.CLI{}, fmt.Errorf("failed to create tmp dir: %w", err)
 	}
 
 	// allow the treasury addr to be either provided or read from file
-	addr := opts.Cardano.TreasuryAddr
+	addr := b.TreasuryAddr
 	if addr == "" {
-		data, err := ioutil.ReadFile(opts.Cardano.TreasuryAddrFile)
+		data, err := ioutil.ReadFile(b.TreasuryAddrFile)
 		if err != nil {
-			return fmt.Errorf("failed to start toolkit-for-cardano: unable to read treasury-addr-file: %w", err)
+			return cardano.CLI{}, fmt.Errorf("unable to read treasury-addr-file: %w", err)
 		}
 		addr = strings.TrimSpace(string(data))
 	}
 
-	cardanoCLI := cardano.CLI{
-		Cmd:              opts.Cardano.CLI.Value(),
+	network, err := cardano.LookupNetwork(b.Network, b.TestnetMagic)
+	if err != nil {
+		return cardano.CLI{}, err
+	}
+	if b.Era != "" {
+		era, err := cardano.LookupEra(b.Era)
+		if err != nil {
+			return cardano.CLI{}, err
+		}
+		network.Era = era.Name
+	}
+	network.ByronGenesis = b.ByronGenesis
+	network.ShelleyGenesis = b.ShelleyGenesis
+	network.AlonzoGenesis = b.AlonzoGenesis
+
+	assetNameFormat := cardano.AssetNameUTF8
+	if b.HexAssetNames {
+		assetNameFormat = cardano.AssetNameHex
+	}
+
+	return cardano.CLI{
+		Cmd:              b.CLI,
 		Dir:              dir,
 		PoolDir:          poolDir,
-		SocketPath:       opts.Cardano.SocketPath,
-		TestnetMagic:     opts.Cardano.TestnetMagic,
+		SocketPath:       b.SocketPath,
+		Network:          network,
 		TreasuryAddr:     addr,
-		TreasurySkeyFile: opts.Cardano.TreasurySkeyFile,
+		TreasurySkeyFile: b.TreasurySkeyFile,
+		Treasury:         cardano.NewTreasuryPool(cardano.FanOut(b.TreasuryFanOut)),
+		Eras:             cardano.NewEraCache(cardano.DefaultEraTTL),
+		FaucetWallet:     b.FaucetWallet,
+		AssetNameFormat:  assetNameFormat,
 		Debug:            opts.Debug,
+	}, nil
+}
+
+// openBackend opens the stores of a backend and starts its background workers.
+// release closes the stores once the server stops.
+func openBackend(logger *zap.Logger, b backend) (config gql.Config, release func(), err error) {
+	var closers []func()
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+	defer func() {
+		if err != nil {
+			closeAll()
+		}
+	}()
+
+	mintMax, ok := big.NewInt(0).SetString(opts.MintMax, 10)
+	if !ok || mintMax.Sign() <= 0 {
+		return gql.Config{}, nil, fmt.Errorf("invalid mint-max-recipient-lovelace, %v", opts.MintMax)
+	}
+
+	cardanoCLI, err := newCardanoCLI(b)
+	if err != nil {
+		return gql.Config{}, nil, err
+	}
+	dir := cardanoCLI.Dir
+
+	txHistory, err := history.Open(filepath.Join(dir, "history"))
+	if err != nil {
+		return gql.Config{}, nil, err
+	}
+	closers = append(closers, func() { txHistory.Close() })
+	cardanoCLI.History = txHistory
+
+	tokenRegistry, err := registry.New(filepath.Join(dir, "registry"))
+	if err != nil {
+		return gql.Config{}, nil, err
+	}
+	if b.TokenRegistry != "" {
+		if _, err := tokenRegistry.ImportPath(b.TokenRegistry); err != nil {
+			return gql.Config{}, nil, fmt.Errorf("unable to import token-registry: %w", err)
+		}
+	}
+
+	limits, err := faucetLimits()
+	if err != nil {
+		return gql.Config{}, nil, err
+	}
+	limiter, err := faucet.NewLimiter(filepath.Join(dir, "faucet"), limits)
+	if err != nil {
+		return gql.Config{}, nil, err
+	}
+
+	var (
+		chainProvider chain.Provider
+		chainIndexer  *indexer.Indexer
+	)
+	switch {
+	case b.Postgres.Enabled():
+		db, err := dbsync.Open(b.Postgres)
+		if err != nil {
+			return gql.Config{}, nil, err
+		}
+		closers = append(closers, func() { db.Close() })
+		chainProvider = db
+	case b.Indexer:
+		chainIndexer, err = indexer.Open(filepath.Join(dir, "index"), cardanoCLI, indexer.History(txHistory))
+		if err != nil {
+			return gql.Config{}, nil, err
+		}
+		closers = append(closers, func() { chainIndexer.Close() })
+		chainProvider = chainIndexer
+	}
+
+	monitor, err := faucet.NewMonitor(cardanoCLI, b.TreasuryLowWater)
+	if err != nil {
+		return gql.Config{}, nil, err
+	}
+
+	ctx := zapctx.NewContext(context.Background(), logger.With(zap.String("network", b.Name)))
+	go txHistory.Watch(ctx, cardanoCLI, history.DefaultWatchInterval)
+	go monitor.Watch(ctx, opts.Treasury.CheckInterval)
+	if chainIndexer != nil {
+		go chainIndexer.Run(ctx)
 	}
-	config := gql.Config{
-		Built:   strings.TrimSpace(built),
-		CLI:     &cardanoCLI,
-		Version: strings.TrimSpace(version),
+
+	return gql.Config{
+		Built:                strings.TrimSpace(built),
+		Chain:                chainProvider,
+		CLI:                  &cardanoCLI,
+		Faucet:               faucet.New(cardanoCLI, faucet.Window(opts.Faucet.Window), faucet.MaxBatch(opts.Faucet.MaxBatch)),
+		GenesisDir:           b.GenesisDir,
+		History:              txHistory,
+		Limiter:              limiter,
+		MaxRecipientLovelace: mintMax,
+		Monitor:              monitor,
+		Network:              cardanoCLI.Network,
+		PollInterval:         opts.PollInterval,
+		Registry:             tokenRegistry,
+		Version:              strings.TrimSpace(version),
+	}, closeAll, nil
+}
+
+// requiredFlag names a flag that must be set by the command being run
+type requiredFlag struct {
+	name  string
+	value string
+}
+
+// requireFlags verifies the flags are set.  flags are validated here rather than
+// marked required so subcommands may run without the flags of the server
+func requireFlags(flags ...requiredFlag) error {
+	for _, flag := range flags {
+		if flag.value == "" {
+			return fmt.Errorf("required flag %q not set", flag.name)
+		}
 	}
-	handler, err := gql.New(config)
+	return nil
+}
+
+func action(_ *cli.Context) error {
+	err := requireFlags(
+		requiredFlag{name: "pool-dir", value: opts.PoolDir},
+		requiredFlag{name: "socket-path", value: opts.Cardano.SocketPath},
+		requiredFlag{name: "treasury-skey-file", value: opts.Cardano.TreasurySkeyFile},
+	)
 	if err != nil {
-		return fmt.Errorf("failed to create server: %w", err)
+		return fmt.Errorf("failed to start toolkit-for-cardano: %w", err)
 	}
 
 	logger, err := zap.NewDevelopment()
@@ -181,13 +773,64 @@ func action(_ *cli.Context) error {
 		return err
 	}
 
+	backends := []backend{defaultBackend()}
+	if opts.Networks != "" {
+		more, err := loadBackends(opts.Networks)
+		if err != nil {
+			return fmt.Errorf("failed to start toolkit-for-cardano: %w", err)
+		}
+		backends = append(backends, more...)
+	}
+
+	var names []string
+	for _, b := range backends {
+		for _, name := range names {
+			if name == b.Name {
+				return fmt.Errorf("failed to start toolkit-for-cardano: duplicate network, %v", name)
+			}
+		}
+		names = append(names, b.Name)
+	}
+
+	var (
+		queries  = map[string]http.Handler{}
+		graphiqs = map[string]http.Handler{}
+		monitors = map[string]*faucet.Monitor{}
+	)
+	for _, b := range backends {
+		config, release, err := openBackend(logger, b)
+		if err != nil {
+			return fmt.Errorf("failed to start toolkit-for-cardano: network, %v: %w", b.Name, err)
+		}
+		defer release()
+
+		config.AdminToken = opts.AdminToken
+		config.Networks = names
+		handler, err := gql.New(config)
+		if err != nil {
+			return fmt.Errorf("failed to create server: %w", err)
+		}
+		queries[b.Name] = handler
+		monitors[b.Name] = config.Monitor
+		graphiqs[b.Name] = withWebsocket(handler, graphiql.New("/graphql/"+b.Name))
+	}
+
+	proxies, err := trustedProxies()
+	if err != nil {
+		return fmt.Errorf("failed to start toolkit-for-cardano: %w", err)
+	}
+
 	router := chi.NewRouter()
 	router.Use(
 		withLogger(logger),
 		withCORS(),
+		withClientIP(proxies),
 	)
-	router.Get("/graphql", graphiql.New("/graphql"))
-	router.Post("/graphql", handler.ServeHTTP)
+	router.Get("/graphql", withNetwork(graphiqs, names[0]))
+	router.Post("/graphql", withNetwork(queries, names[0]))
+	router.Get("/graphql/{network}", withNetwork(graphiqs, names[0]))
+	router.Post("/graphql/{network}", withNetwork(queries, names[0]))
+	router.Get("/health", withHealth(monitors))
 	if opts.Assets != "" {
 		fs := http.FileServer(http.Dir(opts.Assets))
 		router.NotFound(fs.ServeHTTP)
@@ -196,12 +839,206 @@ func action(_ *cli.Context) error {
 		router.NotFound(http.FileServer(fs).ServeHTTP)
 	}
 
-	logger.Info("started server", zap.Int("port", opts.Port))
+	logger.Info("started server", zap.Int("port", opts.Port), zap.Strings("networks", names))
 	defer logger.Info("stopped server")
 
 	return http.ListenAndServe(fmt.Sprintf(":%v", opts.Port), router)
 }
 
+// devnetInit generates a fresh local devnet into the dir named by the first arg
+func devnetInit(c *cli.Context) error {
+	dir := c.Args().First()
+	if dir == "" {
+		return fmt.Errorf("failed to init devnet: dir required")
+	}
+
+	d, err := devnet.Init(c.Context, dir,
+		devnet.Cmd(opts.Cardano.CLI.Value()...),
+		devnet.TestnetMagic(opts.Devnet.TestnetMagic),
+		devnet.BFTNodes(opts.Devnet.BFTNodes),
+		devnet.Pools(opts.Devnet.Pools),
+		devnet.Supply(opts.Devnet.Supply),
+		devnet.TreasuryFunds(opts.Devnet.TreasuryFunds),
+		devnet.SlotLength(opts.Devnet.SlotLength),
+		devnet.EpochLength(opts.Devnet.EpochLength),
+		devnet.SecurityParam(opts.Devnet.SecurityParam),
+		devnet.BasePort(opts.Devnet.BasePort),
+		devnet.StartTime(time.Now().Add(opts.Devnet.StartDelay)),
+		devnet.AlonzoTemplate(opts.Devnet.AlonzoTemplate),
+	)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("generated devnet in %v\n\n", d.Dir)
+	for _, node := range d.Nodes {
+		fmt.Printf("  %-12v %v/run.sh (port %v)\n", node.Name, node.Dir, node.Port)
+	}
+	fmt.Printf("\nstart each node with its run.sh, then start the toolkit with:\n\n")
+	fmt.Printf("  TESTNET_MAGIC=%v \\\n", d.TestnetMagic)
+	fmt.Printf("  CARDANO_NODE_SOCKET_PATH=%v \\\n", filepath.Join(d.Nodes[0].Dir, "node.sock"))
+	fmt.Printf("  TREASURY_ADDR_FILE=%v \\\n", d.TreasuryAddrFile)
+	fmt.Printf("  TREASURY_SIGNING_KEY_FILE=%v \\\n", d.TreasurySkeyFile)
+	fmt.Printf("  POOL_DIR=%v \\\n", d.Nodes[len(d.Nodes)-1].Dir)
+	fmt.Printf("  toolkit-for-cardano\n")
+	return nil
+}
+
+// treasuryCLI returns the cardano cli of the default backend for the treasury
+// subcommands
+func treasuryCLI(flags ...requiredFlag) (cardano.CLI, error) {
+	flags = append(flags, requiredFlag{name: "socket-path", value: opts.Cardano.SocketPath})
+	if err := requireFlags(flags...); err != nil {
+		return cardano.CLI{}, err
+	}
+	return newCardanoCLI(defaultBackend())
+}
+
+// treasuryStatus prints the balance and fragmentation of the treasury
+func treasuryStatus(_ *cli.Context) error {
+	cardanoCLI, err := treasuryCLI()
+	if err != nil {
+		return fmt.Errorf("failed to get treasury status: %w", err)
+	}
+
+	status, err := cardanoCLI.TreasuryStatus()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("address:     %v\n", status.Address)
+	fmt.Printf("lovelace:    %v\n", status.Lovelace)
+	fmt.Printf("utxos:       %v (%v in-flight, %v with tokens)\n", status.Utxos, status.InFlight, status.TokenUtxos)
+	fmt.Printf("fundable:    %v\n", status.Fundable)
+	fmt.Printf("largest:     %v\n", status.Largest)
+	fmt.Printf("dust:        %v utxos holding %v lovelace\n", status.Dust, status.DustLovelace)
+	return nil
+}
+
+// treasurySweepGenesis transfers the funds held by the genesis utxo keys under the
+// dir named by the first arg, or --genesis-dir, into the treasury
+func treasurySweepGenesis(c *cli.Context) error {
+	dir := c.Args().First()
+	if dir == "" {
+		dir = opts.GenesisDir
+	}
+
+	cardanoCLI, err := treasuryCLI(requiredFlag{name: "genesis-dir", value: dir})
+	if err != nil {
+		return fmt.Errorf("failed to sweep genesis funds: %w", err)
+	}
+
+	keys, err := cardanoCLI.GenesisKeys(dir)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		fmt.Printf("  %v %v (%v lovelace)\n", key.SigningKeyFile, key.Address, key.Lovelace)
+	}
+
+	tx, err := cardanoCLI.SweepGenesis(c.Context, dir)
+	if err != nil {
+		return err
+	}
+	if tx.ID == "" {
+		fmt.Println("no genesis funds to sweep")
+		return nil
+	}
+	fmt.Printf("swept genesis funds into %v in tx %v\n", cardanoCLI.TreasuryAddr, tx.ID)
+	return nil
+}
+
+// treasuryConsolidate merges dust treasury utxos into a single utxo
+func treasuryConsolidate(c *cli.Context) error {
+	cardanoCLI, err := treasuryCLI(requiredFlag{name: "treasury-skey-file", value: opts.Cardano.TreasurySkeyFile})
+	if err != nil {
+		return fmt.Errorf("failed to consolidate treasury: %w", err)
+	}
+
+	tx, n, err := cardanoCLI.ConsolidateTreasury(c.Context, opts.Treasury.MaxInputs)
+	if err != nil {
+		return err
+	}
+	if tx.ID == "" {
+		fmt.Println("no dust to consolidate")
+		return nil
+	}
+	fmt.Printf("consolidated %v utxos in tx %v\n", n, tx.ID)
+	return nil
+}
+
+// withNetwork routes requests to the handler of the network named by the path,
+// e.g. /graphql/devnet, or the network query parameter, defaulting to fallback
+func withNetwork(handlers map[string]http.Handler, fallback string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := chi.URLParam(req, "network")
+		if name == "" {
+			name = req.URL.Query().Get("network")
+		}
+		if name == "" {
+			name = fallback
+		}
+
+		handler, ok := handlers[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown network, %v", name), http.StatusNotFound)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	}
+}
+
+// withHealth reports the treasury health of each network as of its last check,
+// responding 503 Service Unavailable when any treasury is low or failed its check
+func withHealth(monitors map[string]*faucet.Monitor) http.HandlerFunc {
+	type network struct {
+		Healthy  bool   `json:"healthy"`
+		Low      bool   `json:"low"`
+		Lovelace string `json:"lovelace,omitempty"`
+		Fundable int    `json:"fundable"`
+		LowWater string `json:"lowWater,omitempty"`
+		Checked  string `json:"checked,omitempty"`
+		Error    string `json:"error,omitempty"`
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		var (
+			healthy  = true
+			networks = map[string]network{}
+		)
+		for name, monitor := range monitors {
+			health := monitor.Health()
+			n := network{
+				Healthy:  health.Healthy(),
+				Low:      health.Low,
+				Lovelace: health.Status.Lovelace,
+				Fundable: health.Status.Fundable,
+				LowWater: health.LowWater,
+			}
+			if !health.Checked.IsZero() {
+				n.Checked = health.Checked.Format(time.RFC3339)
+			}
+			if health.Err != nil {
+				n.Error = health.Err.Error()
+			}
+			healthy = healthy && n.Healthy
+			networks[name] = n
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(struct {
+			Healthy  bool               `json:"healthy"`
+			Networks map[string]network `json:"networks"`
+		}{
+			Healthy:  healthy,
+			Networks: networks,
+		})
+	}
+}
+
 type fileSystemFunc func(name string) (http.File, error)
 
 func (fn fileSystemFunc) Open(name string) (http.File, error) {
@@ -215,6 +1052,109 @@ func withRoot(root string, fs http.FileSystem) fileSystemFunc {
 	}
 }
 
+// faucetLimits returns the faucet quotas configured via flags
+func faucetLimits() (faucet.Limits, error) {
+	limits := faucet.Limits{Window: opts.Faucet.QuotaWindow}
+	for _, item := range []struct {
+		name  string
+		value string
+		limit **big.Int
+	}{
+		{name: "faucet-address-quota", value: opts.Faucet.AddressQuota, limit: &limits.PerAddress},
+		{name: "faucet-ip-quota", value: opts.Faucet.IPQuota, limit: &limits.PerIP},
+		{name: "faucet-daily-budget", value: opts.Faucet.DailyBudget, limit: &limits.DailyBudget},
+		{name: "faucet-token-max", value: opts.Faucet.TokenMax, limit: &limits.TokensPerRequest},
+		{name: "faucet-token-quota", value: opts.Faucet.TokenQuota, limit: &limits.TokenQuota},
+	} {
+		if item.value == "" {
+			continue
+		}
+		v, ok := big.NewInt(0).SetString(item.value, 10)
+		if !ok || v.Sign() < 0 {
+			return faucet.Limits{}, fmt.Errorf("invalid %v, %v", item.name, item.value)
+		}
+		*item.limit = v
+	}
+	return limits, nil
+}
+
+// withWebsocket routes websocket upgrades to the graphql handler and all other
+// requests to next e.g. graphiql
+func withWebsocket(handler, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if gql.IsWebsocket(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		next.ServeHTTP(w, req)
+	}
+}
+
+// trustedProxies parses the ips and cidrs of the trusted reverse proxies
+func trustedProxies() ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, value := range opts.Faucet.TrustedProxies.Value() {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid faucet-trusted-proxies, %v", value)
+			}
+			bits := 8 * len(ip)
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 32
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid faucet-trusted-proxies, %v: %w", value, err)
+		}
+		proxies = append(proxies, ipNet)
+	}
+	return proxies, nil
+}
+
+// withClientIP stores the client ip in the request context for the faucet quotas.
+// The ip is that of the connection; X-Forwarded-For is only honored when the
+// connection comes from a trusted proxy, in which case the right-most address
+// not belonging to a trusted proxy is used
+func withClientIP(proxies []*net.IPNet) func(handler http.Handler) http.Handler {
+	trusted := func(ip string) bool {
+		parsed := net.ParseIP(ip)
+		for _, proxy := range proxies {
+			if parsed != nil && proxy.Contains(parsed) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ip := req.RemoteAddr
+			if host, _, err := net.SplitHostPort(ip); err == nil {
+				ip = host
+			}
+			if trusted(ip) {
+				forwarded := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+				for i := len(forwarded) - 1; i >= 0; i-- {
+					hop := strings.TrimSpace(forwarded[i])
+					if hop == "" {
+						continue
+					}
+					ip = hop
+					if !trusted(hop) {
+						break
+					}
+				}
+			}
+			handler.ServeHTTP(w, req.WithContext(faucet.WithClientIP(req.Context(), ip)))
+		})
+	}
+}
+
 func withCORS() func(next http.Handler) http.Handler {
 	return cors.Handler(cors.Options{
 		AllowedOrigins: []string{"*"},
@@ -1,3 +1,4 @@
+import { NetworkSelect } from "../network/NetworkSelect";
 import { Tip } from "../tip/Tip";
 import React from "react";
 import styled from "styled-components";
@@ -9,6 +10,7 @@ export const Header = () => {
     <StyledHeader>
       <div className="sundae-asset--left" />
       <div className="header__tip">
+        <NetworkSelect />
         <Tip />
       </div>
       <div className="sundae-asset--right" />
@@ -0,0 +1,50 @@
+import React, { useEffect, useState } from "react";
+import styled from "styled-components";
+import { clearNetwork, getNetwork, gqlNetworks, setNetwork } from "../queries";
+
+export const NetworkSelect = () => {
+  const [networks, setNetworks] = useState<string[]>([]);
+
+  useEffect(() => {
+    gqlNetworks()
+      .then((networks) => {
+        // the selected network is no longer served; fall back to the default
+        const selected = getNetwork();
+        if (selected && !networks.includes(selected)) {
+          clearNetwork();
+          window.location.reload();
+          return;
+        }
+        setNetworks(networks);
+      })
+      .catch(() => setNetworks([]));
+  }, []);
+
+  // nothing to switch between
+  if (networks.length < 2) return null;
+
+  const onChange = (e: React.ChangeEvent<HTMLSelectElement>) => {
+    setNetwork(e.target.value);
+    window.location.reload();
+  };
+
+  return (
+    <StyledNetworkSelect value={getNetwork() ?? networks[0]} onChange={onChange}>
+      {networks.map((network) => (
+        <option key={network} value={network}>{network}</option>
+      ))}
+    </StyledNetworkSelect>
+  );
+}
+
+const StyledNetworkSelect = styled.select`
+  margin-right: 12px;
+  padding: 10px 12px;
+  border: none;
+  border-radius: var(--radius-md);
+  background: var(--gradient-card-header);
+  box-shadow: var(--shadow-overview);
+  font-size: 12px;
+  font-weight: 700;
+  color: var(--secondary);
+`;
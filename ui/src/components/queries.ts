@@ -1,12 +1,35 @@
 import { TTip, TUtxo } from "./types";
 
 const API_URL = "/graphql";
+const NETWORK_KEY = "network";
+
+/**
+ * Returns the network selected in the UI, if any. The server default is used otherwise.
+ */
+export const getNetwork = (): string | null => localStorage.getItem(NETWORK_KEY);
+
+/**
+ * Selects the network subsequent requests are sent to.
+ * @param network
+ */
+export const setNetwork = (network: string) => localStorage.setItem(NETWORK_KEY, network);
+
+/**
+ * Clears the selected network so requests are sent to the server default.
+ */
+export const clearNetwork = () => localStorage.removeItem(NETWORK_KEY);
+
+const apiURL = (): string => {
+  const network = getNetwork();
+  return network ? `${API_URL}/${encodeURIComponent(network)}` : API_URL;
+};
 
 const gql = (
   query: string,
   variables: Record<string, any>,
   key: string,
-  debug?: boolean
+  debug?: boolean,
+  url: string = apiURL()
 ): Promise<any> => {
   // Debug option for printing to console
   if (debug) {
@@ -15,7 +38,7 @@ const gql = (
     console.log(JSON.stringify(variables, null, 2));
   }
   // Make request
-  return fetch(API_URL, {
+  return fetch(url, {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify({ query, variables }),
@@ -91,7 +114,7 @@ export const gqlWallets = (): Promise<string[]> => {
       $quantity: String!
     ) {
       walletFund(address: $address, quantity: $quantity) {
-        ok
+        id
       }
     }
     `,
@@ -122,7 +145,7 @@ export const gqlWallets = (): Promise<string[]> => {
       $walletAddress: String!
     ) {
       mint(assetName: $assetName, quantity: $quantity, wallet: $walletAddress) {
-        ok
+        id
       }
     }`,
     {
@@ -168,7 +191,9 @@ export const gqlGetUtxos = ({
         tokens {
           asset {
             assetId
-            assetName
+            assetNameHex
+            assetNameUtf8
+            fingerprint
             #description
             #logo
             #name
@@ -211,3 +236,21 @@ export const gqlTip = (): Promise<TTip> => {
     "tip"
   );
 };
+
+/**
+ * Get the names of the networks served, the default first.  Always asks the
+ * default endpoint so a selected network that is no longer served can be detected
+ */
+export const gqlNetworks = (): Promise<string[]> => {
+  return gql(
+    `
+    query {
+      networks
+    }
+    `,
+    {},
+    "networks",
+    false,
+    API_URL
+  );
+};
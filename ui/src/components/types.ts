@@ -1,6 +1,8 @@
 export type TAsset = {
   assetId: string;
-  assetName: string;
+  assetNameHex: string;
+  assetNameUtf8?: string;
+  fingerprint: string;
   policyId: string;
   description?: string;
   logo?: string;
@@ -34,3 +36,5 @@ export type TTip = {
   hash: string;
   slot: number;
 };
+
+export const assetLabel = (asset: TAsset): string => asset.assetNameUtf8 ?? asset.assetNameHex;
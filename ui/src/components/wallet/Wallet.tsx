@@ -6,6 +6,7 @@ import { Button } from "../styled/Button";
 import Input from "../styled/Input";
 import Select from "../styled/Select";
 import { gqlWallets } from "../queries";
+import { assetLabel, TAsset } from "../types";
 
 const WalletConnected: React.FC = () => {
   const {
@@ -17,6 +18,9 @@ const WalletConnected: React.FC = () => {
   } = useWallet();
   const [walletSection, setWalletSection] = useState<"balance" | "utxos">("balance");
   const [walletTokenFilter, setWalletTokenFilter] = useState("");
+  const walletAssets = walletUtxos
+    .flatMap((utxo) => utxo.tokens)
+    .reduce((acc, token) => ({ ...acc, [token.asset.assetId]: token.asset }), {} as { [assetId: string]: TAsset });
   // RENDER
   return (
     <>
@@ -44,8 +48,8 @@ const WalletConnected: React.FC = () => {
               {Object.entries(walletBalanceAssets ?? {}).map(([assetId, amount]) => (
                 <div key={assetId} className="wallet__asset">
                   <div>
-                    <p>{assetId.split('.')[1]}</p>
-                    <small>{assetId.split('.')[0]}</small>
+                    <p>{walletAssets[assetId] ? assetLabel(walletAssets[assetId]) : assetId.split('.')[1]}</p>
+                    <small>{walletAssets[assetId]?.fingerprint ?? assetId.split('.')[0]}</small>
                   </div>
                   <div>
                     <p>{amount}</p>
@@ -66,14 +70,14 @@ const WalletConnected: React.FC = () => {
             </div>
             <div className="wallet__utxos">
               {walletUtxos
-                .filter((utxo) => !!walletTokenFilter ? utxo.tokens.some((t) => new RegExp(walletTokenFilter, 'i').test(t.asset.assetName)) : true)
+                .filter((utxo) => !!walletTokenFilter ? utxo.tokens.some((t) => new RegExp(walletTokenFilter, 'i').test(assetLabel(t.asset))) : true)
                 .sort((a, b) => Number(b.value) - Number(a.value))
                 .map((utxo) => (
                   <div key={`${utxo.address}-${utxo.index}`} className="wallet__utxo">
                     <p className="wallet__utxo__values">
                       <span>{utxo.value} ₳</span>
                       {utxo.tokens.map((token) => (
-                        <span key={`${token.asset.assetName}${token.quantity}`}>{token.quantity} {token.asset.assetName}</span>
+                        <span key={`${token.asset.assetId}${token.quantity}`}>{token.quantity} {assetLabel(token.asset)}</span>
                       ))}
                     </p>
                     <p className="wallet__utxo__address">